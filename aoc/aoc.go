@@ -0,0 +1,228 @@
+// Package aoc holds the registry of every day's solvers, and the helpers they share for reading puzzle input
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NumParts is the number of parts every day's puzzle has
+const NumParts = 2
+
+// ErrUnknownDay is returned when looking up a day that has no registered solvers
+var ErrUnknownDay = errors.New("unknown day")
+
+// ErrUnknownPart is returned when looking up a part other than 1 or 2
+var ErrUnknownPart = errors.New("unknown part")
+
+// ErrNoAnswer is returned when a puzzle's input has no answer
+var ErrNoAnswer = errors.New("no answer")
+
+// Puzzle holds the functions that solve a day's puzzle. Each day exposes typed versions of these,
+// which are wrapped here so that every day can be looked up and run the same way.
+type Puzzle struct {
+	// Parse reads the puzzle input into the form both parts are solved from
+	Parse func(reader io.Reader) (interface{}, error)
+	// Part1 and Part2 solve each part from the parsed input, which they must not modify
+	Part1 Part
+	Part2 Part
+	// LongRunning holds the parts that can take a long time, which are worth running in the background
+	LongRunning map[int]bool
+}
+
+// Part solves one part of a puzzle. It stops with a *CancelledError once ctx is done, or once it has taken more steps
+// than the budget allows.
+type Part func(ctx context.Context, input interface{}, budget Budget) (interface{}, error)
+
+// registry maps each day to its puzzle
+var registry = map[int]Puzzle{}
+
+// Register adds the puzzle for a day. It is meant to be called from the init function of each day's package.
+func Register(day int, puzzle Puzzle) {
+	if _, exists := registry[day]; exists {
+		panic(fmt.Sprintf("day %d registered twice", day))
+	}
+
+	registry[day] = puzzle
+}
+
+// Lookup gets the puzzle for a day
+func Lookup(day int) (Puzzle, error) {
+	puzzle, ok := registry[day]
+	if !ok {
+		return Puzzle{}, fmt.Errorf("day %d: %w", day, ErrUnknownDay)
+	}
+
+	return puzzle, nil
+}
+
+// Days gets every day that has a registered puzzle, in order
+func Days() []int {
+	days := make([]int, 0, len(registry))
+	for day := range registry {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	return days
+}
+
+// Solve solves a single part of the puzzle from input that has already been parsed
+func (puzzle Puzzle) Solve(ctx context.Context, part int, input interface{}, budget Budget) (interface{}, error) {
+	switch part {
+	case 1:
+		return puzzle.Part1(ctx, input, budget)
+	case 2:
+		return puzzle.Part2(ctx, input, budget)
+	default:
+		return nil, fmt.Errorf("part %d: %w", part, ErrUnknownPart)
+	}
+}
+
+// StdinPath is the path that means the input should be read from stdin
+const StdinPath = "-"
+
+// OpenInput opens the puzzle input at the given path. An empty path, or StdinPath, reads from stdin.
+func OpenInput(path string) (io.ReadCloser, error) {
+	if path == "" || path == StdinPath {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(path)
+}
+
+// InputName gets the name to show for the input at the given path, such as in a diagnostic
+func InputName(path string) string {
+	if path == "" || path == StdinPath {
+		return "<stdin>"
+	}
+
+	return path
+}
+
+// ReadLines reads the input as a list of lines. Windows line endings are accepted, and blank lines at the end of
+// the input are dropped, whether or not the last line ends with a newline.
+func ReadLines(input io.Reader) ([]string, error) {
+	contents, err := ReadString(input)
+	if err != nil {
+		return nil, err
+	} else if contents == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(contents, "\n"), nil
+}
+
+// ReadString reads the input as a single string, with Windows line endings replaced and any trailing blank lines removed
+func ReadString(input io.Reader) (string, error) {
+	contents, err := ioutil.ReadAll(input)
+	if err != nil {
+		return "", err
+	}
+
+	normalized := strings.Replace(string(contents), "\r\n", "\n", -1)
+	lines := strings.Split(normalized, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// Result is the answer to one part of a day's puzzle, along with how long it took to find
+type Result struct {
+	Day    int         `json:"day"`
+	Part   int         `json:"part"`
+	Answer interface{} `json:"answer"`
+	// Duration is how long solving the part took, not counting parsing. It is written as nanoseconds in JSON.
+	Duration time.Duration `json:"duration"`
+	// Metadata holds details about the run that aren't part of the answer, such as where the input came from
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// Run solves the given parts of a day's puzzle using the input at the given path, which is only read once, so it
+// may be stdin. Each answer is written on its own line as soon as it is found. Every part gets the same budget, and
+// stops once ctx is done.
+func Run(ctx context.Context, writer io.Writer, day int, parts []int, path string, budget Budget) error {
+	return RunFunc(ctx, day, parts, path, budget, func(result Result) error {
+		_, err := fmt.Fprintln(writer, result.Answer)
+		return err
+	})
+}
+
+// RunFunc solves the given parts of a day's puzzle like Run, but passes each result to handleResult as soon as it is
+// found, rather than writing it. An error from handleResult stops any remaining parts from being solved.
+func RunFunc(ctx context.Context, day int, parts []int, path string, budget Budget, handleResult func(Result) error) error {
+	puzzle, err := Lookup(day)
+	if err != nil {
+		return err
+	}
+
+	file, err := OpenInput(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	parseStart := time.Now()
+	input, err := puzzle.Parse(file)
+	if err != nil {
+		return err
+	}
+	parseDuration := time.Since(parseStart)
+
+	for _, part := range parts {
+		solveStart := time.Now()
+		answer, err := puzzle.Solve(ctx, part, input, budget)
+		if err != nil {
+			return fmt.Errorf("day %d part %d: %w", day, part, err)
+		}
+
+		err = handleResult(Result{
+			Day:      day,
+			Part:     part,
+			Answer:   answer,
+			Duration: time.Since(solveStart),
+			Metadata: map[string]interface{}{
+				"input":          InputName(path),
+				"parse_duration": parseDuration,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Main runs a day's program, solving every part of its puzzle.
+// The input is read from the file named by the program's argument, or stdin if there is no argument or it is StdinPath.
+func Main(day int) {
+	if len(os.Args) > 2 {
+		fmt.Println("Usage: ./main [in_file]")
+		return
+	}
+
+	path := StdinPath
+	if len(os.Args) == 2 {
+		path = os.Args[1]
+	}
+
+	parts := make([]int, 0, NumParts)
+	for part := 1; part <= NumParts; part++ {
+		parts = append(parts, part)
+	}
+
+	err := Run(context.Background(), os.Stdout, day, parts, path, Budget{})
+	if err != nil {
+		Fatal(InputName(path), err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package aoctest holds helpers for testing each day's solutions
+package aoctest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/bench"
+)
+
+// inputPath is where each day keeps its puzzle input, relative to the day's package
+const inputPath = "input.txt"
+
+var (
+	update      = flag.Bool("update", false, "update the golden answers with the answers for each day's input.txt")
+	partTimeout = flag.Duration("part-timeout", 5*time.Minute, "how long each part may take before its test gives up on it")
+)
+
+// goldenPath gets the path to the stored answer for a part, relative to the day's package
+func goldenPath(part int) string {
+	return filepath.Join("testdata", fmt.Sprintf("part%d.golden", part))
+}
+
+// Golden solves every part of a day's puzzle for its input.txt, and checks the answers against the ones stored in
+// testdata/partN.golden. Running the tests with -update stores the current answers instead. A part that takes longer
+// than -part-timeout fails, saying how far it got, rather than hanging the tests.
+func Golden(t *testing.T, day int) {
+	t.Helper()
+	puzzle, err := aoc.Lookup(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(inputPath)
+	if os.IsNotExist(err) {
+		t.Skipf("day %d has no %s", day, inputPath)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	input, err := puzzle.Parse(file)
+	if err != nil {
+		t.Fatalf("could not parse %s: %s", inputPath, err)
+	}
+
+	for part := 1; part <= aoc.NumParts; part++ {
+		t.Run(fmt.Sprintf("part%d", part), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), *partTimeout)
+			defer cancel()
+			answer, err := puzzle.Solve(ctx, part, input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := fmt.Sprint(answer)
+			if *update {
+				writeGolden(t, part, got)
+				return
+			}
+
+			rawWant, err := ioutil.ReadFile(goldenPath(part))
+			if err != nil {
+				t.Fatalf("could not read golden answer, which can be made with -update: %s", err)
+			}
+
+			want := strings.TrimSuffix(string(rawWant), "\n")
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// Benchmark times parsing a day's input.txt, and solving each part from it, as separate sub-benchmarks
+func Benchmark(b *testing.B, day int) {
+	b.Helper()
+	rawInput, err := ioutil.ReadFile(inputPath)
+	if os.IsNotExist(err) {
+		b.Skipf("day %d has no %s", day, inputPath)
+	} else if err != nil {
+		b.Fatal(err)
+	}
+
+	BenchmarkInput(b, day, string(rawInput))
+}
+
+// BenchmarkInput times parsing the given input, and solving each part from it, as separate sub-benchmarks.
+// It is meant for the days that have no input.txt.
+func BenchmarkInput(b *testing.B, day int, rawInput string) {
+	b.Helper()
+	stages, err := bench.Stages(day, []byte(rawInput))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, stage := range stages {
+		b.Run(stage.Name, stage.Benchmark)
+	}
+}
+
+func writeGolden(t *testing.T, part int, answer string) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(goldenPath(part)), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(goldenPath(part), []byte(answer+"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,205 @@
+// Package bench times how long each day's puzzle takes to parse and solve, and compares the timings between runs
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+)
+
+// ParseStage is the name of the stage that parses the puzzle input
+const ParseStage = "parse"
+
+// Stage is a step in solving a puzzle that can be timed on its own
+type Stage struct {
+	// Name is ParseStage, or partN for each part
+	Name string
+	Run  func() error
+}
+
+// Timing is how long a stage of a day's puzzle took
+type Timing struct {
+	Day         int    `json:"day"`
+	Stage       string `json:"stage"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+}
+
+// Report holds the timings from a run of every day, along with what they were run on
+type Report struct {
+	GoVersion string   `json:"go_version"`
+	GOOS      string   `json:"goos"`
+	GOARCH    string   `json:"goarch"`
+	Timings   []Timing `json:"timings"`
+}
+
+// Regression is a stage that got slower between two reports
+type Regression struct {
+	Day      int
+	Stage    string
+	Baseline time.Duration
+	Current  time.Duration
+}
+
+// Stages gets the stages of solving a day's puzzle for the given input. The input is parsed once up front,
+// so that each part is timed without parsing.
+func Stages(day int, rawInput []byte) ([]Stage, error) {
+	puzzle, err := aoc.Lookup(day)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := puzzle.Parse(bytes.NewReader(rawInput))
+	if err != nil {
+		return nil, err
+	}
+
+	stages := []Stage{
+		{
+			Name: ParseStage,
+			Run: func() error {
+				_, err := puzzle.Parse(bytes.NewReader(rawInput))
+				return err
+			},
+		},
+	}
+	for part := 1; part <= aoc.NumParts; part++ {
+		// Keep a copy of part for the closure
+		part := part
+		stages = append(stages, Stage{
+			Name: fmt.Sprintf("part%d", part),
+			Run: func() error {
+				_, err := puzzle.Solve(context.Background(), part, input, aoc.Budget{})
+				return err
+			},
+		})
+	}
+
+	return stages, nil
+}
+
+// Benchmark runs a stage b.N times, failing the benchmark if it returns an error
+func (stage Stage) Benchmark(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := stage.Run(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Measure times every stage of a day's puzzle for the given input
+func Measure(day int, rawInput []byte) ([]Timing, error) {
+	stages, err := Stages(day, rawInput)
+	if err != nil {
+		return nil, err
+	}
+
+	timings := make([]Timing, 0, len(stages))
+	for _, stage := range stages {
+		// Run the stage once first, as a benchmark that fails doesn't say why
+		if err := stage.Run(); err != nil {
+			return nil, fmt.Errorf("day %d %s: %w", day, stage.Name, err)
+		}
+
+		result := testing.Benchmark(stage.Benchmark)
+		timings = append(timings, Timing{
+			Day:         day,
+			Stage:       stage.Name,
+			Iterations:  result.N,
+			NsPerOp:     result.NsPerOp(),
+			AllocsPerOp: result.AllocsPerOp(),
+			BytesPerOp:  result.AllocedBytesPerOp(),
+		})
+	}
+
+	return timings, nil
+}
+
+// NewReport makes a report of the given timings, recording the machine they were taken on
+func NewReport(timings []Timing) Report {
+	return Report{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		Timings:   timings,
+	}
+}
+
+// ReadReport reads a report that was written with WriteReport
+func ReadReport(path string) (Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Report{}, err
+	}
+	defer file.Close()
+
+	var report Report
+	err = json.NewDecoder(file).Decode(&report)
+	if err != nil {
+		return Report{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return report, nil
+}
+
+// WriteReport writes a report as JSON
+func WriteReport(writer io.Writer, report Report) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+
+	return encoder.Encode(report)
+}
+
+// Compare finds every stage that is more than threshold slower in the current report than in the baseline,
+// where a threshold of 0.1 allows a stage to be 10% slower. Stages only in one of the reports are ignored.
+func Compare(baseline, current Report, threshold float64) []Regression {
+	type key struct {
+		day   int
+		stage string
+	}
+
+	baselineTimings := make(map[key]Timing, len(baseline.Timings))
+	for _, timing := range baseline.Timings {
+		baselineTimings[key{timing.Day, timing.Stage}] = timing
+	}
+
+	var regressions []Regression
+	for _, timing := range current.Timings {
+		baselineTiming, ok := baselineTimings[key{timing.Day, timing.Stage}]
+		if !ok || baselineTiming.NsPerOp == 0 {
+			continue
+		}
+
+		if float64(timing.NsPerOp) > float64(baselineTiming.NsPerOp)*(1+threshold) {
+			regressions = append(regressions, Regression{
+				Day:      timing.Day,
+				Stage:    timing.Stage,
+				Baseline: time.Duration(baselineTiming.NsPerOp),
+				Current:  time.Duration(timing.NsPerOp),
+			})
+		}
+	}
+
+	return regressions
+}
+
+// Change gets how much slower the stage got, as a fraction of the baseline
+func (regression Regression) Change() float64 {
+	return float64(regression.Current-regression.Baseline) / float64(regression.Baseline)
+}
+
+func (regression Regression) String() string {
+	return fmt.Sprintf("day %d %s: %s -> %s (+%.0f%%)", regression.Day, regression.Stage, regression.Baseline, regression.Current,
+		regression.Change()*100)
+}
@@ -0,0 +1,33 @@
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := Report{Timings: []Timing{
+		{Day: 1, Stage: "part1", NsPerOp: 1000},
+		{Day: 1, Stage: "part2", NsPerOp: 1000},
+		{Day: 2, Stage: "part1", NsPerOp: 1000},
+	}}
+	current := Report{Timings: []Timing{
+		// Within the threshold
+		{Day: 1, Stage: "part1", NsPerOp: 1100},
+		{Day: 1, Stage: "part2", NsPerOp: 1500},
+		// Faster
+		{Day: 2, Stage: "part1", NsPerOp: 500},
+		// Not in the baseline
+		{Day: 3, Stage: "part1", NsPerOp: 5000},
+	}}
+
+	regressions := Compare(baseline, current, 0.1)
+	want := Regression{Day: 1, Stage: "part2", Baseline: 1000 * time.Nanosecond, Current: 1500 * time.Nanosecond}
+	if len(regressions) != 1 || regressions[0] != want {
+		t.Fatalf("got %v, want [%v]", regressions, want)
+	}
+
+	if change := regressions[0].Change(); change != 0.5 {
+		t.Errorf("got a change of %f, want 0.5", change)
+	}
+}
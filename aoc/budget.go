@@ -0,0 +1,72 @@
+package aoc
+
+import "context"
+
+// reportInterval is how many steps are taken between each report of a part's progress
+const reportInterval = 1024
+
+// Progress is how far a part has got
+type Progress struct {
+	// Steps is how many steps the part has taken so far, where what a step is depends on the day
+	Steps int `json:"steps"`
+	// Stage describes what the part is working on, such as which attack power is being tried
+	Stage string `json:"stage,omitempty"`
+}
+
+// Budget limits how long a part may run for. The zero Budget has no limit.
+type Budget struct {
+	// MaxSteps is how many steps the part may take before giving up, or 0 for no limit
+	MaxSteps int
+	// Report, if set, is called with the part's progress every so often, and whenever its stage changes
+	Report func(Progress)
+}
+
+// Steps counts the steps a part takes against its budget. Each part takes a step for every pass of its main loop.
+type Steps struct {
+	ctx      context.Context
+	done     <-chan struct{}
+	budget   Budget
+	progress Progress
+}
+
+// Start starts counting the steps of a part, which must stop once ctx is done
+func (budget Budget) Start(ctx context.Context) *Steps {
+	return &Steps{ctx: ctx, done: ctx.Done(), budget: budget}
+}
+
+// Take counts a step, returning a *CancelledError instead if the context is done or the budget has run out
+func (steps *Steps) Take() error {
+	select {
+	case <-steps.done:
+		return &CancelledError{Progress: steps.progress, Err: steps.ctx.Err()}
+	default:
+	}
+
+	if steps.budget.MaxSteps > 0 && steps.progress.Steps >= steps.budget.MaxSteps {
+		return &CancelledError{Progress: steps.progress, Err: ErrOutOfSteps}
+	}
+
+	steps.progress.Steps++
+	if steps.progress.Steps%reportInterval == 0 {
+		steps.report()
+	}
+
+	return nil
+}
+
+// SetStage sets what the part is working on
+func (steps *Steps) SetStage(stage string) {
+	steps.progress.Stage = stage
+	steps.report()
+}
+
+// Progress gets how far the part has got
+func (steps *Steps) Progress() Progress {
+	return steps.progress
+}
+
+func (steps *Steps) report() {
+	if steps.budget.Report != nil {
+		steps.budget.Report(steps.progress)
+	}
+}
@@ -0,0 +1,113 @@
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrMalformedInput is the error held by a ParseError when the input doesn't match what the puzzle describes
+var ErrMalformedInput = errors.New("malformed input")
+
+// ErrOutOfSteps is the error held by a CancelledError when a part takes more steps than its budget allows
+var ErrOutOfSteps = errors.New("out of steps")
+
+// ParseError is an error in a day's puzzle input, along with where in the input it was found
+type ParseError struct {
+	Day int
+	// Line is the line the error is on, starting from 1
+	Line int
+	// Column is the column the error is at, starting from 1. It is 0 if the error is with the line as a whole.
+	Column int
+	// Text is the line the error is on
+	Text string
+	Err  error
+}
+
+// NewParseError makes a ParseError for the given line, which is indexed from 0 like the lines returned by ReadLines.
+// column is also indexed from 0, and may be -1 if the error is with the line as a whole.
+func NewParseError(day int, lineIndex int, column int, text string, err error) *ParseError {
+	return &ParseError{Day: day, Line: lineIndex + 1, Column: column + 1, Text: text, Err: err}
+}
+
+func (parseErr *ParseError) Error() string {
+	if parseErr.Column == 0 {
+		return fmt.Sprintf("day %d: line %d: %s", parseErr.Day, parseErr.Line, parseErr.Err)
+	}
+
+	return fmt.Sprintf("day %d: line %d, column %d: %s", parseErr.Day, parseErr.Line, parseErr.Column, parseErr.Err)
+}
+
+func (parseErr *ParseError) Unwrap() error {
+	return parseErr.Err
+}
+
+// CancelledError is returned by a part that stops before finding its answer, along with how far it got
+type CancelledError struct {
+	Progress Progress
+	// Err is why the part stopped, which is either the error of its context or ErrOutOfSteps
+	Err error
+}
+
+func (cancelledErr *CancelledError) Error() string {
+	if cancelledErr.Progress.Stage == "" {
+		return fmt.Sprintf("stopped after %d steps: %s", cancelledErr.Progress.Steps, cancelledErr.Err)
+	}
+
+	return fmt.Sprintf(
+		"stopped after %d steps, while %s: %s",
+		cancelledErr.Progress.Steps,
+		cancelledErr.Progress.Stage,
+		cancelledErr.Err,
+	)
+}
+
+func (cancelledErr *CancelledError) Unwrap() error {
+	return cancelledErr.Err
+}
+
+// WriteDiagnostic writes the error the way a compiler would, with the offending line and a marker under the column,
+// e.g.
+//
+//	input.txt:3:7: malformed input
+//	    #1 @ 1x3: 4x4
+//	          ^
+func (parseErr *ParseError) WriteDiagnostic(writer io.Writer, filename string) error {
+	location := fmt.Sprintf("%s:%d", filename, parseErr.Line)
+	if parseErr.Column != 0 {
+		location += fmt.Sprintf(":%d", parseErr.Column)
+	}
+
+	_, err := fmt.Fprintf(writer, "%s: %s\n    %s\n", location, parseErr.Err, parseErr.Text)
+	if err != nil || parseErr.Column == 0 {
+		return err
+	}
+
+	// Tabs are kept in the marker's indentation, so it lines up with the column however wide they are shown
+	indentation := []rune(parseErr.Text)
+	if parseErr.Column-1 < len(indentation) {
+		indentation = indentation[:parseErr.Column-1]
+	}
+	for i, char := range indentation {
+		if char != '\t' {
+			indentation[i] = ' '
+		}
+	}
+	_, err = fmt.Fprintf(writer, "    %s^\n", string(indentation))
+
+	return err
+}
+
+// Fatal prints an error that happened while solving the puzzle in the given file, and exits with a non-zero status.
+// A ParseError is printed as a diagnostic pointing at the part of the file that is wrong.
+func Fatal(filename string, err error) {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		parseErr.WriteDiagnostic(os.Stderr, filename)
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(1)
+}
@@ -0,0 +1,351 @@
+// Package server serves every day's solvers over HTTP, so that they can be used without Go.
+// Parts that take a long time run as jobs, whose progress can be polled and which can be cancelled.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+)
+
+const (
+	// maxInputSize is the largest puzzle input that will be read
+	maxInputSize = 16 << 20
+	// finishedJobTTL is how long a job is kept for after it finishes, for its result to be fetched
+	finishedJobTTL = 10 * time.Minute
+	inputName      = "request body"
+	// parsingStage is the stage a job is at until its input is parsed
+	parsingStage = "parsing input"
+)
+
+// The states a job can be in
+const (
+	jobRunning   = "running"
+	jobDone      = "done"
+	jobFailed    = "failed"
+	jobCancelled = "cancelled"
+)
+
+var (
+	errUnknownJob       = errors.New("unknown job")
+	errNotFound         = errors.New("not found")
+	errMethodNotAllowed = errors.New("method not allowed")
+	errTooManyJobs      = errors.New("too many jobs are running")
+)
+
+// Server is an http.Handler that serves the solvers
+type Server struct {
+	mutex  sync.Mutex
+	jobs   map[string]*job
+	lastID int
+	// maxRunningJobs is how many jobs may run at once, counting cancelled jobs until they stop
+	maxRunningJobs int
+}
+
+// job is a part that is being solved in the background
+type job struct {
+	status jobStatus
+	cancel context.CancelFunc
+	// finished is when the job stopped running, or the zero time if it is still running
+	finished time.Time
+}
+
+// jobStatus is what is written in response to a request about a job. Line and Column are set if the job failed
+// because of an error in the puzzle input.
+type jobStatus struct {
+	ID       string       `json:"id"`
+	Day      int          `json:"day"`
+	Part     int          `json:"part"`
+	State    string       `json:"state"`
+	Progress aoc.Progress `json:"progress"`
+	Result   *aoc.Result  `json:"result,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Line     int          `json:"line,omitempty"`
+	Column   int          `json:"column,omitempty"`
+}
+
+// errorResponse is what is written in response to a request that fails. Line and Column are set for errors in the
+// puzzle input.
+type errorResponse struct {
+	Error  string `json:"error"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// New makes a server for every registered day, which runs at most maxRunningJobs jobs at once
+func New(maxRunningJobs int) *Server {
+	return &Server{jobs: map[string]*job{}, maxRunningJobs: maxRunningJobs}
+}
+
+// ServeHTTP serves a request:
+//
+//	POST /days/{day}/parts/{part} solves a part using the request body as the puzzle input, writing the result.
+//	Parts that take a long time are started as a job instead, which parses the input too, and the job is written with
+//	a 202 status. If too many jobs are already running, the job is not started, and a 503 status is written.
+//	GET /jobs/{id} gets a job's progress, along with its result once it is done.
+//	DELETE /jobs/{id} cancels a job.
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) == 4 && segments[0] == "days" && segments[2] == "parts":
+		if allowMethod(w, r, http.MethodPost) {
+			server.handleSolve(w, r, segments[1], segments[3])
+		}
+	case len(segments) == 2 && segments[0] == "jobs" && r.Method == http.MethodDelete:
+		server.handleCancelJob(w, segments[1])
+	case len(segments) == 2 && segments[0] == "jobs":
+		if allowMethod(w, r, http.MethodGet, http.MethodDelete) {
+			server.handleGetJob(w, segments[1])
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s: %w", r.URL.Path, errNotFound))
+	}
+}
+
+// allowMethod checks that a request uses one of the given methods, responding with an error if it doesn't
+func allowMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s %s: %w", r.Method, r.URL.Path, errMethodNotAllowed))
+
+	return false
+}
+
+func (server *Server) handleSolve(w http.ResponseWriter, r *http.Request, rawDay string, rawPart string) {
+	day, dayErr := strconv.Atoi(rawDay)
+	part, partErr := strconv.Atoi(rawPart)
+	puzzle, err := aoc.Lookup(day)
+	if dayErr != nil || err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("day %s: %w", rawDay, aoc.ErrUnknownDay))
+		return
+	} else if partErr != nil || part < 1 || part > aoc.NumParts {
+		writeError(w, http.StatusNotFound, fmt.Errorf("part %s: %w", rawPart, aoc.ErrUnknownPart))
+		return
+	}
+
+	reader := http.MaxBytesReader(w, r.Body, maxInputSize)
+	if puzzle.LongRunning[part] {
+		// Parsing can take a while too, so only the body is read before the job starts
+		rawInput, err := io.ReadAll(reader)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		status, err := server.startJob(day, part, puzzle, rawInput)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		w.Header().Set("Location", "/jobs/"+status.ID)
+		writeJSON(w, http.StatusAccepted, status)
+		return
+	}
+
+	parseStart := time.Now()
+	input, err := puzzle.Parse(reader)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	metadata := map[string]interface{}{
+		"input":          inputName,
+		"parse_duration": time.Since(parseStart),
+	}
+
+	solveStart := time.Now()
+	answer, err := puzzle.Solve(r.Context(), part, input, aoc.Budget{})
+	if err != nil {
+		writeError(w, statusForError(err), fmt.Errorf("day %d part %d: %w", day, part, err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, aoc.Result{
+		Day:      day,
+		Part:     part,
+		Answer:   answer,
+		Duration: time.Since(solveStart),
+		Metadata: metadata,
+	})
+}
+
+// startJob starts parsing the input and solving a part in the background, returning the job's status as it starts.
+// errTooManyJobs is returned if the server is already running as many jobs as it may.
+func (server *Server) startJob(day int, part int, puzzle aoc.Puzzle, rawInput []byte) (jobStatus, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.removeFinishedJobs()
+	if server.numRunningJobs() >= server.maxRunningJobs {
+		return jobStatus{}, fmt.Errorf("%w, at most %d may run at once", errTooManyJobs, server.maxRunningJobs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server.lastID++
+	id := strconv.Itoa(server.lastID)
+	server.jobs[id] = &job{
+		status: jobStatus{ID: id, Day: day, Part: part, State: jobRunning, Progress: aoc.Progress{Stage: parsingStage}},
+		cancel: cancel,
+	}
+
+	go func() {
+		defer cancel()
+		result, err := server.runJob(ctx, id, day, part, puzzle, rawInput)
+
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		finishedJob := server.jobs[id]
+		finishedJob.finished = time.Now()
+		var parseErr *aoc.ParseError
+		switch {
+		case finishedJob.status.State == jobCancelled:
+			// The job was cancelled while it was running, so whatever it returned is of no use
+		case errors.As(err, &parseErr):
+			finishedJob.status.State = jobFailed
+			finishedJob.status.Error = err.Error()
+			finishedJob.status.Line = parseErr.Line
+			finishedJob.status.Column = parseErr.Column
+		case err != nil:
+			finishedJob.status.State = jobFailed
+			finishedJob.status.Error = err.Error()
+		default:
+			finishedJob.status.State = jobDone
+			finishedJob.status.Result = &result
+		}
+	}()
+
+	return server.jobs[id].status, nil
+}
+
+// runJob parses the input and solves the part for the job with the given id, reporting its progress to the job
+func (server *Server) runJob(ctx context.Context, id string, day int, part int, puzzle aoc.Puzzle, rawInput []byte) (aoc.Result, error) {
+	parseStart := time.Now()
+	input, err := puzzle.Parse(bytes.NewReader(rawInput))
+	if err != nil {
+		return aoc.Result{}, err
+	} else if err := ctx.Err(); err != nil {
+		return aoc.Result{}, err
+	}
+
+	metadata := map[string]interface{}{
+		"input":          inputName,
+		"parse_duration": time.Since(parseStart),
+	}
+	report := func(progress aoc.Progress) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		server.jobs[id].status.Progress = progress
+	}
+	report(aoc.Progress{})
+
+	solveStart := time.Now()
+	answer, err := puzzle.Solve(ctx, part, input, aoc.Budget{Report: report})
+	if err != nil {
+		return aoc.Result{}, fmt.Errorf("day %d part %d: %w", day, part, err)
+	}
+
+	return aoc.Result{
+		Day:      day,
+		Part:     part,
+		Answer:   answer,
+		Duration: time.Since(solveStart),
+		Metadata: metadata,
+	}, nil
+}
+
+// numRunningJobs counts the jobs that haven't finished yet. The mutex must be held.
+func (server *Server) numRunningJobs() int {
+	numRunning := 0
+	for _, runningJob := range server.jobs {
+		if runningJob.finished.IsZero() {
+			numRunning++
+		}
+	}
+
+	return numRunning
+}
+
+// removeFinishedJobs forgets every job that finished long enough ago. The mutex must be held.
+func (server *Server) removeFinishedJobs() {
+	for id, oldJob := range server.jobs {
+		if !oldJob.finished.IsZero() && time.Since(oldJob.finished) > finishedJobTTL {
+			delete(server.jobs, id)
+		}
+	}
+}
+
+func (server *Server) handleGetJob(w http.ResponseWriter, id string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	foundJob, ok := server.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s: %w", id, errUnknownJob))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, foundJob.status)
+}
+
+func (server *Server) handleCancelJob(w http.ResponseWriter, id string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	foundJob, ok := server.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s: %w", id, errUnknownJob))
+		return
+	}
+
+	// Jobs that have already finished are left as they are
+	if foundJob.status.State == jobRunning {
+		foundJob.status.State = jobCancelled
+		foundJob.cancel()
+	}
+
+	writeJSON(w, http.StatusOK, foundJob.status)
+}
+
+// statusForError gets the status to respond with when solving a part fails
+func statusForError(err error) int {
+	if errors.Is(err, aoc.ErrNoAnswer) {
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	response := errorResponse{Error: err.Error()}
+	var parseErr *aoc.ParseError
+	if errors.As(err, &parseErr) {
+		response.Line = parseErr.Line
+		response.Column = parseErr.Column
+	}
+
+	writeJSON(w, status, response)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	// There is nothing more that can be done if the client has gone away
+	encoder.Encode(value)
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+)
+
+// The days registered by the tests, out of the way of the real ones
+const (
+	sumDay = 101
+	jobDay = 102
+)
+
+var errNotANumber = errors.New("not a number")
+
+func parseNumbers(reader io.Reader) (interface{}, error) {
+	lines, err := aoc.ReadLines(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make([]int, len(lines))
+	for i, line := range lines {
+		numbers[i], err = strconv.Atoi(line)
+		if err != nil {
+			return nil, aoc.NewParseError(sumDay, i, -1, line, errNotANumber)
+		}
+	}
+
+	return numbers, nil
+}
+
+func sum(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+	total := 0
+	for _, n := range input.([]int) {
+		total += n
+	}
+
+	return total, nil
+}
+
+func init() {
+	aoc.Register(sumDay, aoc.Puzzle{Parse: parseNumbers, Part1: sum, Part2: sum})
+	aoc.Register(jobDay, aoc.Puzzle{
+		Parse: parseNumbers,
+		Part1: sum,
+		// Part 2 never finishes, so must be cancelled
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			steps := budget.Start(ctx)
+			steps.SetStage("waiting")
+			<-ctx.Done()
+
+			return nil, steps.Take()
+		},
+		LongRunning: map[int]bool{1: true, 2: true},
+	})
+}
+
+func request(t *testing.T, server *Server, method string, path string, body string, wantStatus int, response interface{}) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if recorder.Code != wantStatus {
+		t.Fatalf("%s %s: got status %d, want %d: %s", method, path, recorder.Code, wantStatus, recorder.Body)
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatalf("%s %s: %s", method, path, err)
+		}
+	}
+}
+
+// waitForJob polls a job until done returns true for it
+func waitForJob(t *testing.T, server *Server, id string, done func(jobStatus) bool) jobStatus {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		var status jobStatus
+		request(t, server, http.MethodGet, "/jobs/"+id, "", http.StatusOK, &status)
+		if done(status) {
+			return status
+		}
+	}
+
+	t.Fatalf("job %s took too long", id)
+	return jobStatus{}
+}
+
+func TestSolve(t *testing.T) {
+	var result aoc.Result
+	request(t, New(1), http.MethodPost, "/days/101/parts/1", "1\n2\n3\n", http.StatusOK, &result)
+	if result.Day != sumDay || result.Part != 1 || result.Answer != 6.0 {
+		t.Errorf("got %+v, want an answer of 6 for day %d part 1", result, sumDay)
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantLine   int
+	}{
+		{"unknown day", http.MethodPost, "/days/99/parts/1", "1", http.StatusNotFound, 0},
+		{"day is not a number", http.MethodPost, "/days/one/parts/1", "1", http.StatusNotFound, 0},
+		{"unknown part", http.MethodPost, "/days/101/parts/3", "1", http.StatusNotFound, 0},
+		{"wrong method", http.MethodGet, "/days/101/parts/1", "", http.StatusMethodNotAllowed, 0},
+		{"malformed input", http.MethodPost, "/days/101/parts/1", "1\ntwo\n", http.StatusBadRequest, 2},
+		{"unknown job", http.MethodGet, "/jobs/nope", "", http.StatusNotFound, 0},
+		{"unknown path", http.MethodGet, "/days/101", "", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response errorResponse
+			request(t, New(1), tt.method, tt.path, tt.body, tt.wantStatus, &response)
+			if response.Error == "" {
+				t.Error("response has no error")
+			} else if response.Line != tt.wantLine {
+				t.Errorf("got an error on line %d, want %d", response.Line, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestJobFinishes(t *testing.T) {
+	server := New(1)
+	var started jobStatus
+	request(t, server, http.MethodPost, "/days/102/parts/1", "4\n5\n", http.StatusAccepted, &started)
+	if started.ID == "" || started.Day != jobDay || started.Part != 1 {
+		t.Fatalf("got %+v, want a job for day %d part 1", started, jobDay)
+	}
+
+	finished := waitForJob(t, server, started.ID, func(status jobStatus) bool { return status.State != jobRunning })
+	if finished.State != jobDone || finished.Result == nil || finished.Result.Answer != 9.0 {
+		t.Errorf("got %+v, want a finished job with an answer of 9", finished)
+	}
+}
+
+func TestJobCancelled(t *testing.T) {
+	server := New(1)
+	var started jobStatus
+	request(t, server, http.MethodPost, "/days/102/parts/2", "1\n", http.StatusAccepted, &started)
+	waitForJob(t, server, started.ID, func(status jobStatus) bool { return status.Progress.Stage == "waiting" })
+
+	var cancelled jobStatus
+	request(t, server, http.MethodDelete, "/jobs/"+started.ID, "", http.StatusOK, &cancelled)
+	if cancelled.State != jobCancelled {
+		t.Errorf("got state %s after cancelling, want %s", cancelled.State, jobCancelled)
+	}
+
+	// The job stops running once it sees it has been cancelled, which must not change its state
+	server.mutex.Lock()
+	cancelledJob := server.jobs[started.ID]
+	server.mutex.Unlock()
+	waitForJob(t, server, started.ID, func(jobStatus) bool {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		return !cancelledJob.finished.IsZero()
+	})
+
+	var status jobStatus
+	request(t, server, http.MethodGet, "/jobs/"+started.ID, "", http.StatusOK, &status)
+	if status.State != jobCancelled || status.Error != "" {
+		t.Errorf("got %+v once the job stopped, want it to still be cancelled", status)
+	}
+}
+
+func TestJobParsesInput(t *testing.T) {
+	server := New(1)
+	var started jobStatus
+	request(t, server, http.MethodPost, "/days/102/parts/1", "4\nfive\n", http.StatusAccepted, &started)
+
+	failed := waitForJob(t, server, started.ID, func(status jobStatus) bool { return status.State != jobRunning })
+	if failed.State != jobFailed || failed.Error == "" || failed.Line != 2 {
+		t.Errorf("got %+v, want a failed job with an error on line 2", failed)
+	}
+}
+
+func TestTooManyJobs(t *testing.T) {
+	server := New(1)
+	var started jobStatus
+	request(t, server, http.MethodPost, "/days/102/parts/2", "1\n", http.StatusAccepted, &started)
+
+	var response errorResponse
+	request(t, server, http.MethodPost, "/days/102/parts/1", "1\n", http.StatusServiceUnavailable, &response)
+	if response.Error == "" {
+		t.Error("response has no error")
+	}
+
+	// Parts that aren't run as jobs are unaffected
+	request(t, server, http.MethodPost, "/days/101/parts/1", "1\n", http.StatusOK, nil)
+
+	// A cancelled job counts until it stops, after which another can start
+	request(t, server, http.MethodDelete, "/jobs/"+started.ID, "", http.StatusOK, nil)
+	server.mutex.Lock()
+	cancelledJob := server.jobs[started.ID]
+	server.mutex.Unlock()
+	waitForJob(t, server, started.ID, func(jobStatus) bool {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		return !cancelledJob.finished.IsZero()
+	})
+
+	request(t, server, http.MethodPost, "/days/102/parts/1", "1\n", http.StatusAccepted, nil)
+}
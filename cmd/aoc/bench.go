@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/bench"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+// defaultThreshold is how much slower a stage can get than the baseline before it counts as a regression
+const defaultThreshold = 0.1
+
+func benchCommand(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	day := flags.Int("day", 0, "day to time, or 0 to time every day")
+	inputDir := flags.String("inputs", ".", "directory holding a dayN/input.txt for each day")
+	outPath := flags.String("out", "", "file to write the JSON timing report to, instead of stdout")
+	baselinePath := flags.String("baseline", "", "timing report to compare against")
+	threshold := flags.Float64("threshold", defaultThreshold, "fraction a stage may slow down by before it is a regression")
+	generated := flags.Bool("generate", false, "time random inputs made by ./aoc generate, instead of reading them from the inputs directory")
+	seed := flags.Int64("seed", 1, "seed for the random inputs, if they are generated")
+	size := flags.Int("size", 0, "size of the random inputs, if they are generated, or 0 for about the size of the real ones")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ./aoc bench [-day n] [-inputs dir] [-out file] [-baseline file] [-threshold f] [-generate [-seed n] [-size n]]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	days := aoc.Days()
+	if *day != 0 {
+		if _, err := aoc.Lookup(*day); err != nil {
+			return err
+		}
+		days = []int{*day}
+	}
+
+	// Read the baseline first, so that a bad path is found before spending time on the benchmarks
+	var baseline bench.Report
+	if *baselinePath != "" {
+		var err error
+		baseline, err = bench.ReadReport(*baselinePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	timings := []bench.Timing{}
+	for _, benchDay := range days {
+		var rawInput []byte
+		if *generated {
+			input, err := generate.Input(benchDay, *seed, *size)
+			if err != nil {
+				return err
+			}
+			rawInput = []byte(input)
+		} else {
+			inputPath := filepath.Join(*inputDir, fmt.Sprintf("day%d", benchDay), "input.txt")
+			var err error
+			rawInput, err = ioutil.ReadFile(inputPath)
+			if os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "day %d: skipped, as %s does not exist\n", benchDay, inputPath)
+				continue
+			} else if err != nil {
+				return err
+			}
+		}
+
+		dayTimings, err := bench.Measure(benchDay, rawInput)
+		if err != nil {
+			return err
+		}
+
+		for _, timing := range dayTimings {
+			fmt.Fprintf(os.Stderr, "day %d %s: %s\n", timing.Day, timing.Stage, time.Duration(timing.NsPerOp))
+		}
+		timings = append(timings, dayTimings...)
+	}
+
+	report := bench.NewReport(timings)
+	err := writeReport(*outPath, report)
+	if err != nil {
+		return err
+	}
+
+	if *baselinePath == "" {
+		return nil
+	}
+
+	regressions := bench.Compare(baseline, report, *threshold)
+	for _, regression := range regressions {
+		fmt.Fprintln(os.Stderr, "regression:", regression)
+	}
+	if len(regressions) > 0 {
+		return fmt.Errorf("found %d regressions of more than %.0f%% against %s", len(regressions), *threshold*100, *baselinePath)
+	}
+
+	return nil
+}
+
+// writeReport writes the report to the file at the given path, or stdout if there is no path
+func writeReport(path string, report bench.Report) error {
+	if path == "" {
+		return bench.WriteReport(os.Stdout, report)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = bench.WriteReport(file, report)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,27 @@
+package main
+
+// Importing each day registers its solvers
+import (
+	_ "github.com/ollien/advent-of-code-2018/day1"
+	_ "github.com/ollien/advent-of-code-2018/day10"
+	_ "github.com/ollien/advent-of-code-2018/day11"
+	_ "github.com/ollien/advent-of-code-2018/day12"
+	_ "github.com/ollien/advent-of-code-2018/day13"
+	_ "github.com/ollien/advent-of-code-2018/day14"
+	_ "github.com/ollien/advent-of-code-2018/day15"
+	_ "github.com/ollien/advent-of-code-2018/day16"
+	_ "github.com/ollien/advent-of-code-2018/day17"
+	_ "github.com/ollien/advent-of-code-2018/day18"
+	_ "github.com/ollien/advent-of-code-2018/day19"
+	_ "github.com/ollien/advent-of-code-2018/day2"
+	_ "github.com/ollien/advent-of-code-2018/day20"
+	_ "github.com/ollien/advent-of-code-2018/day21"
+	_ "github.com/ollien/advent-of-code-2018/day22"
+	_ "github.com/ollien/advent-of-code-2018/day3"
+	_ "github.com/ollien/advent-of-code-2018/day4"
+	_ "github.com/ollien/advent-of-code-2018/day5"
+	_ "github.com/ollien/advent-of-code-2018/day6"
+	_ "github.com/ollien/advent-of-code-2018/day7"
+	_ "github.com/ollien/advent-of-code-2018/day8"
+	_ "github.com/ollien/advent-of-code-2018/day9"
+)
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const (
+	formatGIF = "gif"
+	formatSVG = "svg"
+)
+
+var errTickOutOfRange = errors.New("tick is not on the recording")
+
+func exportCommand(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	day := flags.Int("day", 0, "day to export")
+	part := flags.Int("part", 1, "part whose simulation should be exported")
+	format := flags.String("format", formatGIF, "image format, which is gif for an animation of every tick, or svg for a single tick")
+	outPath := flags.String("out", "", "file to write the image to, instead of stdout")
+	cellSize := flags.Int("cell", 4, "width and height of each cell, in pixels")
+	rawPalette := flags.String("palette", "", "colours to use instead of the day's own, as char=#rrggbb pairs separated by commas")
+	rawBackground := flags.String("background", "#000000", "colour of cells that have no colour in the palette")
+	delay := flags.Duration("delay", 100*time.Millisecond, "time each tick of a gif is shown for")
+	every := flags.Int("every", 1, "only draw every nth tick in a gif, to shorten long simulations")
+	tick := flags.Int("tick", -1, "tick to draw as an svg, or -1 for the last one")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ./aoc export -day n [-part n] [-format gif|svg] [-out file] [-cell n] [-palette p] [-background #rrggbb] [-delay d] [-every n] [-tick n] [in_file]")
+		fmt.Fprintf(flags.Output(), "Days that can be exported: %v\n", replay.Days())
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 1 || *day == 0 {
+		flags.Usage()
+		os.Exit(2)
+	} else if *format != formatGIF && *format != formatSVG {
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+
+	simulation, err := replay.Lookup(*day)
+	if err != nil {
+		return err
+	}
+
+	options := replay.ImageOptions{Palette: replay.Palette{}, CellSize: *cellSize, Delay: *delay, Every: *every}
+	options.Background, err = replay.ParseColour(*rawBackground)
+	if err != nil {
+		return fmt.Errorf("background %s: %w", *rawBackground, err)
+	}
+
+	// Colours that are given override the day's own, which are kept for everything else
+	overrides, err := replay.ParsePalette(*rawPalette)
+	if err != nil {
+		return err
+	}
+	for char, colour := range simulation.Palette {
+		options.Palette[char] = colour
+	}
+	for char, colour := range overrides {
+		options.Palette[char] = colour
+	}
+
+	tape := replay.NewTape()
+	if err := record(*day, *part, flags.Arg(0), simulation, tape); err != nil {
+		return err
+	}
+
+	if *tick == -1 {
+		*tick = tape.Len() - 1
+	} else if *tick < 0 || *tick >= tape.Len() {
+		return fmt.Errorf("tick %d: %w", *tick, errTickOutOfRange)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		outFile, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer outFile.Close()
+		out = outFile
+	}
+
+	if *format == formatSVG {
+		return replay.WriteSVG(out, tape.Frame(*tick), options)
+	}
+
+	return replay.WriteGIF(out, tape, options)
+}
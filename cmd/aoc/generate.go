@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+func generateCommand(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	day := flags.Int("day", 0, "day to make an input for")
+	seed := flags.Int64("seed", 1, "seed for the random input, which always gives the same input for the same size")
+	size := flags.Int("size", 0, "size of the input, or 0 for about the size of the real one")
+	outPath := flags.String("out", "", "file to write the input to, instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ./aoc generate -day n [-seed n] [-size n] [-out file]")
+		fmt.Fprintln(flags.Output(), "What the size counts for each day:")
+		for _, generatorDay := range generate.Days() {
+			generator, _ := generate.Lookup(generatorDay)
+			sizeName := generator.Size
+			if sizeName == "" {
+				sizeName = "(ignored)"
+			}
+			fmt.Fprintf(flags.Output(), "  day %d: %s, %d by default\n", generatorDay, sizeName, generator.DefaultSize)
+		}
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 0 || *day == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	input, err := generate.Input(*day, *seed, *size)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		outFile, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer outFile.Close()
+		out = outFile
+	}
+
+	_, err = io.WriteString(out, input)
+
+	return err
+}
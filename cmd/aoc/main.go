@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+)
+
+const usage = `Usage: ./aoc command [flags]
+
+Commands:
+  run      solve a day's puzzle, e.g. ./aoc run -day 15 -part 2 input.txt, reading stdin if no file or "-" is given
+  list     list every day that can be solved
+  bench    time every day's input.txt or a generated input, writing a JSON report and comparing it with a baseline report if one is given
+  replay   record a day's simulation and play it back, e.g. ./aoc replay -day 15 input.txt, taking commands on stdin
+  export   draw a day's simulation as an animated GIF or an SVG, e.g. ./aoc export -day 18 -out day18.gif input.txt
+  serve    serve every day over HTTP, e.g. curl --data-binary @input.txt localhost:8080/days/15/parts/1
+  generate make a random input for a day, e.g. ./aoc generate -day 3 -seed 7 -size 5000 | ./aoc run -day 3`
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var errUnknownFormat = errors.New("unknown output format")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		return
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "list":
+		listCommand()
+	case "bench":
+		err = benchCommand(os.Args[2:])
+	case "replay":
+		err = replayCommand(os.Args[2:])
+	case "export":
+		err = exportCommand(os.Args[2:])
+	case "serve":
+		err = serveCommand(os.Args[2:])
+	case "generate":
+		err = generateCommand(os.Args[2:])
+	default:
+		fmt.Println(usage)
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	day := flags.Int("day", 0, "day to solve")
+	part := flags.Int("part", 0, "part to solve, or 0 to solve both")
+	format := flags.String("format", formatText, "output format, which is text for just the answers, or json for a JSON object for each part")
+	timeout := flags.Duration("timeout", 0, "how long to spend solving every part, or 0 for no limit")
+	maxSteps := flags.Int("max-steps", 0, "how many steps each part may take before giving up, or 0 for no limit")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ./aoc run -day n [-part n] [-format text|json] [-timeout d] [-max-steps n] [in_file]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 1 || *day == 0 {
+		flags.Usage()
+		os.Exit(2)
+	} else if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%s: %w", *format, errUnknownFormat)
+	}
+
+	parts := []int{*part}
+	if *part == 0 {
+		parts = []int{1, 2}
+	}
+
+	_, err := aoc.Lookup(*day)
+	if err != nil {
+		return err
+	}
+
+	// Check every part exists up front, so the input is not read if the arguments are wrong
+	for _, partNum := range parts {
+		if partNum < 1 || partNum > aoc.NumParts {
+			return fmt.Errorf("part %d: %w", partNum, aoc.ErrUnknownPart)
+		}
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	budget := aoc.Budget{MaxSteps: *maxSteps}
+	inFile := flags.Arg(0)
+	if *format == formatJSON {
+		// Each part is written as a line of its own, so that it can be read before the other parts are solved
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		err = aoc.RunFunc(ctx, *day, parts, inFile, budget, func(result aoc.Result) error {
+			return encoder.Encode(result)
+		})
+	} else {
+		err = aoc.Run(ctx, os.Stdout, *day, parts, inFile, budget)
+	}
+	if err != nil {
+		// Parse errors are shown as a diagnostic pointing at the offending part of the input
+		aoc.Fatal(aoc.InputName(inFile), err)
+	}
+
+	return nil
+}
+
+func listCommand() {
+	for _, day := range aoc.Days() {
+		fmt.Println(day)
+	}
+}
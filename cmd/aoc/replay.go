@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+var errReplayStdin = errors.New("replay reads its commands from stdin, so the input must be given as a file")
+
+func replayCommand(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	day := flags.Int("day", 0, "day to replay")
+	part := flags.Int("part", 1, "part whose simulation should be replayed")
+	fps := flags.Float64("fps", 10, "ticks to play each second")
+	rows := flags.Int("rows", 50, "rows of the board to show at once")
+	cols := flags.Int("cols", 160, "columns of the board to show at once")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ./aoc replay -day n [-part n] [-fps f] [-rows n] [-cols n] in_file")
+		fmt.Fprintf(flags.Output(), "Days that can be replayed: %v\n", replay.Days())
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 || *day == 0 {
+		flags.Usage()
+		os.Exit(2)
+	} else if flags.Arg(0) == aoc.StdinPath {
+		return errReplayStdin
+	}
+
+	simulation, err := replay.Lookup(*day)
+	if err != nil {
+		return err
+	}
+
+	tape := replay.NewTape()
+	if err := record(*day, *part, flags.Arg(0), simulation, tape); err != nil {
+		return err
+	}
+
+	player := replay.NewPlayer(tape, simulation.Palette, os.Stdout)
+	player.FPS = *fps
+	player.Rows = *rows
+	player.Cols = *cols
+
+	return player.Play(os.Stdin)
+}
+
+// record parses the input at path and runs a day's simulation on it, recording it with the recorder
+func record(day int, part int, path string, simulation replay.Simulation, recorder replay.Recorder) error {
+	puzzle, err := aoc.Lookup(day)
+	if err != nil {
+		return err
+	}
+
+	inFile, err := aoc.OpenInput(path)
+	if err != nil {
+		return err
+	}
+	defer inFile.Close()
+
+	input, err := puzzle.Parse(inFile)
+	if err != nil {
+		// Parse errors are shown as a diagnostic pointing at the offending part of the input
+		aoc.Fatal(aoc.InputName(path), err)
+	}
+
+	return simulation.Run(input, part, recorder)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/ollien/advent-of-code-2018/aoc/server"
+)
+
+func serveCommand(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	maxJobs := flags.Int("max-jobs", runtime.NumCPU(), "number of long-running parts that may be solved at once")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ./aoc serve [-addr host:port] [-max-jobs n]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 || *maxJobs < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Fprintf(os.Stderr, "Serving on http://%s\n", *addr)
+
+	return http.ListenAndServe(*addr, server.New(*maxJobs))
+}
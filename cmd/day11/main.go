@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/day11"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Println("Usage: ./main serial_number")
+		return
+	}
+
+	serialNumber, err := day11.Parse(strings.NewReader(os.Args[1]))
+	if err != nil {
+		aoc.Fatal("serial_number", err)
+	}
+
+	cell, err := day11.Part1(context.Background(), serialNumber, aoc.Budget{})
+	if err != nil {
+		aoc.Fatal("serial_number", err)
+	}
+	fmt.Println(cell)
+
+	square, err := day11.Part2(context.Background(), serialNumber, aoc.Budget{})
+	if err != nil {
+		aoc.Fatal("serial_number", err)
+	}
+	fmt.Println(square)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/day14"
+)
+
+const usageString = "Usage: ./main number_of_scores partNum[,partNum]"
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Println(usageString)
+		return
+	}
+
+	rawNumScores := os.Args[1]
+	partNum := os.Args[2]
+	// Kind of ugly but it works for the simple argparsing of this...
+	runPart1 := partNum == "1" || partNum == "1,2"
+	runPart2 := partNum == "2" || partNum == "1,2"
+	if !runPart1 && !runPart2 {
+		fmt.Println(usageString)
+		return
+	}
+
+	input, err := day14.Parse(strings.NewReader(rawNumScores))
+	if err != nil {
+		aoc.Fatal("number_of_scores", err)
+	}
+
+	if runPart1 {
+		scores, err := day14.Part1(context.Background(), input, aoc.Budget{})
+		if err != nil {
+			aoc.Fatal("number_of_scores", err)
+		}
+		fmt.Println(scores)
+	}
+
+	if runPart2 {
+		numRecipes, err := day14.Part2(context.Background(), input, aoc.Budget{})
+		if err != nil {
+			aoc.Fatal("number_of_scores", err)
+		}
+		fmt.Println(numRecipes)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/ollien/advent-of-code-2018/aoc"
+	_ "github.com/ollien/advent-of-code-2018/day15"
+)
+
+func main() {
+	aoc.Main(15)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+)
+
+func main() {
+	outFile := flag.String("o", "", "file to write the program to, instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./main [-o out_file] [in_file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		return
+	}
+
+	inFile := flag.Arg(0)
+	input, err := aoc.OpenInput(inFile)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	source, err := aoc.ReadLines(input)
+	input.Close()
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	program, err := elfcode.Assemble(source)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	var writer io.Writer = os.Stdout
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			elfcode.Fatal(inFile, err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	_, err = io.WriteString(writer, program.String())
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+)
+
+func main() {
+	outFile := flag.String("o", "", "file to write the Go source to, instead of stdout")
+	numRegisters := flag.Int("registers", 6, "number of registers the device has")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./main [-registers n] [-o out_file] [in_file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		return
+	}
+
+	inFile := flag.Arg(0)
+	input, err := aoc.OpenInput(inFile)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	rawInstructions, err := aoc.ReadLines(input)
+	input.Close()
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	program, err := elfcode.ParseProgram(rawInstructions)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	var writer io.Writer = os.Stdout
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			elfcode.Fatal(inFile, err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	err = elfcode.WriteGo(writer, program, *numRegisters, filepath.Base(aoc.InputName(inFile)))
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+)
+
+const (
+	defaultNumRegisters = 6
+	defaultHistorySize  = 10000
+	prompt              = "(elfdbg) "
+	helpText            = `Commands:
+  s, step [n]          run n instructions (default 1), ignoring a breakpoint on the current one
+  c, continue          run until a breakpoint or watchpoint is hit, or the program halts
+  r, run n             run n instructions, stopping early on breakpoints and watchpoints
+  back [n]             step back n instructions (default 1) using the history buffer
+  b, break n           stop before running instruction n
+  d, delete n          remove the breakpoint on instruction n
+  w, watch r           stop whenever register r changes
+  u, unwatch r         remove the watchpoint on register r
+  set r v              set register r to v
+  ip n                 set the instruction pointer to n
+  p, regs              print the registers
+  l, list [n]          list the instructions around the instruction pointer, or around n
+  i, info              list breakpoints and watchpoints
+  h, help              print this message
+  q, quit              exit the debugger`
+)
+
+var errUsage = errors.New("bad arguments, see help")
+
+// command runs a single debugger command, given its arguments
+type command func(d *elfcode.Debugger, out io.Writer, args []int) error
+
+var commands = map[string]command{
+	"s":        stepCommand,
+	"step":     stepCommand,
+	"c":        continueCommand,
+	"continue": continueCommand,
+	"r":        runCommand,
+	"run":      runCommand,
+	"back":     backCommand,
+	"b":        breakCommand,
+	"break":    breakCommand,
+	"d":        deleteCommand,
+	"delete":   deleteCommand,
+	"w":        watchCommand,
+	"watch":    watchCommand,
+	"u":        unwatchCommand,
+	"unwatch":  unwatchCommand,
+	"set":      setCommand,
+	"ip":       ipCommand,
+	"p":        printCommand,
+	"regs":     printCommand,
+	"l":        listCommand,
+	"list":     listCommand,
+	"i":        infoCommand,
+	"info":     infoCommand,
+}
+
+// optionalArg gets the argument at the given index, or the fallback if there are not enough arguments
+func optionalArg(args []int, index int, fallback int) int {
+	if index >= len(args) {
+		return fallback
+	}
+
+	return args[index]
+}
+
+func printStop(d *elfcode.Debugger, out io.Writer, stop elfcode.Stop) {
+	fmt.Fprintln(out, stop)
+	printCurrent(d, out)
+}
+
+func printCurrent(d *elfcode.Debugger, out io.Writer) {
+	machine := d.Machine()
+	instructions := machine.Program().Instructions
+	ip := machine.IP()
+	if machine.Halted() {
+		fmt.Fprintf(out, "%v after %d instructions\n", machine.Registers, machine.NumSteps())
+		return
+	}
+
+	fmt.Fprintf(out, "%v %d: %s\n", machine.Registers, ip, instructions[ip])
+}
+
+func stepCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	n := optionalArg(args, 0, 1)
+	var stop elfcode.Stop
+	// Unlike run, step deliberately walks through breakpoints
+	for i := 0; i < n; i++ {
+		stop = d.Step()
+		if stop.Reason == elfcode.StopHalted || stop.Reason == elfcode.StopWatchpoint {
+			break
+		}
+	}
+	printStop(d, out, stop)
+
+	return nil
+}
+
+func continueCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	printStop(d, out, d.Continue())
+
+	return nil
+}
+
+func runCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	printStop(d, out, d.RunN(args[0]))
+
+	return nil
+}
+
+func backCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	n := optionalArg(args, 0, 1)
+	for i := 0; i < n; i++ {
+		err := d.StepBack()
+		if err != nil {
+			return err
+		}
+	}
+	printCurrent(d, out)
+
+	return nil
+}
+
+func breakCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	return d.AddBreakpoint(args[0])
+}
+
+func deleteCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	d.RemoveBreakpoint(args[0])
+
+	return nil
+}
+
+func watchCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	return d.AddWatchpoint(args[0])
+}
+
+func unwatchCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	d.RemoveWatchpoint(args[0])
+
+	return nil
+}
+
+func setCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	err := d.SetRegister(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	printCurrent(d, out)
+
+	return nil
+}
+
+func ipCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	d.Machine().SetIP(args[0])
+	printCurrent(d, out)
+
+	return nil
+}
+
+func printCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	printCurrent(d, out)
+
+	return nil
+}
+
+func listCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	const listRadius = 5
+	machine := d.Machine()
+	instructions := machine.Program().Instructions
+	center := optionalArg(args, 0, machine.IP())
+	breakpoints := map[int]bool{}
+	for _, breakpoint := range d.Breakpoints() {
+		breakpoints[breakpoint] = true
+	}
+
+	for i := center - listRadius; i <= center+listRadius; i++ {
+		if i < 0 || i >= len(instructions) {
+			continue
+		}
+
+		marker := "  "
+		if i == machine.IP() {
+			marker = "=>"
+		}
+		breakMarker := " "
+		if breakpoints[i] {
+			breakMarker = "*"
+		}
+		fmt.Fprintf(out, "%s%s %3d: %s\n", marker, breakMarker, i, instructions[i])
+	}
+
+	return nil
+}
+
+func infoCommand(d *elfcode.Debugger, out io.Writer, args []int) error {
+	fmt.Fprintln(out, "breakpoints:", d.Breakpoints())
+	fmt.Fprintln(out, "watchpoints:", d.Watchpoints())
+	fmt.Fprintf(out, "%d instructions run, %d in history\n", d.Machine().NumSteps(), d.HistoryLen())
+
+	return nil
+}
+
+// runREPL reads commands from in until it is exhausted or the user quits. An empty line repeats the last command.
+func runREPL(d *elfcode.Debugger, in io.Reader, out io.Writer) {
+	scanner := bufio.NewScanner(in)
+	lastLine := ""
+	fmt.Fprint(out, prompt)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			line = lastLine
+		}
+		lastLine = line
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			fmt.Fprint(out, prompt)
+			continue
+		}
+
+		name := fields[0]
+		if name == "q" || name == "quit" {
+			return
+		} else if name == "h" || name == "help" {
+			fmt.Fprintln(out, helpText)
+			fmt.Fprint(out, prompt)
+			continue
+		}
+
+		err := dispatch(d, out, name, fields[1:])
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+		}
+		fmt.Fprint(out, prompt)
+	}
+}
+
+func dispatch(d *elfcode.Debugger, out io.Writer, name string, rawArgs []string) error {
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	args := make([]int, len(rawArgs))
+	for i, rawArg := range rawArgs {
+		arg, err := strconv.Atoi(rawArg)
+		if err != nil {
+			return errUsage
+		}
+		args[i] = arg
+	}
+
+	return cmd(d, out, args)
+}
+
+func main() {
+	numRegisters := flag.Int("registers", defaultNumRegisters, "number of registers on the device")
+	historySize := flag.Int("history", defaultHistorySize, "number of instructions that can be stepped back through")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./main [-registers n] [-history n] in_file")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return
+	}
+
+	inFile := flag.Arg(0)
+	input, err := os.Open(inFile)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	rawInstructions, err := aoc.ReadLines(input)
+	input.Close()
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	program, err := elfcode.ParseProgram(rawInstructions)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	machine, err := elfcode.NewMachine(program, *numRegisters)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	debugger := elfcode.NewDebugger(machine, *historySize)
+	printCurrent(debugger, os.Stdout)
+	runREPL(debugger, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+)
+
+func main() {
+	numRegisters := flag.Int("registers", 6, "number of registers the device has")
+	register0 := flag.Int("r0", 0, "initial value of register 0")
+	maxSteps := flag.Int("max-steps", 100000000, "stop after this many instructions, or never stop if 0")
+	optimize := flag.Bool("optimize", false, "replace known loops with native operations, which are counted as a single instruction")
+	jsonFile := flag.String("json", "", "file to write the profile to as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./main [-registers n] [-r0 n] [-max-steps n] [-optimize] [-json out_file] [in_file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		return
+	}
+
+	inFile := flag.Arg(0)
+	input, err := aoc.OpenInput(inFile)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	rawInstructions, err := aoc.ReadLines(input)
+	input.Close()
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	program, err := elfcode.ParseProgram(rawInstructions)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	machine, err := elfcode.NewMachine(program, *numRegisters)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+	if *optimize {
+		machine.Optimize()
+	}
+	machine.Registers[0] = *register0
+
+	profiler := elfcode.NewProfiler(machine)
+	profiler.Run(*maxSteps)
+	profile := profiler.Profile()
+
+	err = profile.WriteTable(os.Stdout)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	if *jsonFile != "" {
+		encoded, err := json.MarshalIndent(profile, "", "\t")
+		if err != nil {
+			elfcode.Fatal(inFile, err)
+		}
+
+		err = ioutil.WriteFile(*jsonFile, append(encoded, '\n'), 0644)
+		if err != nil {
+			elfcode.Fatal(inFile, err)
+		}
+	}
+}
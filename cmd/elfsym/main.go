@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+)
+
+func main() {
+	numRegisters := flag.Int("registers", 6, "number of registers the device has")
+	maxSteps := flag.Int("max-steps", 100000000, "stop after this many instructions across all paths, or never stop if 0")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ./main [-registers n] [-max-steps n] [in_file]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		return
+	}
+
+	inFile := flag.Arg(0)
+	input, err := aoc.OpenInput(inFile)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	rawInstructions, err := aoc.ReadLines(input)
+	input.Close()
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	program, err := elfcode.ParseProgram(rawInstructions)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	result, err := elfcode.ExecuteSymbolic(program, *numRegisters, *maxSteps)
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "instructions\tcondition")
+	for _, path := range result.HaltingPaths {
+		fmt.Fprintf(table, "%d\t%s\n", path.Instructions, path.Condition)
+	}
+	err = table.Flush()
+	if err != nil {
+		elfcode.Fatal(inFile, err)
+	}
+
+	fmt.Printf("\n%d halting paths, %d paths never halt\n", len(result.HaltingPaths), result.Repeated)
+	if result.Truncated {
+		fmt.Println("stopped early, so some paths may be missing")
+	}
+
+	if len(result.HaltingPaths) > 0 {
+		fmt.Println("fewest instructions:", result.HaltingPaths[0].Condition)
+		fmt.Println("most instructions:", result.HaltingPaths[len(result.HaltingPaths)-1].Condition)
+	}
+}
@@ -0,0 +1,88 @@
+// Package cycle finds where simulations that step from one state to the next start repeating themselves, so that the
+// state after a very large number of steps can be found without running every one of them
+package cycle
+
+// Cycle describes where a sequence of states starts repeating
+type Cycle struct {
+	// Start is the first step whose state repeats
+	Start int
+	// Length is the number of steps it takes for a state in the cycle to come around again
+	Length int
+}
+
+// Reduce maps step n onto the earliest step with the same state, along with the number of whole cycles between them
+func (c Cycle) Reduce(n int) (step int, cycles int) {
+	if n < c.Start {
+		return n, 0
+	}
+
+	return c.Start + (n-c.Start)%c.Length, (n - c.Start) / c.Length
+}
+
+// Floyd finds the cycle in the states reached by repeatedly stepping from the initial state, using Floyd's
+// tortoise and hare. The sequence must repeat eventually, or this will never return, and next must not modify its
+// argument.
+func Floyd[S any](initial S, next func(S) S, equal func(S, S) bool) Cycle {
+	// The hare moves twice as fast as the tortoise, so they meet at a multiple of the cycle length
+	tortoise := next(initial)
+	hare := next(next(initial))
+	for !equal(tortoise, hare) {
+		tortoise = next(tortoise)
+		hare = next(next(hare))
+	}
+
+	// The distance from the meeting point to the start of the cycle is the same as from the initial state
+	start := 0
+	tortoise = initial
+	for !equal(tortoise, hare) {
+		tortoise = next(tortoise)
+		hare = next(hare)
+		start++
+	}
+
+	length := 1
+	hare = next(tortoise)
+	for !equal(tortoise, hare) {
+		hare = next(hare)
+		length++
+	}
+
+	return Cycle{Start: start, Length: length}
+}
+
+// Brent finds the cycle in the states reached by repeatedly stepping from the initial state, using Brent's algorithm,
+// which takes fewer steps than Floyd's. The sequence must repeat eventually, or this will never return, and next must
+// not modify its argument.
+func Brent[S any](initial S, next func(S) S, equal func(S, S) bool) Cycle {
+	// The tortoise teleports to the hare at every power of two, until the hare comes back around to it
+	power := 1
+	length := 1
+	tortoise := initial
+	hare := next(initial)
+	for !equal(tortoise, hare) {
+		if power == length {
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+
+		hare = next(hare)
+		length++
+	}
+
+	// With the hare a cycle ahead of the tortoise, they meet at the start of the cycle
+	tortoise = initial
+	hare = initial
+	for i := 0; i < length; i++ {
+		hare = next(hare)
+	}
+
+	start := 0
+	for !equal(tortoise, hare) {
+		tortoise = next(tortoise)
+		hare = next(hare)
+		start++
+	}
+
+	return Cycle{Start: start, Length: length}
+}
@@ -0,0 +1,110 @@
+package cycle
+
+import (
+	"errors"
+	"testing"
+)
+
+// rho steps 0 through 1 and into the cycle 2, 3, 4, 5, 2, ...
+func rho(n int) int {
+	if n == 5 {
+		return 2
+	}
+
+	return n + 1
+}
+
+func equalInts(a, b int) bool {
+	return a == b
+}
+
+func TestDetectors(t *testing.T) {
+	detectors := []struct {
+		name   string
+		detect func(int, func(int) int, func(int, int) bool) Cycle
+	}{
+		{"floyd", Floyd[int]},
+		{"brent", Brent[int]},
+	}
+
+	for _, tt := range detectors {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := tt.detect(0, rho, equalInts), (Cycle{Start: 2, Length: 4}); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	tests := []struct {
+		n          int
+		wantStep   int
+		wantCycles int
+	}{
+		{1, 1, 0},
+		{5, 5, 0},
+		{6, 2, 1},
+		{1000000001, 5, 249999999},
+	}
+
+	c := Cycle{Start: 2, Length: 4}
+	for _, tt := range tests {
+		step, cycles := c.Reduce(tt.n)
+		if step != tt.wantStep || cycles != tt.wantCycles {
+			t.Errorf("Reduce(%d) = %d, %d, want %d, %d", tt.n, step, cycles, tt.wantStep, tt.wantCycles)
+		}
+	}
+}
+
+func TestHistoryRun(t *testing.T) {
+	for _, n := range []int{0, 4, 1000000001} {
+		history := NewHistory(func(n int) int { return n })
+		// Every fourth step from 2 on is the same
+		want := n
+		if n >= 2 {
+			want = 2 + (n-2)%4
+		}
+
+		got, err := history.Run(0, func(n int) (int, error) { return rho(n), nil }, n)
+		if err != nil {
+			t.Fatal(err)
+		} else if got != want {
+			t.Errorf("got %d after %d steps, want %d", got, n, want)
+		}
+	}
+}
+
+func TestHistoryRunStops(t *testing.T) {
+	errStop := errors.New("stop")
+	history := NewHistory(func(n int) int { return n })
+	_, err := history.Run(0, func(n int) (int, error) {
+		if n == 3 {
+			return 0, errStop
+		}
+
+		return rho(n), nil
+	}, 100)
+	if err != errStop {
+		t.Errorf("got error %v, want %v", err, errStop)
+	} else if history.Len() != 4 {
+		t.Errorf("got %d states, want 4", history.Len())
+	}
+}
+
+func TestHashedHistoryCollisions(t *testing.T) {
+	// Every state has the same hash, so only the equality check can tell them apart
+	history := NewHashedHistory(func(int) uint64 { return 0 }, equalInts)
+	for _, state := range []int{0, 1, 2, 3} {
+		if _, ok := history.Add(state); ok {
+			t.Fatalf("%d was reported as a repeat", state)
+		}
+	}
+
+	c, ok := history.Add(1)
+	if !ok {
+		t.Fatal("repeat of 1 was not found")
+	} else if want := (Cycle{Start: 1, Length: 3}); c != want {
+		t.Errorf("got %+v, want %+v", c, want)
+	}
+}
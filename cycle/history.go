@@ -0,0 +1,76 @@
+package cycle
+
+// History records every state of a simulation, so that a repeated state can be spotted as soon as it is reached.
+// States are looked up by a key, so a repeat is found without comparing against every state that came before.
+type History[S any, K comparable] struct {
+	key    func(S) K
+	equal  func(S, S) bool
+	states []S
+	steps  map[K][]int
+}
+
+// NewHistory makes an empty history, where two states are the same if they have the same key
+func NewHistory[S any, K comparable](key func(S) K) *History[S, K] {
+	return &History[S, K]{
+		key:   key,
+		steps: map[K][]int{},
+	}
+}
+
+// NewHashedHistory makes an empty history for states that are too large to use as keys. States are looked up by
+// their hash, and equal settles which of the states with the same hash, if any, is the same.
+func NewHashedHistory[S any](hash func(S) uint64, equal func(S, S) bool) *History[S, uint64] {
+	history := NewHistory[S](hash)
+	history.equal = equal
+
+	return history
+}
+
+// Add records the state for the next step. If it has been seen before it is not recorded, and the cycle it
+// completes is returned instead.
+func (history *History[S, K]) Add(state S) (Cycle, bool) {
+	key := history.key(state)
+	for _, step := range history.steps[key] {
+		if history.equal == nil || history.equal(history.states[step], state) {
+			return Cycle{Start: step, Length: len(history.states) - step}, true
+		}
+	}
+
+	history.steps[key] = append(history.steps[key], len(history.states))
+	history.states = append(history.states, state)
+
+	return Cycle{}, false
+}
+
+// Len gets the number of states that have been recorded
+func (history *History[S, K]) Len() int {
+	return len(history.states)
+}
+
+// State gets the state that was recorded for a step
+func (history *History[S, K]) State(step int) S {
+	return history.states[step]
+}
+
+// Run steps n times from the initial state, recording each state in the history, and gets the final state. If a
+// state repeats along the way, the rest of the steps are skipped, and the final state is taken from the history.
+// The history should be empty to begin with, and next must not modify its argument. If next fails, Run stops and
+// returns its error.
+func (history *History[S, K]) Run(initial S, next func(S) (S, error), n int) (S, error) {
+	state := initial
+	for step := 0; step < n; step++ {
+		if cycle, ok := history.Add(state); ok {
+			equivalentStep, _ := cycle.Reduce(n)
+
+			return history.State(equivalentStep), nil
+		}
+
+		var err error
+		state, err = next(state)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,123 @@
+// Package day1 solves the puzzle for day 1 of Advent of Code 2018
+package day1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+// maxChange is the largest change in frequency a generated input makes
+const maxChange = 20
+
+func parseInput(rawNums []string) ([]int, error) {
+	nums := make([]int, 0, len(rawNums))
+	for i, rawNum := range rawNums {
+		num, err := strconv.Atoi(rawNum)
+		if err != nil {
+			return nil, aoc.NewParseError(1, i, -1, rawNum, err)
+		}
+
+		nums = append(nums, num)
+	}
+
+	return nums, nil
+}
+
+func part1(nums []int, steps *aoc.Steps) (int, error) {
+	total := 0
+	for _, num := range nums {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		total += num
+	}
+
+	return total, nil
+}
+
+// part2 applies the changes over and over until a frequency is reached twice, which never happens if they only ever
+// move the frequency one way
+func part2(nums []int, steps *aoc.Steps) (int, error) {
+	totals := map[int]int{0: 1}
+	lastTotal := 0
+	for i := 0; ; i = (i + 1) % len(nums) {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		newTotal := lastTotal + nums[i]
+		if _, ok := totals[newTotal]; ok {
+			return newTotal, nil
+		}
+
+		totals[newTotal] = 1
+		lastTotal = newTotal
+	}
+}
+
+func init() {
+	aoc.Register(1, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.([]int), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.([]int), budget)
+		},
+	})
+	generate.Register(1, generate.Generator{Size: "frequency changes", DefaultSize: 1000, Generate: Generate})
+}
+
+// Parse parses the list of frequency changes
+func Parse(reader io.Reader) ([]int, error) {
+	rawNums, err := aoc.ReadLines(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseInput(rawNums)
+}
+
+// Part1 finds the resulting frequency after every change
+func Part1(ctx context.Context, nums []int, budget aoc.Budget) (int, error) {
+	return part1(nums, budget.Start(ctx))
+}
+
+// Part2 finds the first frequency that is reached twice
+func Part2(ctx context.Context, nums []int, budget aoc.Budget) (int, error) {
+	if len(nums) == 0 {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return part2(nums, budget.Start(ctx))
+}
+
+// Generate makes a list of size frequency changes. The changes add up to less than the number of them, so some
+// frequency is always reached twice: two of the frequencies reached on the first pass must be a multiple of the total
+// apart, and each pass moves both of them on by the total, until one lands where the other was.
+func Generate(rng *rand.Rand, size int) string {
+	changes := make([]string, size)
+	total := 0
+	for i := 0; i < size-1; i++ {
+		change := generate.Between(rng, -maxChange, maxChange)
+		if change == 0 {
+			change = 1
+		}
+
+		changes[i] = fmt.Sprintf("%+d", change)
+		total += change
+	}
+
+	// The last change brings the total back to within size-1 of zero
+	change := generate.Between(rng, -(size-1), size-1) - total
+	changes[size-1] = fmt.Sprintf("%+d", change)
+
+	return generate.Lines(changes)
+}
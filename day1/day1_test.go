@@ -0,0 +1,96 @@
+package day1
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"+1\n-2\n+3\n+1\n", 3},
+		{"+1\n+1\n+1\n", 3},
+		{"+1\n+1\n-2\n", 0},
+		{"-1\n-2\n-3\n", -6},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Replace(tt.input, "\n", ",", -1), func(t *testing.T) {
+			nums, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Part1(context.Background(), nums, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"+1\n-2\n+3\n+1\n", 2},
+		{"+1\n-1\n", 0},
+		{"+3\n+3\n+4\n-2\n-4\n", 10},
+		{"-6\n+3\n+8\n+5\n-6\n", 5},
+		{"+7\n+7\n-2\n-7\n-4\n", 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Replace(tt.input, "\n", ",", -1), func(t *testing.T) {
+			nums, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Part2(context.Background(), nums, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPart2RunsOutOfSteps(t *testing.T) {
+	// The frequency only ever goes up, so is never reached twice
+	nums := []int{1, 2}
+	_, err := Part2(context.Background(), nums, aoc.Budget{MaxSteps: 100})
+	var cancelledErr *aoc.CancelledError
+	if !errors.As(err, &cancelledErr) || !errors.Is(err, aoc.ErrOutOfSteps) {
+		t.Fatalf("got error %v, want a CancelledError for running out of steps", err)
+	} else if cancelledErr.Progress.Steps != 100 {
+		t.Errorf("got %d steps, want 100", cancelledErr.Progress.Steps)
+	}
+}
+
+func TestPart2Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Part2(ctx, []int{1, 2}, aoc.Budget{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 1)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 1)
+}
@@ -0,0 +1,353 @@
+// Package day10 solves the puzzle for day 10 of Advent of Code 2018
+package day10
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const (
+	inputFormat     = "position=<%d, %d> velocity=<%d, %d>"
+	letterThreshold = 10 // should be 8 for the sample input
+	pointChar       = '#'
+	skyChar         = '.'
+	// recordRows is how few rows the points must fit in before they are recorded, as they are too spread out to draw
+	// before then
+	recordRows = 100
+	// hoursAfterMessage is how many hours are recorded after the message appears, to show the points moving apart again
+	hoursAfterMessage = 3
+)
+
+const (
+	// generatedFormat lines up the numbers in generated inputs, like the real input
+	generatedFormat = "position=<%6d, %6d> velocity=<%2d, %2d>"
+	// maxVelocity is the fastest a generated point moves along each axis
+	maxVelocity = 5
+	// minHours and maxHours bound how long it takes generated points to spell their message
+	minHours = 10000
+	maxHours = 11000
+	// pointsPerCol is roughly how many generated points there are for each column of their message
+	pointsPerCol = 5
+)
+
+// palette colours the points and the sky around them
+var palette = replay.Palette{
+	pointChar: {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	skyChar:   {R: 0x19, G: 0x19, B: 0x70, A: 0xff},
+}
+
+type point struct {
+	row int
+	col int
+}
+
+type velocity struct {
+	rowVelocity int
+	colVelocity int
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return n * -1
+	}
+
+	return n
+}
+
+func normalizeInputLines(points map[point][]velocity, rowOffset int, colOffset int) map[point][]velocity {
+	normalizedPoints := make(map[point][]velocity, len(points))
+	for storedPoint, storedVelocities := range points {
+		storedPoint.row += rowOffset
+		storedPoint.col += colOffset
+		normalizedPoints[storedPoint] = storedVelocities
+	}
+
+	return normalizedPoints
+}
+
+func parseInput(rawPoints []string) (map[point][]velocity, error) {
+	minRow := math.MaxInt32
+	minCol := math.MaxInt32
+	maxRow := 0
+	maxCol := 0
+	points := make(map[point][]velocity, len(rawPoints))
+	for i, rawPoint := range rawPoints {
+		parsedPoint := point{}
+		parsedVelocity := velocity{}
+		numMatched, err := fmt.Sscanf(rawPoint, inputFormat, &parsedPoint.col, &parsedPoint.row, &parsedVelocity.colVelocity, &parsedVelocity.rowVelocity)
+		if err != nil {
+			return nil, aoc.NewParseError(10, i, -1, rawPoint, err)
+		} else if numMatched != 4 {
+			return nil, aoc.NewParseError(10, i, -1, rawPoint, aoc.ErrMalformedInput)
+		}
+		// Several points may start in the same place
+		points[parsedPoint] = append(points[parsedPoint], parsedVelocity)
+		if parsedPoint.row < minRow {
+			minRow = parsedPoint.row
+		}
+		if parsedPoint.col < minCol {
+			minCol = parsedPoint.col
+		}
+		if parsedPoint.row > maxRow {
+			maxRow = parsedPoint.row
+		}
+		if parsedPoint.col > maxCol {
+			maxCol = parsedPoint.col
+		}
+	}
+
+	return normalizeInputLines(points, abs(minRow), abs(minCol)), nil
+}
+func findMinPos(points map[point][]velocity) (minRow int, minCol int, maxRow int, maxCol int) {
+	minCol = math.MaxInt32
+	maxCol = 0
+	minRow = math.MaxInt32
+	maxRow = 0
+	for storedPoint := range points {
+		if storedPoint.row < minRow {
+			minRow = storedPoint.row
+		}
+		if storedPoint.row > maxRow {
+			maxRow = storedPoint.row
+		}
+		if storedPoint.col < minCol {
+			minCol = storedPoint.col
+		}
+		if storedPoint.col > maxCol {
+			maxCol = storedPoint.col
+		}
+	}
+
+	return
+}
+
+func movePoints(points map[point][]velocity) map[point][]velocity {
+	updatedPoints := make(map[point][]velocity)
+	for storedPoint, storedVelocities := range points {
+		for _, storedVelocity := range storedVelocities {
+			updatedPoint := storedPoint
+			updatedPoint.row += storedVelocity.rowVelocity
+			updatedPoint.col += storedVelocity.colVelocity
+			_, ok := updatedPoints[updatedPoint]
+			if ok {
+				updatedPoints[updatedPoint] = append(updatedPoints[updatedPoint], storedVelocity)
+			} else {
+				updatedPoints[updatedPoint] = []velocity{storedVelocity}
+			}
+		}
+	}
+
+	return updatedPoints
+}
+
+// renderBoard renders the points as rows of '#', with '.' for the spaces between them
+func renderBoard(points map[point][]velocity) string {
+	minRow, minCol, maxRow, maxCol := findMinPos(points)
+	lines := make([]string, 0, maxRow-minRow+1)
+	for row := minRow; row <= maxRow; row++ {
+		lineBuffer := bytes.NewBufferString("")
+		for col := minCol; col <= maxCol; col++ {
+			if _, ok := points[point{row, col}]; ok {
+				lineBuffer.WriteRune(pointChar)
+			} else {
+				lineBuffer.WriteRune(skyChar)
+			}
+		}
+		lines = append(lines, lineBuffer.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func shouldPrint(points map[point][]velocity, rowThreshold int) bool {
+	minRow, _, maxRow, _ := findMinPos(points)
+
+	return maxRow-minRow < rowThreshold
+}
+
+// findMessage moves the points until they fit in fewer than rowThreshold rows, which is when they spell a message,
+// returning them and the number of hours taken. A step is taken for each hour, as points that never come together
+// would be moved forever.
+func findMessage(points map[point][]velocity, rowThreshold int, steps *aoc.Steps) (map[point][]velocity, int, error) {
+	hourCount := 0
+	// Don't print until the threshold is met
+	for !shouldPrint(points, rowThreshold) {
+		if err := steps.Take(); err != nil {
+			return nil, 0, err
+		}
+
+		hourCount++
+		points = movePoints(points)
+	}
+
+	return points, hourCount, nil
+}
+
+// recordMessage records the points from the first hour they fit in recordRows rows, until a few hours after they spell
+// the message. Every frame covers the rectangle the points fit in when they are first recorded.
+func recordMessage(points map[point][]velocity, rowThreshold int, recorder replay.Recorder, steps *aoc.Steps) error {
+	points, hourCount, err := findMessage(points, recordRows, steps)
+	if err != nil {
+		return err
+	}
+
+	minRow, minCol, maxRow, maxCol := findMinPos(points)
+	bounds := grid.Rect{Min: grid.Point{Row: minRow, Col: minCol}, Max: grid.Point{Row: maxRow + 1, Col: maxCol + 1}}
+	_, hoursUntilMessage, err := findMessage(points, rowThreshold, steps)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i <= hoursUntilMessage+hoursAfterMessage; i++ {
+		cells := grid.NewDense[rune](bounds)
+		for pos := range bounds.Points() {
+			cells.Set(pos, skyChar)
+		}
+
+		for storedPoint := range points {
+			// Points that move out of the rectangle once the message has gone are left out
+			if pos := (grid.Point{Row: storedPoint.row, Col: storedPoint.col}); bounds.Contains(pos) {
+				cells.Set(pos, pointChar)
+			}
+		}
+
+		recorder.Record(replay.Frame{Caption: fmt.Sprintf("hour %d", hourCount+i), Cells: cells})
+		points = movePoints(points)
+	}
+
+	return nil
+}
+
+func init() {
+	aoc.Register(10, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(10, generate.Generator{Size: "points", DefaultSize: 350, Generate: Generate})
+	replay.Register(10, replay.Simulation{
+		Palette: palette,
+		Run: func(input interface{}, part int, recorder replay.Recorder) error {
+			return Simulate(input.(Input), part, recorder)
+		},
+	})
+}
+
+// Message is the message the points spell, as rows of '#' and '.'
+type Message string
+
+// MarshalJSON writes the message as a list of its rows, so that it can be shown without splitting it up first
+func (m Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Split(string(m), "\n"))
+}
+
+// Input is the position of every point, along with the velocities of the points there
+type Input struct {
+	points map[point][]velocity
+}
+
+// Parse parses the position and velocity of every point
+func Parse(reader io.Reader) (Input, error) {
+	rawPointInfo, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	points, err := parseInput(rawPointInfo)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{points: points}, nil
+}
+
+// Part1 renders the message the points spell, as rows of '#' and '.'
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (Message, error) {
+	if len(input.points) == 0 {
+		return "", aoc.ErrNoAnswer
+	}
+
+	message, _, err := findMessage(input.points, letterThreshold, budget.Start(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	return Message(renderBoard(message)), nil
+}
+
+// Part2 finds how many seconds it takes for the message to appear
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	if len(input.points) == 0 {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	_, hourCount, err := findMessage(input.points, letterThreshold, budget.Start(ctx))
+
+	return hourCount, err
+}
+
+// Simulate records the points coming together to spell the message, which both parts are solved from
+func Simulate(input Input, part int, recorder replay.Recorder) error {
+	if part < 1 || part > aoc.NumParts {
+		return fmt.Errorf("part %d: %w", part, aoc.ErrUnknownPart)
+	} else if len(input.points) == 0 {
+		return aoc.ErrNoAnswer
+	}
+
+	return recordMessage(input.points, letterThreshold, recorder, aoc.Budget{}.Start(context.Background()))
+}
+
+// Generate makes size points, or two if size is smaller, which spell a message of random dots letterThreshold rows
+// tall. Two of them are on the top and bottom rows of the message, moving apart from each other as fast as any point
+// can, so that the points only fit in fewer than letterThreshold rows once they spell it.
+func Generate(rng *rand.Rand, size int) string {
+	numPoints := max(size, 2)
+	numCols := max(numPoints/pointsPerCol, 1)
+	hours := generate.Between(rng, minHours, maxHours)
+	lines := make([]string, numPoints)
+	for i := range lines {
+		var messagePoint point
+		var pointVelocity velocity
+		switch i {
+		case 0:
+			messagePoint = point{row: 0, col: rng.Intn(numCols)}
+			pointVelocity = velocity{rowVelocity: maxVelocity}
+		case 1:
+			messagePoint = point{row: letterThreshold - 1, col: rng.Intn(numCols)}
+			pointVelocity = velocity{rowVelocity: -maxVelocity}
+		default:
+			messagePoint = point{row: rng.Intn(letterThreshold), col: rng.Intn(numCols)}
+			pointVelocity.rowVelocity = generate.Between(rng, 1, maxVelocity)
+			if rng.Intn(2) == 0 {
+				pointVelocity.rowVelocity *= -1
+			}
+		}
+		pointVelocity.colVelocity = generate.Between(rng, -maxVelocity, maxVelocity)
+
+		// Work back from where the point is in the message to where it starts
+		row := messagePoint.row - pointVelocity.rowVelocity*hours
+		col := messagePoint.col - pointVelocity.colVelocity*hours
+		lines[i] = fmt.Sprintf(generatedFormat, col, row, pointVelocity.colVelocity, pointVelocity.rowVelocity)
+	}
+
+	rng.Shuffle(len(lines), func(i, j int) {
+		lines[i], lines[j] = lines[j], lines[i]
+	})
+
+	return generate.Lines(lines)
+}
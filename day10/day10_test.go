@@ -0,0 +1,130 @@
+package day10
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const example = `position=< 9,  1> velocity=< 0,  2>
+position=< 7,  0> velocity=<-1,  0>
+position=< 3, -2> velocity=<-1,  1>
+position=< 6, 10> velocity=<-2, -1>
+position=< 2, -4> velocity=< 2,  2>
+position=<-6, 10> velocity=< 2, -2>
+position=< 1,  8> velocity=< 1, -1>
+position=< 1,  7> velocity=< 1,  0>
+position=<-3, 11> velocity=< 1, -2>
+position=< 7,  6> velocity=<-1, -1>
+position=<-2,  3> velocity=< 1,  0>
+position=<-4,  3> velocity=< 2,  0>
+position=<10, -3> velocity=<-1,  1>
+position=< 5, 11> velocity=< 1, -2>
+position=< 4,  7> velocity=< 0, -1>
+position=< 8, -2> velocity=< 0,  1>
+position=<15,  0> velocity=<-2,  0>
+position=< 1,  6> velocity=< 1,  0>
+position=< 8,  9> velocity=< 0, -1>
+position=< 3,  3> velocity=<-1,  1>
+position=< 0,  5> velocity=< 0, -1>
+position=<-2,  2> velocity=< 2,  0>
+position=< 5, -2> velocity=< 1,  2>
+position=< 1,  4> velocity=< 2,  1>
+position=<-2,  7> velocity=< 2, -2>
+position=< 3,  6> velocity=<-1, -1>
+position=< 5,  0> velocity=< 1,  0>
+position=<-6,  0> velocity=< 2,  0>
+position=< 5,  9> velocity=< 1, -2>
+position=<14,  7> velocity=<-2,  0>
+position=<-3,  6> velocity=< 2, -1>
+`
+
+const exampleMessage = `#...#..###
+#...#...#.
+#...#...#.
+#####...#.
+#...#...#.
+#...#...#.
+#...#...#.
+#...#..###`
+
+func TestFindMessage(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The example's letters are shorter than the real puzzle's
+	message, hourCount, err := findMessage(input.points, 8, aoc.Budget{}.Start(context.Background()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := renderBoard(message); got != exampleMessage {
+		t.Errorf("got message\n%s\nwant\n%s", got, exampleMessage)
+	}
+	if hourCount != 3 {
+		t.Errorf("got %d hours, want 3", hourCount)
+	}
+}
+
+func TestPointsThatNeverMeet(t *testing.T) {
+	input, err := Parse(strings.NewReader("position=<0, 0> velocity=<0, -1>\nposition=<0, 20> velocity=<0, 1>\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Part2(context.Background(), input, aoc.Budget{MaxSteps: 50}); !errors.Is(err, aoc.ErrOutOfSteps) {
+		t.Errorf("got error %v, want %v", err, aoc.ErrOutOfSteps)
+	}
+}
+
+func TestRecordMessage(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tape := replay.NewTape()
+	if err := recordMessage(input.points, 8, tape, aoc.Budget{}.Start(context.Background())); err != nil {
+		t.Fatal(err)
+	}
+
+	// The points already fit in recordRows, so are recorded from the start until a few hours after the message
+	if want := 3 + hoursAfterMessage + 1; tape.Len() != want {
+		t.Fatalf("got %d frames, want %d", tape.Len(), want)
+	}
+
+	frame := tape.Frame(3)
+	if frame.Caption != "hour 3" {
+		t.Errorf("got caption %q, want %q", frame.Caption, "hour 3")
+	} else if got, want := grid.Count(frame.Cells, pointChar), strings.Count(exampleMessage, "#"); got != want {
+		t.Errorf("got %d points in the message's frame, want %d", got, want)
+	}
+}
+
+func TestMessageJSON(t *testing.T) {
+	got, err := json.Marshal(Message("#.#\n.#."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `["#.#",".#."]`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 10)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 10)
+}
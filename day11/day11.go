@@ -0,0 +1,207 @@
+// Package day11 solves the puzzle for day 11 of Advent of Code 2018
+package day11
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const gridSize = 300
+
+// maxSerialNumber is the largest serial number a generated input has
+const maxSerialNumber = 9999
+
+type summedAreaTable [][]int
+
+// Make a table where each location represents the scores up and to the left
+func makeSummedAreaTable(serialNumber int) summedAreaTable {
+	table := make([][]int, gridSize)
+	for row := range table {
+		table[row] = make([]int, gridSize)
+		for col := range table[row] {
+			score := getTileScore(row+1, col+1, serialNumber)
+			if row-1 >= 0 && col-1 >= 0 {
+				score += table[row-1][col] + table[row][col-1] - table[row-1][col-1]
+			} else if row-1 >= 0 {
+				score += table[row-1][col]
+			} else if col-1 >= 0 {
+				score += table[row][col-1]
+			}
+			table[row][col] = score
+		}
+	}
+
+	return table
+}
+
+func getTileScore(row int, col int, serialNumber int) int {
+	rackID := col + 10
+	powerLevel := rackID * (rackID*row + serialNumber)
+	powerLevel = powerLevel / 100 % 10
+	return powerLevel - 5
+}
+
+func getSquareScore(areaTable summedAreaTable, startRow int, startCol int, size int, serialNumber int) (score int) {
+	bottomRightRow := startRow + (size - 1)
+	bottomRightCol := startCol + (size - 1)
+	if bottomRightCol >= gridSize || bottomRightRow >= gridSize {
+		return 0
+	}
+
+	bottomRightScore := areaTable[bottomRightRow][bottomRightCol]
+
+	leftBoundRow := bottomRightRow
+	leftBoundCol := startCol - 1
+	leftBoundScore := 0
+	if leftBoundRow >= 0 && leftBoundCol >= 0 {
+		leftBoundScore = areaTable[leftBoundRow][leftBoundCol]
+	}
+
+	topBoundRow := startRow - 1
+	topBoundCol := bottomRightCol
+	topBoundScore := 0
+	if topBoundRow >= 0 && topBoundCol >= 0 {
+		topBoundScore = areaTable[topBoundRow][topBoundCol]
+	}
+
+	topLeftBoundScore := 0
+	if startRow > 0 && startCol > 0 {
+		topLeftBoundScore = areaTable[startRow-1][startCol-1]
+	}
+
+	/*
+	 * cxxb
+	 * xxxx
+	 * xxxx
+	 * dxxa
+	 * Perform a - b - c + d; the +d eliminates double counting form the other two subtractions
+	 */
+	return bottomRightScore - leftBoundScore - topBoundScore + topLeftBoundScore
+}
+
+// part1 finds the top left of the 3x3 square with the most power, taking a step for each row
+func part1(areaTable summedAreaTable, serialNumber int, steps *aoc.Steps) (bestRow int, bestCol int, err error) {
+	maxScore := 0
+	for i := 1; i < gridSize; i++ {
+		if err := steps.Take(); err != nil {
+			return 0, 0, err
+		}
+
+		for j := 1; j < gridSize; j++ {
+			score := getSquareScore(areaTable, i-1, j-1, 3, serialNumber)
+			if score > maxScore {
+				maxScore = score
+				bestRow = i
+				bestCol = j
+			}
+		}
+	}
+
+	return
+}
+
+// part2 finds the top left and size of the square with the most power, taking a step for each size
+func part2(areaTable summedAreaTable, serialNumber int, steps *aoc.Steps) (bestRow int, bestCol int, bestSize int, err error) {
+	maxScore := 0
+	for squareSize := 1; squareSize <= gridSize; squareSize++ {
+		if err := steps.Take(); err != nil {
+			return 0, 0, 0, err
+		}
+
+		for i := 1; i < gridSize; i++ {
+			for j := 1; j < gridSize; j++ {
+				score := getSquareScore(areaTable, i-1, j-1, squareSize, serialNumber)
+				if score > maxScore {
+					maxScore = score
+					bestRow = i
+					bestCol = j
+					bestSize = squareSize
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func init() {
+	aoc.Register(11, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(int), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(int), budget)
+		},
+	})
+	generate.Register(11, generate.Generator{DefaultSize: 1, Generate: Generate})
+}
+
+// Point is the top-left fuel cell of a 3x3 square
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+// Square is a square of fuel cells, identified by its top-left fuel cell and its size
+type Square struct {
+	X    int `json:"x"`
+	Y    int `json:"y"`
+	Size int `json:"size"`
+}
+
+func (s Square) String() string {
+	return fmt.Sprintf("%d,%d,%d", s.X, s.Y, s.Size)
+}
+
+// Parse parses the grid's serial number
+func Parse(reader io.Reader) (int, error) {
+	rawSerialNumber, err := aoc.ReadString(reader)
+	if err != nil {
+		return 0, err
+	}
+
+	serialNumber, err := strconv.Atoi(rawSerialNumber)
+	if err != nil {
+		return 0, aoc.NewParseError(11, 0, -1, rawSerialNumber, err)
+	}
+
+	return serialNumber, nil
+}
+
+// Part1 finds the 3x3 square with the largest total power
+func Part1(ctx context.Context, serialNumber int, budget aoc.Budget) (Point, error) {
+	areaTable := makeSummedAreaTable(serialNumber)
+	bestRow, bestCol, err := part1(areaTable, serialNumber, budget.Start(ctx))
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: bestCol, Y: bestRow}, nil
+}
+
+// Part2 finds the square of any size with the largest total power
+func Part2(ctx context.Context, serialNumber int, budget aoc.Budget) (Square, error) {
+	areaTable := makeSummedAreaTable(serialNumber)
+	bestRow, bestCol, bestSize, err := part2(areaTable, serialNumber, budget.Start(ctx))
+	if err != nil {
+		return Square{}, err
+	}
+
+	return Square{X: bestCol, Y: bestRow, Size: bestSize}, nil
+}
+
+// Generate makes a serial number. The grid is always the same size, so size is ignored.
+func Generate(rng *rand.Rand, size int) string {
+	return strconv.Itoa(generate.Between(rng, 1, maxSerialNumber)) + "\n"
+}
@@ -0,0 +1,77 @@
+package day11
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestGetTileScore(t *testing.T) {
+	tests := []struct {
+		x, y, serialNumber int
+		want               int
+	}{
+		{3, 5, 8, 4},
+		{122, 79, 57, -5},
+		{217, 196, 39, 0},
+		{101, 153, 71, 4},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d,%d serial %d", tt.x, tt.y, tt.serialNumber)
+		t.Run(name, func(t *testing.T) {
+			if got := getTileScore(tt.y, tt.x, tt.serialNumber); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		serialNumber int
+		want         Point
+	}{
+		{18, Point{X: 33, Y: 45}},
+		{42, Point{X: 21, Y: 61}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("serial %d", tt.serialNumber), func(t *testing.T) {
+			got, err := Part1(context.Background(), tt.serialNumber, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		serialNumber int
+		want         Square
+	}{
+		{18, Square{X: 90, Y: 269, Size: 16}},
+		{42, Square{X: 232, Y: 251, Size: 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("serial %d", tt.serialNumber), func(t *testing.T) {
+			got, err := Part2(context.Background(), tt.serialNumber, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkExample(b *testing.B) {
+	aoctest.BenchmarkInput(b, 11, "18")
+}
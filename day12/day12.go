@@ -0,0 +1,225 @@
+// Package day12 solves the puzzle for day 12 of Advent of Code 2018
+package day12
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/cycle"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const (
+	initialStateDelim = ": "
+	stateDelim        = " => "
+	liveChar          = '#'
+	deadChar          = '.'
+	part1Steps        = 20
+	part2Steps        = 50000000000
+	// patternLength is the number of pots that decide whether a pot has a plant in the next generation
+	patternLength = 5
+	// maxSettleGenerations is how many generations the plants of a generated input must settle into a pattern within
+	maxSettleGenerations = 1000
+)
+
+var errMissingNotes = errors.New("input ends before the notes")
+
+func parseInput(inputLines []string) (string, map[string]bool, error) {
+	if len(inputLines) < 2 {
+		// Point just past the end of the input, where the notes should be
+		return "", nil, aoc.NewParseError(12, len(inputLines), -1, "", errMissingNotes)
+	}
+
+	states := make(map[string]bool, len(inputLines)-2)
+	initialStateComponents := strings.Split(inputLines[0], initialStateDelim)
+	if len(initialStateComponents) != 2 {
+		return "", nil, aoc.NewParseError(12, 0, -1, inputLines[0], aoc.ErrMalformedInput)
+	}
+
+	initialState := initialStateComponents[1]
+	stateColumn := len(initialStateComponents[0]) + len(initialStateDelim)
+	if badPot := strings.IndexFunc(initialState, isNotPot); badPot != -1 {
+		return "", nil, aoc.NewParseError(12, 0, stateColumn+badPot, inputLines[0], aoc.ErrMalformedInput)
+	}
+
+	for i, line := range inputLines[2:] {
+		lineIndex := i + 2
+		lineComponents := strings.Split(line, stateDelim)
+		if len(lineComponents) != 2 || len(lineComponents[1]) != 1 {
+			return "", nil, aoc.NewParseError(12, lineIndex, -1, line, aoc.ErrMalformedInput)
+		} else if badPot := strings.IndexFunc(lineComponents[0], isNotPot); badPot != -1 {
+			return "", nil, aoc.NewParseError(12, lineIndex, badPot, line, aoc.ErrMalformedInput)
+		}
+
+		resultChar := lineComponents[1][0]
+		if resultChar == liveChar || resultChar == deadChar {
+			states[lineComponents[0]] = (resultChar == liveChar)
+		} else {
+			resultColumn := len(lineComponents[0]) + len(stateDelim)
+			return "", nil, aoc.NewParseError(12, lineIndex, resultColumn, line, aoc.ErrMalformedInput)
+		}
+	}
+
+	return initialState, states, nil
+}
+
+// isNotPot checks if a character is neither a pot with a plant nor an empty pot
+func isNotPot(char rune) bool {
+	return char != liveChar && char != deadChar
+}
+
+// runStep calculates the new step and returns the new string and the number of pots added to the left
+func runStep(state string, states map[string]bool) (string, int) {
+	// Add .... to either side - this ensures we cover the case of ....# and #....
+	parseState := "...." + state + "...."
+	finalState := bytes.NewBufferString("")
+	firstPotPos := -1
+	for i := range parseState[2 : len(parseState)-2] {
+		parseComponent := parseState[i : i+5]
+		if states[parseComponent] {
+			finalState.WriteRune(liveChar)
+			if firstPotPos == -1 {
+				firstPotPos = i + 2
+			}
+		} else if firstPotPos != -1 {
+			finalState.WriteRune(deadChar)
+		}
+	}
+
+	trimmedFinalState := strings.TrimRight(finalState.String(), ".")
+	return trimmedFinalState, 4 - firstPotPos
+}
+
+// getStateScore gets the score of the current state given current state and the index of the starting pot
+func getStateScore(currentState string, startingPotPos int) (score int) {
+	for i, char := range currentState {
+		if char == liveChar {
+			score += i - startingPotPos
+		}
+	}
+
+	return
+}
+
+// generation is the state of the pots after some number of generations
+type generation struct {
+	pots string
+	// zeroIndex is the index into pots of pot number 0
+	zeroIndex int
+}
+
+func (g generation) next(states map[string]bool) generation {
+	pots, leftPots := runStep(g.pots, states)
+
+	return generation{pots: pots, zeroIndex: g.zeroIndex + leftPots}
+}
+
+// sumAfter finds the sum of the numbers of every pot containing a plant after the given number of generations.
+// The plants eventually settle into a pattern that repeats while drifting along the row, so once that happens, the rest
+// of the generations can be skipped by moving the pattern by the drift of every cycle that is skipped. A step is taken
+// for each generation grown, as rules that never settle into a pattern would leave all fifty billion to grow.
+func sumAfter(initialState string, states map[string]bool, numSteps int, steps *aoc.Steps) (int, error) {
+	history := cycle.NewHistory(func(g generation) string { return g.pots })
+	current := generation{pots: initialState}
+	for step := 0; step < numSteps; step++ {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		if repeat, ok := history.Add(current); ok {
+			equivalentStep, cycles := repeat.Reduce(numSteps)
+			drift := current.zeroIndex - history.State(repeat.Start).zeroIndex
+			final := history.State(equivalentStep)
+			final.zeroIndex += cycles * drift
+
+			return getStateScore(final.pots, final.zeroIndex), nil
+		}
+
+		current = current.next(states)
+	}
+
+	return getStateScore(current.pots, current.zeroIndex), nil
+}
+
+func init() {
+	aoc.Register(12, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(12, generate.Generator{Size: "pots", DefaultSize: 100, Generate: Generate})
+}
+
+// Input is the initial state of the pots, along with the notes on which patterns produce a plant
+type Input struct {
+	initialState string
+	states       map[string]bool
+}
+
+// Parse parses the initial state and the notes
+func Parse(reader io.Reader) (Input, error) {
+	inputLines, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	initialState, states, err := parseInput(inputLines)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{initialState: initialState, states: states}, nil
+}
+
+// Part1 finds the sum of the numbers of every pot containing a plant after 20 generations
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return sumAfter(input.initialState, input.states, part1Steps, budget.Start(ctx))
+}
+
+// Part2 finds the sum of the numbers of every pot containing a plant after fifty billion generations
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return sumAfter(input.initialState, input.states, part2Steps, budget.Start(ctx))
+}
+
+// Generate makes an initial state of size pots, along with a note for every pattern. Like the real notes, an empty
+// pattern never grows a plant, and the plants settle into a repeating pattern, as the notes are made again until they do.
+func Generate(rng *rand.Rand, size int) string {
+	initialState := make([]byte, size)
+	for i := range initialState {
+		initialState[i] = deadChar
+		if rng.Intn(2) == 0 {
+			initialState[i] = liveChar
+		}
+	}
+
+	toPots := strings.NewReplacer("0", string(deadChar), "1", string(liveChar))
+	for {
+		states := map[string]bool{}
+		lines := []string{"initial state" + initialStateDelim + string(initialState), ""}
+		for pattern := 0; pattern < 1<<patternLength; pattern++ {
+			rawPattern := toPots.Replace(fmt.Sprintf("%0*b", patternLength, pattern))
+			states[rawPattern] = pattern != 0 && rng.Intn(2) == 0
+			result := deadChar
+			if states[rawPattern] {
+				result = liveChar
+			}
+
+			lines = append(lines, rawPattern+stateDelim+string(result))
+		}
+
+		steps := aoc.Budget{MaxSteps: maxSettleGenerations}.Start(context.Background())
+		if _, err := sumAfter(string(initialState), states, part2Steps, steps); err == nil {
+			return generate.Lines(lines)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package day12
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = `initial state: #..#.#..##......###...###
+
+...## => #
+..#.. => #
+.#... => #
+.#.#. => #
+.#.## => #
+.##.. => #
+.#### => #
+#.#.# => #
+#.### => #
+##.#. => #
+##.## => #
+###.. => #
+###.# => #
+####. => #
+`
+
+func TestPart1(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), input, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 325 {
+		t.Errorf("got %d, want 325", got)
+	}
+}
+
+func TestParseMissingNotes(t *testing.T) {
+	if _, err := Parse(strings.NewReader("initial state: #..#\n")); err == nil {
+		t.Error("expected an error for input without any notes")
+	}
+}
+
+func TestSumAfterSkipsCycles(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Step through every generation, to check against the sum found by skipping the repeats
+	current := generation{pots: input.initialState}
+	for step := 1; step <= 500; step++ {
+		current = current.next(input.states)
+		if step%100 != 0 {
+			continue
+		}
+
+		want := getStateScore(current.pots, current.zeroIndex)
+		got, err := sumAfter(input.initialState, input.states, step, aoc.Budget{}.Start(context.Background()))
+		if err != nil {
+			t.Fatal(err)
+		} else if got != want {
+			t.Errorf("got %d after %d generations, want %d", got, step, want)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 12)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 12)
+}
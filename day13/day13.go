@@ -0,0 +1,777 @@
+// Package day13 solves the puzzle for day 13 of Advent of Code 2018
+package day13
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+type cartDirection int
+type trackDirection int
+
+// implements sort.Interface
+type cartSet []cart
+
+const (
+	blankTileChar         = ' '
+	horizontalTrackChar   = '-'
+	verticalTrackChar     = '|'
+	curveUpTrackChar      = '/'
+	curveDownTrackChar    = '\\'
+	intersectionTrackChar = '+'
+	upCartChar            = '^'
+	leftCartChar          = '<'
+	downCartChar          = 'v'
+	rightCartChar         = '>'
+	crashChar             = 'X'
+)
+
+// palette colours the track, the carts, and any crashes between them
+var palette = replay.Palette{
+	horizontalTrackChar:   {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	verticalTrackChar:     {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	curveUpTrackChar:      {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	curveDownTrackChar:    {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	intersectionTrackChar: {R: 0xc0, G: 0xc0, B: 0xc0, A: 0xff},
+	upCartChar:            {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	leftCartChar:          {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	downCartChar:          {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	rightCartChar:         {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	crashChar:             {R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+}
+
+var errDisconnectedTrack = errors.New("track is not connected to the track before it")
+
+const (
+	// minGeneratedCarts is the fewest carts a generated input has, so that there is a crash and a cart left after it
+	minGeneratedCarts = 3
+	// loopSpacing is how many rows and columns of the map there are for each generated loop of track
+	loopSpacing = 8
+	// ticksPerRow is how many ticks, for each row of the map, the carts on a generated map have to crash in
+	ticksPerRow = 100
+)
+
+// loop is a rectangular loop of track, given by the rows and columns of its corners
+type loop struct {
+	top, bottom, left, right int
+}
+
+const (
+	upDirection cartDirection = iota
+	rightDirection
+	downDirection
+	leftDirection
+)
+
+const (
+	verticalDirection trackDirection = iota
+	horizontalDirection
+	intersectionDirection
+	// We define up and down for these constants as what will happen when you encounter these types of curves as you are going to the RIGHT
+	curveUpDirection
+	curveDownDirection
+)
+
+type cart struct {
+	row, col          int
+	currentTrack      *track
+	direction         cartDirection
+	willTurn          bool
+	nextTurnDirection cartDirection
+}
+
+type track struct {
+	row, col  int
+	direction trackDirection
+	neighbors []*track
+}
+
+func makeCart(row int, col int, direction cartDirection, currentTrack *track) cart {
+	return cart{
+		row:               row,
+		col:               col,
+		direction:         direction,
+		currentTrack:      currentTrack,
+		willTurn:          true,
+		nextTurnDirection: leftDirection,
+	}
+}
+
+func makeTrack(row int, col int, direction trackDirection) *track {
+	return &track{
+		row:       row,
+		col:       col,
+		direction: direction,
+		// all tracks must have at least two neighbors
+		neighbors: make([]*track, 0, 2),
+	}
+}
+
+func (c *cart) turnAtIntersection() {
+	if c.willTurn && c.nextTurnDirection == leftDirection {
+		c.nextTurnDirection = rightDirection
+		if c.direction == upDirection {
+			c.direction = leftDirection
+		} else if c.direction == rightDirection {
+			c.direction = upDirection
+		} else if c.direction == downDirection {
+			c.direction = rightDirection
+		} else if c.direction == leftDirection {
+			c.direction = downDirection
+		}
+		c.willTurn = false
+	} else if c.willTurn && c.nextTurnDirection == rightDirection {
+		c.nextTurnDirection = leftDirection
+		if c.direction == upDirection {
+			c.direction = rightDirection
+		} else if c.direction == rightDirection {
+			c.direction = downDirection
+		} else if c.direction == downDirection {
+			c.direction = leftDirection
+		} else if c.direction == leftDirection {
+			c.direction = upDirection
+		}
+	} else if !c.willTurn {
+		c.willTurn = true
+	}
+}
+
+// turnAtCurve turns at a curve, and returns true if it could, false otherwise.
+func (c *cart) turnAtCurve() {
+	if c.direction == upDirection && c.currentTrack.direction == curveUpDirection {
+		c.direction = rightDirection
+	} else if c.direction == upDirection && c.currentTrack.direction == curveDownDirection {
+		c.direction = leftDirection
+	} else if c.direction == rightDirection && c.currentTrack.direction == curveUpDirection {
+		c.direction = upDirection
+	} else if c.direction == rightDirection && c.currentTrack.direction == curveDownDirection {
+		c.direction = downDirection
+	} else if c.direction == downDirection && c.currentTrack.direction == curveUpDirection {
+		c.direction = leftDirection
+	} else if c.direction == downDirection && c.currentTrack.direction == curveDownDirection {
+		c.direction = rightDirection
+	} else if c.direction == leftDirection && c.currentTrack.direction == curveUpDirection {
+		c.direction = downDirection
+	} else if c.direction == leftDirection && c.currentTrack.direction == curveDownDirection {
+		c.direction = upDirection
+	}
+}
+
+func (c *cart) move() {
+	if c.direction == upDirection {
+		c.row--
+	} else if c.direction == downDirection {
+		c.row++
+	} else if c.direction == leftDirection {
+		c.col--
+	} else if c.direction == rightDirection {
+		c.col++
+	}
+	for _, neighborTrack := range c.currentTrack.neighbors {
+		if neighborTrack.row == c.row && neighborTrack.col == c.col {
+			c.currentTrack = neighborTrack
+		}
+	}
+}
+
+func (c *cart) char() rune {
+	switch c.direction {
+	case upDirection:
+		return upCartChar
+	case leftDirection:
+		return leftCartChar
+	case downDirection:
+		return downCartChar
+	default:
+		return rightCartChar
+	}
+}
+
+// clone copies the set, so the carts can be moved without modifying the original
+func (set cartSet) clone() cartSet {
+	return append(cartSet{}, set...)
+}
+
+func (set cartSet) Len() int {
+	return len(set)
+}
+
+func (set cartSet) Less(i int, j int) bool {
+	if set[i].row == set[j].row {
+		return set[i].col < set[j].col
+	}
+
+	return set[i].row < set[j].row
+}
+
+func (set cartSet) Swap(i int, j int) {
+	set[i], set[j] = set[j], set[i]
+}
+
+// identifyTile works out what a tile on the map holds. ok is false if the tile is not a known piece of track.
+func identifyTile(tile rune) (isCart bool, cartTravelDirection cartDirection, direction trackDirection, ok bool) {
+	ok = true
+	if tile == horizontalTrackChar {
+		direction = horizontalDirection
+	} else if tile == verticalTrackChar {
+		direction = verticalDirection
+	} else if tile == curveUpTrackChar {
+		direction = curveUpDirection
+	} else if tile == curveDownTrackChar {
+		direction = curveDownDirection
+	} else if tile == upCartChar {
+		isCart = true
+		cartTravelDirection = upDirection
+		direction = verticalDirection
+	} else if tile == intersectionTrackChar {
+		direction = intersectionDirection
+	} else if tile == downCartChar {
+		isCart = true
+		cartTravelDirection = downDirection
+		direction = verticalDirection
+	} else if tile == leftCartChar {
+		isCart = true
+		cartTravelDirection = leftDirection
+		direction = horizontalDirection
+	} else if tile == rightCartChar {
+		isCart = true
+		cartTravelDirection = rightDirection
+		direction = horizontalDirection
+	} else {
+		ok = false
+	}
+
+	return
+}
+
+// crosses checks if either loop has a horizontal side that crosses a vertical side of the other
+func (l loop) crosses(other loop) bool {
+	between := func(n int, low int, high int) bool {
+		return n > low && n < high
+	}
+	crossesVertical := func(horizontal loop, vertical loop) bool {
+		return (between(horizontal.top, vertical.top, vertical.bottom) || between(horizontal.bottom, vertical.top, vertical.bottom)) &&
+			(between(vertical.left, horizontal.left, horizontal.right) || between(vertical.right, horizontal.left, horizontal.right))
+	}
+
+	return crossesVertical(l, other) || crossesVertical(other, l)
+}
+
+// draw draws the loop onto the map, with an intersection wherever it crosses a loop that is already drawn
+func (l loop) draw(tiles [][]byte) {
+	for col := l.left + 1; col < l.right; col++ {
+		for _, row := range []int{l.top, l.bottom} {
+			if tiles[row][col] == verticalTrackChar {
+				tiles[row][col] = intersectionTrackChar
+			} else {
+				tiles[row][col] = horizontalTrackChar
+			}
+		}
+	}
+
+	for row := l.top + 1; row < l.bottom; row++ {
+		for _, col := range []int{l.left, l.right} {
+			if tiles[row][col] == horizontalTrackChar {
+				tiles[row][col] = intersectionTrackChar
+			} else {
+				tiles[row][col] = verticalTrackChar
+			}
+		}
+	}
+
+	tiles[l.top][l.left] = curveUpTrackChar
+	tiles[l.top][l.right] = curveDownTrackChar
+	tiles[l.bottom][l.left] = curveDownTrackChar
+	tiles[l.bottom][l.right] = curveUpTrackChar
+}
+
+func parseTracks(rawTracks []string) (cartSet, error) {
+	carts := make(cartSet, 0)
+	width := 0
+	for _, rawTrack := range rawTracks {
+		if len(rawTrack) > width {
+			width = len(rawTrack)
+		}
+	}
+
+	previousRowTracks := make([]*track, width)
+	for row := range rawTracks {
+		// The first track on a line cannot possibly be horizontal, unless the track were open.
+		var lastTrack *track
+		for col, tile := range rawTracks[row] {
+			if tile == blankTileChar {
+				lastTrack = nil
+				previousRowTracks[col] = nil
+				continue
+			}
+
+			haveCart, cartTravelDrection, direction, ok := identifyTile(tile)
+			if !ok {
+				return nil, aoc.NewParseError(13, row, col, rawTracks[row], aoc.ErrMalformedInput)
+			}
+
+			newTrack := makeTrack(row, col, direction)
+			aboveTrack := previousRowTracks[col]
+			if (direction == horizontalDirection && lastTrack == nil) || (direction == verticalDirection && aboveTrack == nil) {
+				return nil, aoc.NewParseError(13, row, col, rawTracks[row], errDisconnectedTrack)
+			} else if direction == horizontalDirection {
+				lastTrack.neighbors = append(lastTrack.neighbors, newTrack)
+				newTrack.neighbors = append(newTrack.neighbors, lastTrack)
+			} else if direction == verticalDirection {
+				aboveTrack.neighbors = append(aboveTrack.neighbors, newTrack)
+				newTrack.neighbors = append(newTrack.neighbors, aboveTrack)
+			} else {
+				// handle the \--, --/, and + cases
+				if aboveTrack != nil {
+					aboveTrack.neighbors = append(aboveTrack.neighbors, newTrack)
+					newTrack.neighbors = append(newTrack.neighbors, aboveTrack)
+				}
+				if lastTrack != nil {
+					lastTrack.neighbors = append(lastTrack.neighbors, newTrack)
+					newTrack.neighbors = append(newTrack.neighbors, lastTrack)
+				}
+			}
+
+			lastTrack = newTrack
+			previousRowTracks[col] = newTrack
+
+			if haveCart {
+				newCart := makeCart(row, col, cartTravelDrection, newTrack)
+				carts = append(carts, newCart)
+			}
+		}
+
+		// Nothing can be below the end of a shorter line
+		for col := len(rawTracks[row]); col < width; col++ {
+			previousRowTracks[col] = nil
+		}
+	}
+
+	return carts, nil
+}
+
+// drawTracks draws the tracks without any carts on them, so that the carts can be drawn over them as they move
+func drawTracks(rawTracks []string) *grid.Dense[rune] {
+	width := 0
+	for _, rawTrack := range rawTracks {
+		width = max(width, len(rawTrack))
+	}
+
+	tracks := grid.NewDense[rune](grid.RectOf(len(rawTracks), width))
+	for pos := range tracks.Bounds().Points() {
+		tracks.Set(pos, blankTileChar)
+	}
+
+	for row, rawTrack := range rawTracks {
+		for col, tile := range rawTrack {
+			// Carts always start on straight track, going along it
+			switch tile {
+			case upCartChar, downCartChar:
+				tile = verticalTrackChar
+			case leftCartChar, rightCartChar:
+				tile = horizontalTrackChar
+			}
+
+			tracks.Set(grid.Point{Row: row, Col: col}, tile)
+		}
+	}
+
+	return tracks
+}
+
+// recordTick records the carts on the tracks after a tick, marking where any carts crashed during it
+func recordTick(recorder replay.Recorder, tracks *grid.Dense[rune], carts cartSet, crashes []grid.Point, tick int) {
+	cells := tracks.Clone()
+	numCarts := 0
+	for i := range carts {
+		// skip zero valued carts - indicates they've been collided
+		if carts[i] == (cart{}) {
+			continue
+		}
+
+		cells.Set(grid.Point{Row: carts[i].row, Col: carts[i].col}, carts[i].char())
+		numCarts++
+	}
+
+	for _, crash := range crashes {
+		cells.Set(crash, crashChar)
+	}
+
+	recorder.Record(replay.Frame{
+		Caption: fmt.Sprintf("tick %d: %d carts, %d crashes", tick, numCarts, len(crashes)),
+		Cells:   cells,
+	})
+}
+
+// getCollidedPair returns the indices of the carts that collided
+func getCollidedPair(carts cartSet) (int, int) {
+	for i := range carts {
+		for j := range carts {
+			// Skip any pair that has already been removed for a collision
+			if carts[i] == (cart{}) || carts[j] == (cart{}) {
+				continue
+			}
+
+			if j != i && carts[i].row == carts[j].row && carts[i].col == carts[j].col {
+				return i, j
+			}
+		}
+	}
+
+	return -1, -1
+}
+
+// Run a single tick of the simulation - callback will give the indices of the collided carts. It should return whether or not the tick should continue after the collision
+func runTick(carts cartSet, collisionCallback func(int, int) bool) {
+	for i := range carts {
+		// skip zero valued carts - indicates they've been collided
+		if carts[i] == (cart{}) {
+			continue
+		}
+
+		if carts[i].currentTrack.direction == intersectionDirection {
+			carts[i].turnAtIntersection()
+		}
+		carts[i].move()
+		carts[i].turnAtCurve()
+		collidedCart1, collidedCart2 := getCollidedPair(carts)
+		if collidedCart1 != -1 && collidedCart2 != -1 {
+			shouldBreak := collisionCallback(collidedCart1, collidedCart2)
+			if shouldBreak {
+				break
+			}
+		}
+	}
+}
+
+// part1 finds the row and column of the first crash, taking a step for each tick. If a recorder is given, the carts
+// are recorded on the tracks before they start, and after every tick.
+func part1(carts cartSet, tracks *grid.Dense[rune], recorder replay.Recorder, steps *aoc.Steps) (int, int, error) {
+	cartsAreCollided := false
+	var collidedRow, collidedCol int
+	if recorder != nil {
+		recordTick(recorder, tracks, carts, nil, 0)
+	}
+
+	for tick := 1; !cartsAreCollided; tick++ {
+		// Carts on tracks that never cross can go around them forever
+		if err := steps.Take(); err != nil {
+			return 0, 0, err
+		}
+
+		sort.Sort(carts)
+		// Run a single tick of the simulation
+		runTick(carts, func(collidedCart1 int, collidedCart2 int) bool {
+			cartsAreCollided = true
+			collidedRow, collidedCol = carts[collidedCart1].row, carts[collidedCart2].col
+			return true
+		})
+
+		if recorder != nil {
+			var crashes []grid.Point
+			if cartsAreCollided {
+				crashes = []grid.Point{{Row: collidedRow, Col: collidedCol}}
+			}
+
+			recordTick(recorder, tracks, carts, crashes, tick)
+		}
+	}
+
+	return collidedRow, collidedCol, nil
+}
+
+// part2 finds the row and column of the last cart left, taking a step for each tick. If a recorder is given, the carts
+// are recorded on the tracks before they start, and after every tick.
+func part2(carts cartSet, tracks *grid.Dense[rune], recorder replay.Recorder, steps *aoc.Steps) (int, int, error) {
+	if recorder != nil {
+		recordTick(recorder, tracks, carts, nil, 0)
+	}
+
+	for tick := 1; len(carts) > 1; tick++ {
+		if err := steps.Take(); err != nil {
+			return 0, 0, err
+		}
+
+		collidedCarts := make([]int, 0, len(carts))
+		crashes := []grid.Point{}
+		sort.Sort(carts)
+		// Run a single tick of the simulation
+		runTick(carts, func(collidedCart1 int, collidedCart2 int) bool {
+			collidedCarts = append(collidedCarts, collidedCart1, collidedCart2)
+			crashes = append(crashes, grid.Point{Row: carts[collidedCart1].row, Col: carts[collidedCart1].col})
+			carts[collidedCart1] = cart{}
+			carts[collidedCart2] = cart{}
+			return false
+		})
+
+		newCartSet := make(cartSet, 0, len(carts)-len(collidedCarts))
+		// Check if this cart has been collided, and added it to the new cart set if not
+		for i := range carts {
+			inCollidedSet := false
+			for _, collidedIndex := range collidedCarts {
+				if i == collidedIndex {
+					inCollidedSet = true
+					break
+				}
+			}
+			if !inCollidedSet {
+				newCartSet = append(newCartSet, carts[i])
+			}
+		}
+		collidedCarts = collidedCarts[:0]
+		carts = newCartSet
+		if recorder != nil {
+			recordTick(recorder, tracks, carts, crashes, tick)
+		}
+	}
+
+	return carts[0].row, carts[0].col, nil
+}
+
+func init() {
+	aoc.Register(13, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(13, generate.Generator{Size: "carts", DefaultSize: 17, Generate: Generate})
+	replay.Register(13, replay.Simulation{
+		Palette: palette,
+		Run: func(input interface{}, part int, recorder replay.Recorder) error {
+			return Simulate(input.(Input), part, recorder)
+		},
+	})
+}
+
+// Input is the set of carts, each of which knows the tracks it is on, along with a drawing of the tracks
+type Input struct {
+	carts  cartSet
+	tracks *grid.Dense[rune]
+}
+
+// Point is a location on the tracks
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+// Parse parses the map of the tracks and the carts on them
+func Parse(reader io.Reader) (Input, error) {
+	rawTracks, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	carts, err := parseTracks(rawTracks)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{carts: carts, tracks: drawTracks(rawTracks)}, nil
+}
+
+// Part1 finds the location of the first crash
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (Point, error) {
+	// With fewer than two carts, nothing can crash
+	if len(input.carts) < 2 {
+		return Point{}, aoc.ErrNoAnswer
+	}
+
+	collidedRow, collidedCol, err := part1(input.carts.clone(), input.tracks, nil, budget.Start(ctx))
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: collidedCol, Y: collidedRow}, nil
+}
+
+// Part2 finds the location of the last cart once every other cart has crashed
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (Point, error) {
+	// Carts crash in pairs, so there can only be a last cart if there are an odd number of them
+	if len(input.carts)%2 == 0 {
+		return Point{}, aoc.ErrNoAnswer
+	}
+
+	finalRow, finalCol, err := part2(input.carts.clone(), input.tracks, nil, budget.Start(ctx))
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: finalCol, Y: finalRow}, nil
+}
+
+// Simulate runs the carts the way a part is solved, recording them on the tracks after every tick
+func Simulate(input Input, part int, recorder replay.Recorder) error {
+	steps := aoc.Budget{}.Start(context.Background())
+	var err error
+	switch part {
+	case 1:
+		if len(input.carts) < 2 {
+			return aoc.ErrNoAnswer
+		}
+
+		_, _, err = part1(input.carts.clone(), input.tracks, recorder, steps)
+	case 2:
+		if len(input.carts)%2 == 0 {
+			return aoc.ErrNoAnswer
+		}
+
+		_, _, err = part2(input.carts.clone(), input.tracks, recorder, steps)
+	default:
+		return fmt.Errorf("part %d: %w", part, aoc.ErrUnknownPart)
+	}
+
+	return err
+}
+
+// Generate makes a map with size carts on it, rounded up to an odd number that is at least three, so that both parts
+// have an answer. The track is made of rectangular loops, each crossing one before it, and no two loops share a row or
+// column for their sides, so they only ever meet at intersections. Maps whose carts don't all crash within a few
+// ticks for each row are made again.
+func Generate(rng *rand.Rand, size int) string {
+	numCarts := max(size, minGeneratedCarts) | 1
+	numLoops := max(int(2*math.Sqrt(float64(numCarts))), 2)
+	mapSize := numLoops * loopSpacing
+	for {
+		rawTracks, ok := generateTracks(rng, numLoops, mapSize, numCarts)
+		if !ok {
+			continue
+		}
+
+		carts, err := parseTracks(rawTracks)
+		if err != nil {
+			panic(fmt.Sprintf("generated tracks do not parse: %s", err))
+		}
+
+		tracks := drawTracks(rawTracks)
+		steps := aoc.Budget{MaxSteps: ticksPerRow * mapSize}.Start(context.Background())
+		if _, _, err := part1(carts.clone(), tracks, nil, steps); err != nil {
+			continue
+		}
+
+		steps = aoc.Budget{MaxSteps: ticksPerRow * mapSize}.Start(context.Background())
+		if _, _, err := part2(carts.clone(), tracks, nil, steps); err != nil {
+			continue
+		}
+
+		return generate.Lines(rawTracks)
+	}
+}
+
+// generateTracks draws numLoops loops on a square map mapSize tiles wide, and puts numCarts carts on their straight
+// pieces of track, returning false if there isn't enough straight track for them
+func generateTracks(rng *rand.Rand, numLoops int, mapSize int, numCarts int) ([]string, bool) {
+	usedRows := map[int]bool{}
+	usedCols := map[int]bool{}
+	// pickSides picks two unused rows or columns, with at least one between them for straight track
+	pickSides := func(used map[int]bool) (int, int, bool) {
+		a, b := rng.Intn(mapSize), rng.Intn(mapSize)
+		if used[a] || used[b] || a-b < 2 && b-a < 2 {
+			return 0, 0, false
+		}
+
+		return min(a, b), max(a, b), true
+	}
+
+	loops := []loop{}
+	for len(loops) < numLoops {
+		top, bottom, ok := pickSides(usedRows)
+		if !ok {
+			continue
+		}
+
+		left, right, ok := pickSides(usedCols)
+		if !ok {
+			continue
+		}
+
+		candidate := loop{top: top, bottom: bottom, left: left, right: right}
+		crossesAny := len(loops) == 0
+		for _, other := range loops {
+			crossesAny = crossesAny || candidate.crosses(other)
+		}
+
+		if !crossesAny {
+			continue
+		}
+
+		loops = append(loops, candidate)
+		usedRows[top], usedRows[bottom] = true, true
+		usedCols[left], usedCols[right] = true, true
+	}
+
+	tiles := make([][]byte, mapSize)
+	for row := range tiles {
+		tiles[row] = []byte(strings.Repeat(string(blankTileChar), mapSize))
+	}
+
+	for _, l := range loops {
+		l.draw(tiles)
+	}
+
+	straightTrack := []grid.Point{}
+	for row := range tiles {
+		for col, tile := range tiles[row] {
+			if tile == horizontalTrackChar || tile == verticalTrackChar {
+				straightTrack = append(straightTrack, grid.Point{Row: row, Col: col})
+			}
+		}
+	}
+
+	if len(straightTrack) < numCarts {
+		return nil, false
+	}
+
+	for _, i := range rng.Perm(len(straightTrack))[:numCarts] {
+		pos := straightTrack[i]
+		forwards := rng.Intn(2) == 0
+		switch {
+		case tiles[pos.Row][pos.Col] == horizontalTrackChar && forwards:
+			tiles[pos.Row][pos.Col] = rightCartChar
+		case tiles[pos.Row][pos.Col] == horizontalTrackChar:
+			tiles[pos.Row][pos.Col] = leftCartChar
+		case forwards:
+			tiles[pos.Row][pos.Col] = downCartChar
+		default:
+			tiles[pos.Row][pos.Col] = upCartChar
+		}
+	}
+
+	rawTracks := make([]string, mapSize)
+	width := 0
+	for row := range tiles {
+		rawTracks[row] = strings.TrimRight(string(tiles[row]), string(blankTileChar))
+		width = max(width, len(rawTracks[row]))
+	}
+
+	// Leave out the rows below the last loop
+	for rawTracks[len(rawTracks)-1] == "" {
+		rawTracks = rawTracks[:len(rawTracks)-1]
+	}
+
+	// Leave out the columns right of the last loop too, but keep every row as wide as the map, like the real inputs
+	for row, rawTrack := range rawTracks {
+		rawTracks[row] = rawTrack + strings.Repeat(string(blankTileChar), width-len(rawTrack))
+	}
+
+	return rawTracks, true
+}
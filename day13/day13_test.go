@@ -0,0 +1,122 @@
+package day13
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const part1Example = `/->-\        
+|   |  /----\
+| /-+--+-\  |
+| | |  | v  |
+\-+-/  \-+--/
+  \------/   
+`
+
+const part2Example = `/>-<\  
+|   |  
+| /<+-\
+| | | v
+\>+</ |
+  |   ^
+  \<->/
+`
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		part  func(context.Context, Input, aoc.Budget) (Point, error)
+		want  Point
+	}{
+		{"part1", part1Example, Part1, Point{X: 7, Y: 3}},
+		{"part2", part2Example, Part2, Point{X: 6, Y: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.part(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoAnswer(t *testing.T) {
+	input, err := Parse(strings.NewReader("/>\\\n\\-/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Part1(context.Background(), input, aoc.Budget{}); err != aoc.ErrNoAnswer {
+		t.Errorf("got error %v with one cart, want %v", err, aoc.ErrNoAnswer)
+	}
+}
+
+func TestCartsThatNeverCrash(t *testing.T) {
+	// Each cart has a loop of track to itself
+	input, err := Parse(strings.NewReader("/>\\ /<\\\n\\-/ \\-/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Part1(context.Background(), input, aoc.Budget{MaxSteps: 100}); !errors.Is(err, aoc.ErrOutOfSteps) {
+		t.Errorf("got error %v, want %v", err, aoc.ErrOutOfSteps)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	input, err := Parse(strings.NewReader(part1Example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tape := replay.NewTape()
+	if err := Simulate(input, 1, tape); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first crash is on the fourteenth tick, at 7,3
+	if tape.Len() != 15 {
+		t.Fatalf("got %d frames, want 15", tape.Len())
+	} else if got := tape.Frame(14).Cells.Get(grid.Point{Row: 3, Col: 7}); got != crashChar {
+		t.Errorf("got %q at the crash, want %q", got, crashChar)
+	} else if got := tape.Frame(0).Cells.Get(grid.Point{Row: 0, Col: 2}); got != rightCartChar {
+		t.Errorf("got %q where the first cart starts, want %q", got, rightCartChar)
+	}
+}
+
+func TestGenerateIsRectangular(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		lines := strings.Split(strings.TrimSuffix(Generate(rand.New(rand.NewSource(seed)), 5), "\n"), "\n")
+		for row, line := range lines {
+			if len(line) != len(lines[0]) {
+				t.Errorf("seed %d: line %d is %d wide, want %d like the first", seed, row+1, len(line), len(lines[0]))
+			}
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 13)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 13)
+}
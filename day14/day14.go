@@ -0,0 +1,185 @@
+// Package day14 solves the puzzle for day 14 of Advent of Code 2018
+package day14
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const (
+	score1 = 3
+	score2 = 7
+	// maxDigits is the most digits a generated input can have while still fitting in an int
+	maxDigits = 18
+)
+
+func calculateNewScores(scores []int, elf1Cursor int, elf2Cursor int) []int {
+	total := scores[elf1Cursor] + scores[elf2Cursor]
+	newScores := make([]int, 0, 2)
+	if total == 0 {
+		newScores = append(newScores, 0)
+	} else {
+		for total != 0 {
+			newScores = append([]int{total % 10}, newScores...)
+			total /= 10
+		}
+	}
+
+	return newScores
+}
+
+func makeStringOfIntSlice(slice []int) string {
+	buffer := bytes.NewBufferString("")
+	for _, item := range slice {
+		buffer.WriteString(strconv.Itoa(item))
+	}
+
+	return buffer.String()
+}
+
+func makeSolutionSlice(solutionString string) []int {
+	solution := make([]int, len(solutionString))
+	for i, char := range solutionString {
+		solution[i] = int(char - '0')
+	}
+
+	return solution
+}
+
+func compareIntSlices(a []int, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// part1 finds the ten scores after numScores recipes, taking a step for each round of new recipes
+func part1(numScores int, steps *aoc.Steps) (string, error) {
+	scores := make([]int, 2, numScores+10)
+	scores[0] = score1
+	scores[1] = score2
+	elf1Cursor := 0
+	elf2Cursor := 1
+	for len(scores) < numScores+10 {
+		if err := steps.Take(); err != nil {
+			return "", err
+		}
+
+		scores = append(scores, calculateNewScores(scores, elf1Cursor, elf2Cursor)...)
+		elf1Score := scores[elf1Cursor]
+		elf2Score := scores[elf2Cursor]
+		elf1Cursor = (elf1Cursor + elf1Score + 1) % len(scores)
+		elf2Cursor = (elf2Cursor + elf2Score + 1) % len(scores)
+	}
+
+	resultString := ""
+	for i := numScores; i < numScores+10; i++ {
+		resultString += strconv.Itoa(scores[i])
+	}
+
+	return makeStringOfIntSlice(scores[numScores : numScores+10]), nil
+}
+
+// part2 finds how many recipes come before the digits of scoreString, taking a step for each round of new recipes, as
+// the digits may never appear
+func part2(scoreString string, steps *aoc.Steps) (int, error) {
+	scores := make([]int, 2)
+	scores[0] = score1
+	scores[1] = score2
+	elf1Cursor := 0
+	elf2Cursor := 1
+	itemIndex := -1
+	solutionSlice := makeSolutionSlice(scoreString)
+	for itemIndex == -1 {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		newScores := calculateNewScores(scores, elf1Cursor, elf2Cursor)
+		// We must loop through each new score in order to make sure that we handle the cases of double digits
+		for _, newScore := range newScores {
+			scores = append(scores, newScore)
+			if len(scores) < len(solutionSlice) {
+				continue
+			}
+
+			// If the last len(solutionSlice) digits are the same sa our solution slice, we're done
+			if compareIntSlices(scores[len(scores)-len(solutionSlice):], solutionSlice) {
+				itemIndex = len(scores) - len(solutionSlice)
+				break
+			}
+		}
+		elf1Score := scores[elf1Cursor]
+		elf2Score := scores[elf2Cursor]
+		elf1Cursor = (elf1Cursor + elf1Score + 1) % len(scores)
+		elf2Cursor = (elf2Cursor + elf2Score + 1) % len(scores)
+	}
+
+	return itemIndex, nil
+}
+
+func init() {
+	aoc.Register(14, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(string), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(string), budget)
+		},
+	})
+	generate.Register(14, generate.Generator{Size: "digits", DefaultSize: 6, Generate: Generate})
+}
+
+// Parse parses the puzzle input, which is a string of digits
+func Parse(reader io.Reader) (string, error) {
+	rawInput, err := aoc.ReadString(reader)
+	if err != nil {
+		return "", err
+	}
+
+	// Make sure the input is a valid number, even though part 2 only needs its digits
+	_, err = strconv.ParseUint(rawInput, 10, 0)
+	if err != nil {
+		return "", aoc.NewParseError(14, 0, -1, rawInput, err)
+	}
+
+	return rawInput, nil
+}
+
+// Part1 finds the scores of the ten recipes after the number of recipes given by the input
+func Part1(ctx context.Context, input string, budget aoc.Budget) (string, error) {
+	numScores, err := strconv.Atoi(input)
+	if err != nil {
+		return "", err
+	}
+
+	return part1(numScores, budget.Start(ctx))
+}
+
+// Part2 finds how many recipes appear before the input's digits first appear as scores
+func Part2(ctx context.Context, input string, budget aoc.Budget) (int, error) {
+	return part2(input, budget.Start(ctx))
+}
+
+// Generate makes a number with size digits, or maxDigits if size is larger, that doesn't start with a zero. Each extra
+// digit makes both parts take around ten times longer.
+func Generate(rng *rand.Rand, size int) string {
+	var digits bytes.Buffer
+	digits.WriteByte(byte('1' + rng.Intn(9)))
+	for i := 1; i < min(size, maxDigits); i++ {
+		digits.WriteByte(byte('0' + rng.Intn(10)))
+	}
+	digits.WriteByte('\n')
+
+	return digits.String()
+}
@@ -0,0 +1,59 @@
+package day14
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		numRecipes string
+		want       string
+	}{
+		{"9", "5158916779"},
+		{"5", "0124515891"},
+		{"18", "9251071085"},
+		{"2018", "5941429882"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.numRecipes, func(t *testing.T) {
+			got, err := Part1(context.Background(), tt.numRecipes, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		scores string
+		want   int
+	}{
+		{"51589", 9},
+		{"01245", 5},
+		{"92510", 18},
+		{"59414", 2018},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scores, func(t *testing.T) {
+			got, err := Part2(context.Background(), tt.scores, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkExample(b *testing.B) {
+	aoctest.BenchmarkInput(b, 14, "59414")
+}
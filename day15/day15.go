@@ -0,0 +1,567 @@
+// Package day15 solves the puzzle for day 15 of Advent of Code 2018
+package day15
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+	"github.com/ollien/advent-of-code-2018/search"
+)
+
+const (
+	startingHealth  = 200
+	baseAttackPower = 3
+	wallChar        = '#'
+	openChar        = '.'
+	elfChar         = 'E'
+	goblinChar      = 'G'
+)
+
+const (
+	// minCaveSize is the narrowest a generated cave can be, leaving room for some open cavern inside its walls
+	minCaveSize = 5
+	// wallChance is one in how many tiles inside a generated cave are walls
+	wallChance = 4
+	// tilesPerUnit is roughly how many open tiles a generated cave has for each unit
+	tilesPerUnit = 20
+)
+
+// palette colours the walls, the open cavern, and each side
+var palette = replay.Palette{
+	wallChar:   {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	openChar:   {R: 0x44, G: 0x44, B: 0x44, A: 0xff},
+	elfChar:    {R: 0x32, G: 0xcd, B: 0x32, A: 0xff},
+	goblinChar: {R: 0xdc, G: 0x14, B: 0x3c, A: 0xff},
+}
+
+const (
+	noWinner winner = iota
+	elfWinner
+	goblinWinner
+)
+
+type board struct {
+	tiles *grid.Dense[node]
+}
+type winner int
+
+// a list of nodes, sortable in reading order
+type nodeList []node
+
+type node interface {
+	setPos(grid.Point)
+	getPos() grid.Point
+	canTravelThrough() bool
+}
+
+type tile struct {
+	position grid.Point
+	isWall   bool
+}
+
+type entity struct {
+	position    grid.Point
+	attackPower int
+	health      int
+	isGoblin    bool
+}
+
+func (list nodeList) Len() int {
+	return len(list)
+}
+
+func (list nodeList) Less(i int, j int) bool {
+	return grid.ReadingLess(list[i].getPos(), list[j].getPos())
+}
+
+func (list nodeList) Swap(i int, j int) {
+	list[i], list[j] = list[j], list[i]
+}
+
+func (t *tile) setPos(newPos grid.Point) {
+	t.position = newPos
+}
+
+func (t *tile) getPos() grid.Point {
+	return t.position
+}
+
+func (t *tile) canTravelThrough() bool {
+	return !t.isWall
+}
+
+func (e *entity) setPos(newPos grid.Point) {
+	e.position = newPos
+}
+
+func (e *entity) getPos() grid.Point {
+	return e.position
+}
+
+func (e *entity) canTravelThrough() bool {
+	return false
+}
+
+// move moves the entity one step along the shortest path to the nearest square next to an enemy.
+// Ties, both between squares and between paths to them, are broken in reading order.
+func (e *entity) move(containingBoard board) {
+	fromEntity := search.BFS(e.position, containingBoard.getOpenNeighbors, nil)
+	target, foundTarget := e.findTarget(containingBoard, fromEntity)
+	if !foundTarget {
+		return
+	}
+
+	// Take the first step, in reading order, that is as close as possible to the target
+	fromTarget := search.BFS(target, containingBoard.getOpenNeighbors, nil)
+	var moveNode node
+	bestDistance := math.MaxInt32
+	for _, neighbor := range containingBoard.getOpenNeighbors(e.position) {
+		if distance, ok := fromTarget.Distance(neighbor); ok && distance < bestDistance {
+			bestDistance = distance
+			moveNode = containingBoard.tiles.Get(neighbor)
+		}
+	}
+
+	newPos := moveNode.getPos()
+	oldPos := e.position
+	e.setPos(newPos)
+	moveNode.setPos(oldPos)
+	containingBoard.tiles.Set(oldPos, moveNode)
+	containingBoard.tiles.Set(newPos, e)
+}
+
+// findTarget finds the nearest open square next to an enemy that the entity can reach, given the distances to
+// every square from the entity
+func (e *entity) findTarget(containingBoard board, fromEntity *search.Result[grid.Point]) (grid.Point, bool) {
+	var target grid.Point
+	foundTarget := false
+	bestDistance := math.MaxInt32
+	for pos, boardNode := range containingBoard.tiles.All() {
+		if enemy, isEntity := boardNode.(*entity); !isEntity || enemy.isGoblin == e.isGoblin {
+			continue
+		}
+
+		for _, inRange := range containingBoard.getOpenNeighbors(pos) {
+			distance, reachable := fromEntity.Distance(inRange)
+			if !reachable {
+				continue
+			}
+
+			if distance < bestDistance || (distance == bestDistance && grid.ReadingLess(inRange, target)) {
+				target = inRange
+				bestDistance = distance
+				foundTarget = true
+			}
+		}
+	}
+
+	return target, foundTarget
+}
+
+func (e *entity) attack(containingBoard board) bool {
+	lowestHealthTarget := &entity{health: math.MaxInt32}
+	neighbors := containingBoard.getNeighbors(e.getPos())
+	sort.Sort(neighbors)
+	for _, neighbor := range neighbors {
+		if entityNode, isEntity := neighbor.(*entity); isEntity && entityNode.isGoblin != e.isGoblin {
+			if entityNode.health < lowestHealthTarget.health {
+				lowestHealthTarget = entityNode
+			}
+		}
+	}
+	if lowestHealthTarget.health == math.MaxInt32 {
+		return false
+	}
+
+	lowestHealthTarget.health -= e.attackPower
+	if lowestHealthTarget.health <= 0 {
+		entityPos := lowestHealthTarget.getPos()
+		containingBoard.tiles.Set(entityPos, &tile{
+			position: entityPos,
+			isWall:   false,
+		})
+	}
+
+	return true
+}
+
+// frame draws the board as a frame, along with how many of each side are left
+func (b board) frame(round int) replay.Frame {
+	elves, goblins := 0, 0
+	cells := grid.Map(b.tiles, func(boardNode node) rune {
+		switch n := boardNode.(type) {
+		case *entity:
+			if n.isGoblin {
+				goblins++
+				return goblinChar
+			}
+
+			elves++
+			return elfChar
+		default:
+			if n.canTravelThrough() {
+				return openChar
+			}
+
+			return wallChar
+		}
+	})
+
+	return replay.Frame{
+		Caption: fmt.Sprintf("round %d: %d elves, %d goblins", round, elves, goblins),
+		Cells:   cells,
+	}
+}
+
+func (b board) getNeighbors(pos grid.Point) nodeList {
+	neighborPositions := grid.Neighbors4[node](b.tiles, pos)
+	neighbors := make(nodeList, len(neighborPositions))
+	for i, neighborPos := range neighborPositions {
+		neighbors[i] = b.tiles.Get(neighborPos)
+	}
+
+	return neighbors
+}
+
+// getOpenNeighbors gets the positions next to pos that can be moved into, in reading order
+func (b board) getOpenNeighbors(pos grid.Point) []grid.Point {
+	neighbors := grid.Neighbors4[node](b.tiles, pos)
+	open := neighbors[:0]
+	for _, neighbor := range neighbors {
+		if b.tiles.Get(neighbor).canTravelThrough() {
+			open = append(open, neighbor)
+		}
+	}
+
+	return open
+}
+
+func (b board) getWinner() winner {
+	currentWinner := noWinner
+	for _, memberNode := range b.tiles.All() {
+		if entityNode, isEntity := memberNode.(*entity); isEntity {
+			if currentWinner == goblinWinner && !entityNode.isGoblin {
+				return noWinner
+			} else if currentWinner == elfWinner && entityNode.isGoblin {
+				return noWinner
+			} else if currentWinner == noWinner && entityNode.isGoblin {
+				currentWinner = goblinWinner
+			} else if currentWinner == noWinner && !entityNode.isGoblin {
+				currentWinner = elfWinner
+			}
+		}
+	}
+
+	return currentWinner
+}
+
+func (b board) clone() (board, nodeList) {
+	entities := nodeList{}
+	newBoard := board{tiles: grid.NewDense[node](b.tiles.Bounds())}
+	for pos, boardNode := range b.tiles.All() {
+		// Every node must be deep copied, as entities change state and tiles are moved around as entities move
+		if entityNode, isEntity := boardNode.(*entity); isEntity {
+			copiedEntity := *entityNode
+			newBoard.tiles.Set(pos, &copiedEntity)
+			entities = append(entities, &copiedEntity)
+		} else {
+			copiedTile := *boardNode.(*tile)
+			newBoard.tiles.Set(pos, &copiedTile)
+		}
+	}
+
+	return newBoard, entities
+}
+
+// parseNode parses a single character of the map. The node's position is set once it is placed on the board.
+func parseNode(char rune) (node, error) {
+	switch char {
+	case wallChar, openChar:
+		return &tile{isWall: char == wallChar}, nil
+	case elfChar, goblinChar:
+		return &entity{
+			attackPower: baseAttackPower,
+			health:      startingHealth,
+			isGoblin:    char == goblinChar,
+		}, nil
+	default:
+		return nil, aoc.ErrMalformedInput
+	}
+}
+
+func parseInput(rawBoard []string) (board, nodeList, error) {
+	tiles, err := grid.ParseDense(rawBoard, parseNode)
+	var cellErr *grid.CellError
+	if errors.As(err, &cellErr) {
+		row := cellErr.Point.Row
+		return board{}, nil, aoc.NewParseError(15, row, cellErr.Point.Col, rawBoard[row], cellErr.Err)
+	} else if err != nil {
+		return board{}, nil, err
+	}
+
+	entities := nodeList{}
+	for pos, boardNode := range tiles.All() {
+		boardNode.setPos(pos)
+		if _, isEntity := boardNode.(*entity); isEntity {
+			entities = append(entities, boardNode)
+		}
+	}
+
+	return board{tiles: tiles}, entities, nil
+}
+
+// runSimulation runs the combat until one side wins, returning the winner and the outcome. A step is taken for each
+// round, as units that can't reach each other would fight forever. If a recorder is given, the board is recorded
+// before the combat and after every round.
+func runSimulation(b board, entities nodeList, recorder replay.Recorder, steps *aoc.Steps) (winner, int, error) {
+	roundCount := 0
+	roundWinner := noWinner
+	if recorder != nil {
+		recorder.Record(b.frame(roundCount))
+	}
+
+	for roundWinner == noWinner {
+		if err := steps.Take(); err != nil {
+			return noWinner, 0, err
+		}
+
+		sort.Sort(entities)
+		finishedRoundEarly := false
+		for _, e := range entities {
+			entityNode := e.(*entity)
+			if entityNode.health <= 0 {
+				continue
+			}
+			if !entityNode.attack(b) {
+				entityNode.move(b)
+				entityNode.attack(b)
+				roundWinner = b.getWinner()
+				if roundWinner != noWinner {
+					finishedRoundEarly = true
+					break
+				}
+			}
+		}
+		if !finishedRoundEarly {
+			roundCount++
+		}
+		if recorder != nil {
+			recorder.Record(b.frame(roundCount))
+		}
+	}
+	healthTotal := 0
+	for _, e := range entities {
+		entityNode := e.(*entity)
+		if entityNode.health > 0 {
+			healthTotal += entityNode.health
+		}
+	}
+
+	return roundWinner, roundCount * healthTotal, nil
+}
+
+func didElfDie(entities nodeList) bool {
+	for _, rawEntity := range entities {
+		entityNode := rawEntity.(*entity)
+		if entityNode.health <= 0 && !entityNode.isGoblin {
+			return true
+		}
+	}
+
+	return false
+}
+
+func part1(b board, entities nodeList, recorder replay.Recorder, steps *aoc.Steps) (int, error) {
+	_, outcome, err := runSimulation(b, entities, recorder, steps)
+
+	return outcome, err
+}
+
+// part2 finds the outcome of the first combat that the elves win without losses, raising their attack power each
+// time. If a recorder is given, every combat is recorded. The rounds of every combat count towards the same steps.
+func part2(b board, recorder replay.Recorder, steps *aoc.Steps) (int, error) {
+	allElvesAlive := false
+	elfAttackPower := baseAttackPower
+	lastOutcome := -1
+	for !allElvesAlive {
+		elfAttackPower++
+		steps.SetStage(fmt.Sprintf("trying an elf attack power of %d", elfAttackPower))
+		roundBoard, roundEntities := b.clone()
+		for i := range roundEntities {
+			if entityNode, isEntity := roundEntities[i].(*entity); isEntity && !entityNode.isGoblin {
+				entityNode.attackPower = elfAttackPower
+			}
+		}
+
+		attackRecorder := replay.Prefix(recorder, fmt.Sprintf("elf attack power %d, ", elfAttackPower))
+		lastWinner, outcome, err := runSimulation(roundBoard, roundEntities, attackRecorder, steps)
+		if err != nil {
+			return 0, err
+		}
+
+		lastOutcome = outcome
+		if lastWinner != elfWinner {
+			continue
+		}
+
+		allElvesAlive = !didElfDie(roundEntities)
+	}
+
+	return lastOutcome, nil
+}
+
+func init() {
+	aoc.Register(15, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+		LongRunning: map[int]bool{2: true},
+	})
+	generate.Register(15, generate.Generator{Size: "tiles along each side of the cave", DefaultSize: 32, Generate: Generate})
+	replay.Register(15, replay.Simulation{
+		Palette: palette,
+		Run: func(input interface{}, part int, recorder replay.Recorder) error {
+			return Simulate(input.(Input), part, recorder)
+		},
+	})
+}
+
+// Input is the map of the cave, with the position of every elf and goblin
+type Input struct {
+	board board
+}
+
+// Parse parses the map of the cave
+func Parse(reader io.Reader) (Input, error) {
+	rawBoard, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	parsedBoard, _, err := parseInput(rawBoard)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{board: parsedBoard}, nil
+}
+
+// Part1 finds the outcome of the combat
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	roundBoard, entities := input.board.clone()
+
+	return part1(roundBoard, entities, nil, budget.Start(ctx))
+}
+
+// Part2 finds the outcome of the combat when the elves have just enough attack power for all of them to survive
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part2(input.board, nil, budget.Start(ctx))
+}
+
+// Simulate runs the combat a part is solved with, recording the cave after every round
+func Simulate(input Input, part int, recorder replay.Recorder) error {
+	steps := aoc.Budget{}.Start(context.Background())
+	switch part {
+	case 1:
+		roundBoard, entities := input.board.clone()
+		_, err := part1(roundBoard, entities, recorder, steps)
+		return err
+	case 2:
+		_, err := part2(input.board, recorder, steps)
+		return err
+	default:
+		return fmt.Errorf("part %d: %w", part, aoc.ErrUnknownPart)
+	}
+}
+
+// Generate makes a square cave size tiles wide, or minCaveSize if size is smaller, with at least one elf and one
+// goblin. The cave is surrounded by walls, and every open tile can be reached from every other, as units that can't
+// reach each other would fight forever.
+func Generate(rng *rand.Rand, size int) string {
+	caveSize := max(size, minCaveSize)
+	for {
+		cave := grid.NewDense[rune](grid.RectOf(caveSize, caveSize))
+		for pos := range cave.Bounds().Points() {
+			onEdge := pos.Row == 0 || pos.Col == 0 || pos.Row == caveSize-1 || pos.Col == caveSize-1
+			if onEdge || rng.Intn(wallChance) == 0 {
+				cave.Set(pos, wallChar)
+			} else {
+				cave.Set(pos, openChar)
+			}
+		}
+
+		openTiles := keepLargestCavern(cave)
+		if len(openTiles) < 2 {
+			continue
+		}
+
+		numUnits := max(len(openTiles)/tilesPerUnit, 2)
+		for i, tile := range rng.Perm(len(openTiles))[:numUnits] {
+			// The first two units are an elf and a goblin, so that there is a fight, and the rest can be either
+			unit := elfChar
+			if i == 1 || i > 1 && rng.Intn(2) == 0 {
+				unit = goblinChar
+			}
+
+			cave.Set(openTiles[tile], unit)
+		}
+
+		return cave.Render(func(tile rune) rune { return tile }) + "\n"
+	}
+}
+
+// keepLargestCavern fills in every open tile that can't reach the largest area of open tiles with wall, and returns
+// the open tiles that are left, in reading order
+func keepLargestCavern(cave *grid.Dense[rune]) []grid.Point {
+	openNeighbors := func(pos grid.Point) []grid.Point {
+		neighbors := []grid.Point{}
+		for _, neighbor := range grid.Neighbors4[rune](cave, pos) {
+			if cave.Get(neighbor) == openChar {
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+
+		return neighbors
+	}
+
+	var largest map[grid.Point]int
+	seen := map[grid.Point]bool{}
+	for pos, tile := range cave.All() {
+		if tile != openChar || seen[pos] {
+			continue
+		}
+
+		cavern := search.BFS(pos, openNeighbors, nil).Distances()
+		for reached := range cavern {
+			seen[reached] = true
+		}
+
+		if len(cavern) > len(largest) {
+			largest = cavern
+		}
+	}
+
+	openTiles := []grid.Point{}
+	for pos, tile := range cave.All() {
+		if _, inLargest := largest[pos]; inLargest {
+			openTiles = append(openTiles, pos)
+		} else if tile == openChar {
+			cave.Set(pos, wallChar)
+		}
+	}
+
+	return openTiles
+}
@@ -0,0 +1,187 @@
+package day15
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+var examples = []struct {
+	name        string
+	cave        string
+	wantOutcome int
+	// wantElvesWin is the outcome when the elves have just enough attack power to all survive, or 0 if the example
+	// doesn't give one
+	wantElvesWin int
+}{
+	{
+		name: "walkthrough",
+		cave: `#######
+#.G...#
+#...EG#
+#.#.#G#
+#..G#E#
+#.....#
+#######`,
+		wantOutcome:  27730,
+		wantElvesWin: 4988,
+	},
+	{
+		name: "goblins win",
+		cave: `#######
+#G..#E#
+#E#E.E#
+#G.##.#
+#...#E#
+#...E.#
+#######`,
+		wantOutcome: 36334,
+	},
+	{
+		name: "elves win",
+		cave: `#######
+#E..EG#
+#.#G.E#
+#E.##E#
+#G..#.#
+#..E#.#
+#######`,
+		wantOutcome:  39514,
+		wantElvesWin: 31284,
+	},
+	{
+		name: "blocked elf",
+		cave: `#######
+#E.G#.#
+#.#G..#
+#G.#.G#
+#G..#.#
+#...E.#
+#######`,
+		wantOutcome:  27755,
+		wantElvesWin: 3478,
+	},
+	{
+		name: "narrow passage",
+		cave: `#######
+#.E...#
+#.#..G#
+#.###.#
+#E#G#G#
+#...#G#
+#######`,
+		wantOutcome:  28944,
+		wantElvesWin: 6474,
+	},
+	{
+		name: "large cave",
+		cave: `#########
+#G......#
+#.E.#...#
+#..##..G#
+#...##..#
+#...#...#
+#.G...G.#
+#.....G.#
+#########`,
+		wantOutcome:  18740,
+		wantElvesWin: 1140,
+	},
+}
+
+func TestPart1(t *testing.T) {
+	for _, tt := range examples {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(tt.cave))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Part1(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.wantOutcome {
+				t.Errorf("got %d, want %d", got, tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestPart2(t *testing.T) {
+	for _, tt := range examples {
+		if tt.wantElvesWin == 0 {
+			continue
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(tt.cave))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Part2(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.wantElvesWin {
+				t.Errorf("got %d, want %d", got, tt.wantElvesWin)
+			}
+		})
+	}
+}
+
+func TestWalledOffCombat(t *testing.T) {
+	// The elf and the goblin can never reach each other
+	input, err := Parse(strings.NewReader("#######\n#E.#.G#\n#######\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Part2(context.Background(), input, aoc.Budget{MaxSteps: 50})
+	var cancelledErr *aoc.CancelledError
+	if !errors.As(err, &cancelledErr) || !errors.Is(err, aoc.ErrOutOfSteps) {
+		t.Fatalf("got error %v, want a CancelledError for running out of steps", err)
+	} else if want := "trying an elf attack power of 4"; cancelledErr.Progress.Stage != want {
+		t.Errorf("got stage %q, want %q", cancelledErr.Progress.Stage, want)
+	}
+}
+
+func TestPartsDoNotModifyInput(t *testing.T) {
+	input, err := Parse(strings.NewReader(examples[0].cave))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := Part1(context.Background(), input, aoc.Budget{})
+	second, _ := Part1(context.Background(), input, aoc.Budget{})
+	if first != second {
+		t.Errorf("solving twice gave %d then %d", first, second)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	input, err := Parse(strings.NewReader(examples[0].cave))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tape := replay.NewTape()
+	if err := Simulate(input, 1, tape); err != nil {
+		t.Fatal(err)
+	}
+
+	// The goblins win during the 48th round, which isn't a full round
+	if tape.Len() != 49 {
+		t.Fatalf("got %d frames, want 49", tape.Len())
+	} else if last := tape.Frame(tape.Len() - 1); last.Caption != "round 47: 0 elves, 4 goblins" {
+		t.Errorf("got caption %q for the last frame", last.Caption)
+	}
+}
+
+func BenchmarkExample(b *testing.B) {
+	aoctest.BenchmarkInput(b, 15, examples[0].cave)
+}
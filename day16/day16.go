@@ -0,0 +1,276 @@
+// Package day16 solves the puzzle for day 16 of Advent of Code 2018
+package day16
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const (
+	beforeFormat      = "Before: [%d, %d, %d, %d]"
+	instructionFormat = "%d %d %d %d"
+	afterFormat       = "After: [%d, %d, %d, %d]"
+	// generatedAfterFormat lines up the registers after an instruction with the ones before it, like the real input
+	generatedAfterFormat = "After:  [%d, %d, %d, %d]"
+)
+
+const (
+	numRegisters = 4
+	// maxAssignments is the number of opcode assignments to report if the notes are ambiguous
+	maxAssignments = 1000
+	// maxGeneratedValue is the largest value held in a generated sample's registers, or given as an operand
+	maxGeneratedValue = 3
+)
+
+type instruction [4]int
+
+// note is a sample of the device from the first part of the input
+type note = elfcode.Sample
+
+func parseInput(input []string) ([]note, []instruction, error) {
+	notes, programStart, err := parsePart1Input(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	instructions, err := parsePart2Input(input, programStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return notes, instructions, nil
+}
+
+// parsePart1Input parses the notes at the start of the input, returning the index of the line the test program starts on
+func parsePart1Input(rawNotes []string) ([]note, int, error) {
+	lastLineLength := -1
+	notes := []note{}
+	currentNote := note{}
+	lastIndex := -1
+	for i, line := range rawNotes {
+		lastIndex = i
+		if lastLineLength == 0 && len(line) == 0 {
+			break
+		}
+		lastLineLength = len(line)
+		if lastLineLength == 0 {
+			continue
+		}
+
+		registers := elfcode.NewRegisters(numRegisters)
+		var ins instruction
+
+		numMatched, err := fmt.Sscanf(line, beforeFormat, &registers[0], &registers[1], &registers[2], &registers[3])
+		if err == nil && numMatched != 4 {
+			return nil, 0, aoc.NewParseError(16, i, -1, line, aoc.ErrMalformedInput)
+		} else if err == nil {
+			currentNote.Before = registers
+			continue
+		}
+
+		numMatched, err = fmt.Sscanf(line, instructionFormat, &ins[0], &ins[1], &ins[2], &ins[3])
+		if err == nil && numMatched != 4 {
+			return nil, 0, aoc.NewParseError(16, i, -1, line, aoc.ErrMalformedInput)
+		} else if err == nil {
+			currentNote.Instruction = ins
+			continue
+		}
+
+		numMatched, err = fmt.Sscanf(line, afterFormat, &registers[0], &registers[1], &registers[2], &registers[3])
+		if err == nil && numMatched != 4 {
+			return nil, 0, aoc.NewParseError(16, i, -1, line, aoc.ErrMalformedInput)
+		} else if err == nil {
+			currentNote.After = registers
+			notes = append(notes, currentNote)
+		} else {
+			// If we have an error at this point, something is actually wrong.
+			return nil, 0, aoc.NewParseError(16, i, -1, line, err)
+		}
+	}
+
+	// The program is separated from the notes by two blank lines, though there may be no program at all
+	programStart := lastIndex + 2
+	if programStart > len(rawNotes) {
+		programStart = len(rawNotes)
+	}
+
+	return notes, programStart, nil
+}
+
+// parsePart2Input parses the test program, which starts on the given line of the input
+func parsePart2Input(rawInput []string, programStart int) ([]instruction, error) {
+	instructions := make([]instruction, 0, len(rawInput)-programStart)
+	for i := programStart; i < len(rawInput); i++ {
+		line := rawInput[i]
+		var ins instruction
+		numMatched, err := fmt.Sscanf(line, instructionFormat, &ins[0], &ins[1], &ins[2], &ins[3])
+		if err != nil {
+			return nil, aoc.NewParseError(16, i, -1, line, err)
+		} else if numMatched != 4 {
+			return nil, aoc.NewParseError(16, i, -1, line, aoc.ErrMalformedInput)
+		}
+		instructions = append(instructions, ins)
+	}
+
+	return instructions, nil
+}
+
+// part1 finds the number of notes that match three or more opcodes
+func part1(notes []note, steps *aoc.Steps) (int, error) {
+	total := 0
+	for _, note := range notes {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		if len(note.MatchingOpcodes()) >= 3 {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+// getOpcodes works out which opcode each opcode number represents. This fails if the notes don't narrow it down to exactly one possibility.
+func getOpcodes(notes []note) (elfcode.Assignment, error) {
+	return elfcode.InferOpcodes(notes, maxAssignments).Assignment()
+}
+
+// part2 runs the test program, taking a step for each instruction run, as the program may never halt
+func part2(instructions []instruction, opcodes elfcode.Assignment, steps *aoc.Steps) (int, error) {
+	program := elfcode.Program{
+		IPRegister:   elfcode.NoIPRegister,
+		Instructions: make([]elfcode.Instruction, len(instructions)),
+	}
+	for i, rawInstruction := range instructions {
+		opcode, ok := opcodes[rawInstruction[0]]
+		if !ok {
+			return 0, fmt.Errorf("opcode %d does not appear in any notes", rawInstruction[0])
+		}
+		program.Instructions[i] = elfcode.Instruction{Op: opcode, A: rawInstruction[1], B: rawInstruction[2], C: rawInstruction[3]}
+	}
+
+	machine, err := elfcode.NewMachine(program, numRegisters)
+	if err != nil {
+		return 0, err
+	}
+	for !machine.Halted() {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		machine.Step()
+	}
+
+	return machine.Registers[0], nil
+}
+
+func init() {
+	aoc.Register(16, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(16, generate.Generator{Size: "samples", DefaultSize: 800, Generate: Generate})
+}
+
+// Input is the samples from the manual, along with the test program
+type Input struct {
+	notes        []note
+	instructions []instruction
+}
+
+// Parse parses the samples and the test program
+func Parse(reader io.Reader) (Input, error) {
+	rawNotes, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	notes, instructions, err := parseInput(rawNotes)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{notes: notes, instructions: instructions}, nil
+}
+
+// Part1 finds how many samples behave like three or more opcodes
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part1(input.notes, budget.Start(ctx))
+}
+
+// Part2 works out every opcode's number from the samples, and finds the value in register 0 after running the test program
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	opcodes, err := getOpcodes(input.notes)
+	if err != nil {
+		return 0, err
+	}
+
+	return part2(input.instructions, opcodes, budget.Start(ctx))
+}
+
+// Generate makes size samples, followed by a test program of size instructions. Every opcode number has a sample, and
+// more samples are added until they narrow the opcodes down to exactly one possibility. The operands of every
+// instruction are valid registers, so the test program can always be run.
+func Generate(rng *rand.Rand, size int) string {
+	opcodeNumbers := rng.Perm(int(elfcode.NumOpcodes))
+	randomInstruction := func(op elfcode.Opcode) instruction {
+		return instruction{
+			opcodeNumbers[op],
+			rng.Intn(maxGeneratedValue + 1),
+			rng.Intn(maxGeneratedValue + 1),
+			rng.Intn(maxGeneratedValue + 1),
+		}
+	}
+
+	lines := []string{}
+	notes := []note{}
+	addNote := func(op elfcode.Opcode) {
+		sample := note{Before: elfcode.NewRegisters(numRegisters), Instruction: randomInstruction(op)}
+		for i := range sample.Before {
+			sample.Before[i] = rng.Intn(maxGeneratedValue + 1)
+		}
+
+		sample.After = sample.Before.Clone()
+		elfcode.Instruction{Op: op, A: sample.Instruction[1], B: sample.Instruction[2], C: sample.Instruction[3]}.Apply(sample.After)
+		notes = append(notes, sample)
+		lines = append(
+			lines,
+			fmt.Sprintf(beforeFormat, sample.Before[0], sample.Before[1], sample.Before[2], sample.Before[3]),
+			fmt.Sprintf(instructionFormat, sample.Instruction[0], sample.Instruction[1], sample.Instruction[2], sample.Instruction[3]),
+			fmt.Sprintf(generatedAfterFormat, sample.After[0], sample.After[1], sample.After[2], sample.After[3]),
+			"",
+		)
+	}
+
+	for op := elfcode.Opcode(0); op < elfcode.NumOpcodes; op++ {
+		addNote(op)
+	}
+
+	for len(notes) < size {
+		addNote(elfcode.Opcode(rng.Intn(int(elfcode.NumOpcodes))))
+	}
+
+	for _, err := getOpcodes(notes); err != nil; _, err = getOpcodes(notes) {
+		addNote(elfcode.Opcode(rng.Intn(int(elfcode.NumOpcodes))))
+	}
+
+	// The test program is separated from the samples by two more blank lines
+	lines = append(lines, "", "")
+	for i := 0; i < size; i++ {
+		ins := randomInstruction(elfcode.Opcode(rng.Intn(int(elfcode.NumOpcodes))))
+		lines = append(lines, fmt.Sprintf(instructionFormat, ins[0], ins[1], ins[2], ins[3]))
+	}
+
+	return generate.Lines(lines)
+}
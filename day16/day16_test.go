@@ -0,0 +1,53 @@
+package day16
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = `Before: [3, 2, 1, 1]
+9 2 1 2
+After:  [3, 2, 2, 1]
+
+
+
+9 2 1 2
+`
+
+func TestPart1(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), input, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+}
+
+func TestPart2Ambiguous(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A single sample can't narrow every opcode down to one possibility
+	if _, err := Part2(context.Background(), input, aoc.Budget{}); err == nil {
+		t.Error("expected an error when the opcodes can't be worked out")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 16)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 16)
+}
@@ -0,0 +1,378 @@
+// Package day17 solves the puzzle for day 17 of Advent of Code 2018
+package day17
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const (
+	xYRangeFormat = "x=%d, y=%d..%d"
+	yXRangeFormat = "y=%d, x=%d..%d"
+	initialCol    = 500
+	sandChar      = '.'
+	clayChar      = '#'
+	flowingChar   = '|'
+	settledChar   = '~'
+	springChar    = '+'
+)
+
+const (
+	// generatedHalfWidth is how far either side of the spring generated clay can be
+	generatedHalfWidth = 150
+	// minGeneratedRows is the fewest rows generated clay is spread over
+	minGeneratedRows = 20
+	// ledgeChance is one in how many generated shapes are a ledge rather than a basin
+	ledgeChance = 5
+	// maxBasinWidth and maxBasinDepth bound the size of a generated basin, or ledge
+	maxBasinWidth = 20
+	maxBasinDepth = 15
+)
+
+// palette colours the sand, clay, water and spring
+var palette = replay.Palette{
+	sandChar:    {R: 0xc2, G: 0xb2, B: 0x80, A: 0xff},
+	clayChar:    {R: 0x8b, G: 0x45, B: 0x13, A: 0xff},
+	flowingChar: {R: 0x87, G: 0xce, B: 0xeb, A: 0xff},
+	settledChar: {R: 0x1e, G: 0x90, B: 0xff, A: 0xff},
+	springChar:  {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+}
+
+const (
+	sandTile tile = iota
+	clayTile
+	flowingTile
+	settledTile
+)
+
+type tile int
+
+// board is the scan of the ground, along with the rows that were scanned, which are the only ones water is counted in
+type board struct {
+	tiles          *grid.Dense[tile]
+	minRow, maxRow int
+	// recorder, if set, records the board every time water moves
+	recorder replay.Recorder
+	// steps counts every row the water spreads along
+	steps *aoc.Steps
+}
+
+// clayVein is a vertical or horizontal line of clay
+type clayVein grid.Rect
+
+func (t tile) char() rune {
+	switch t {
+	case clayTile:
+		return clayChar
+	case flowingTile:
+		return flowingChar
+	case settledTile:
+		return settledChar
+	default:
+		return sandChar
+	}
+}
+
+// isSolid reports whether water can rest on top of the tile
+func (t tile) isSolid() bool {
+	return t == clayTile || t == settledTile
+}
+
+func parseVein(line string) (clayVein, error) {
+	var coord, rangeMin, rangeMax int
+	numMatched, err := fmt.Sscanf(line, yXRangeFormat, &coord, &rangeMin, &rangeMax)
+	if err == nil && numMatched == 3 {
+		return clayVein{Min: grid.Point{Row: coord, Col: rangeMin}, Max: grid.Point{Row: coord + 1, Col: rangeMax + 1}}, nil
+	}
+
+	numMatched, err = fmt.Sscanf(line, xYRangeFormat, &coord, &rangeMin, &rangeMax)
+	if err != nil {
+		return clayVein{}, err
+	} else if numMatched != 3 {
+		return clayVein{}, aoc.ErrMalformedInput
+	}
+
+	return clayVein{Min: grid.Point{Row: rangeMin, Col: coord}, Max: grid.Point{Row: rangeMax + 1, Col: coord + 1}}, nil
+}
+
+// parseInput takes the input for the problem and produces a board with the clay marked on it
+func parseInput(input []string) (board, error) {
+	veins := make([]clayVein, 0, len(input))
+	// The board always covers the spring, so that water can be poured from it
+	bounds := grid.Rect{}.Extend(grid.Point{Row: 0, Col: initialCol})
+	minRow := math.MaxInt32
+	for i, line := range input {
+		vein, err := parseVein(line)
+		if err != nil {
+			return board{}, aoc.NewParseError(17, i, -1, line, err)
+		} else if grid.Rect(vein).Empty() || vein.Min.Row < 0 {
+			return board{}, aoc.NewParseError(17, i, -1, line, aoc.ErrMalformedInput)
+		}
+
+		veins = append(veins, vein)
+		bounds = bounds.Extend(vein.Min).Extend(vein.Max.Add(grid.UpLeft))
+		minRow = min(minRow, vein.Min.Row)
+	}
+
+	// Account for possibility that water flows off edges
+	bounds.Min.Col--
+	bounds.Max.Col++
+
+	tiles := grid.NewDense[tile](bounds)
+	for _, vein := range veins {
+		for pos := range grid.Rect(vein).Points() {
+			tiles.Set(pos, clayTile)
+		}
+	}
+
+	return board{tiles: tiles, minRow: minRow, maxRow: bounds.Max.Row - 1}, nil
+}
+
+// record records the board with the recorder, if there is one, with the spring marked on the top row
+func (b board) record(caption string) {
+	if b.recorder == nil {
+		return
+	}
+
+	cells := grid.Map(b.tiles, tile.char)
+	cells.Set(grid.Point{Row: b.tiles.Bounds().Min.Row, Col: initialCol}, springChar)
+	b.recorder.Record(replay.Frame{Caption: caption, Cells: cells})
+}
+
+// recordFall records water falling from one tile down to another, if it fell at all
+func (b board) recordFall(from, to grid.Point) {
+	if to.Row >= from.Row {
+		b.record(fmt.Sprintf("fell from y=%d to y=%d at x=%d", from.Row, to.Row, from.Col))
+	}
+}
+
+// pour lets water fall from start until it lands on something, and then fills up whatever it lands in.
+// Water that fills up to start's row is left for the caller to spread out, as that row is the caller's.
+func (b board) pour(start grid.Point) error {
+	pos := start
+	for {
+		if !b.tiles.Contains(pos) || b.tiles.Get(pos) != sandTile {
+			// Either the water has left the board, or another stream has already been here
+			b.recordFall(start, pos.Add(grid.Up))
+			return nil
+		}
+
+		b.tiles.Set(pos, flowingTile)
+		below := b.tiles.Get(pos.Add(grid.Down))
+		if below.isSolid() {
+			break
+		} else if below == flowingTile || pos.Row == b.maxRow {
+			b.recordFall(start, pos)
+			return nil
+		}
+
+		pos = pos.Add(grid.Down)
+	}
+
+	b.recordFall(start, pos)
+
+	for {
+		if err := b.steps.Take(); err != nil {
+			return err
+		}
+
+		left, leftHeld := b.spread(pos, grid.Left)
+		right, rightHeld := b.spread(pos, grid.Right)
+		if leftHeld && rightHeld {
+			b.fillRow(left, right, settledTile)
+			if pos.Row == start.Row {
+				return nil
+			}
+
+			pos = pos.Add(grid.Up)
+			continue
+		}
+
+		b.fillRow(left, right, flowingTile)
+		overflowed := false
+		for _, edge := range []struct {
+			pos  grid.Point
+			held bool
+		}{{left, leftHeld}, {right, rightHeld}} {
+			if edge.held {
+				continue
+			}
+
+			if err := b.pour(edge.pos.Add(grid.Down)); err != nil {
+				return err
+			}
+
+			// If the water filled up what it fell into, it may now spread further along this row
+			overflowed = overflowed || b.tiles.Get(edge.pos.Add(grid.Down)).isSolid()
+		}
+
+		if !overflowed {
+			return nil
+		}
+	}
+}
+
+// spread finds how far water at pos spreads in the given direction. It returns the last tile the water reaches, and
+// whether the water is held there by clay, rather than falling off the edge of what it is resting on.
+func (b board) spread(pos grid.Point, dir grid.Point) (grid.Point, bool) {
+	for {
+		if !b.tiles.Get(pos.Add(grid.Down)).isSolid() {
+			return pos, false
+		}
+
+		next := pos.Add(dir)
+		if !b.tiles.Contains(next) {
+			return pos, false
+		} else if b.tiles.Get(next) == clayTile {
+			return pos, true
+		}
+
+		pos = next
+	}
+}
+
+// fillRow sets every tile between left and right, which must be on the same row, to the given tile
+func (b board) fillRow(left, right grid.Point, fill tile) {
+	for col := left.Col; col <= right.Col; col++ {
+		b.tiles.Set(grid.Point{Row: left.Row, Col: col}, fill)
+	}
+
+	if fill == settledTile {
+		b.record(fmt.Sprintf("settled at y=%d, x=%d..%d", left.Row, left.Col, right.Col))
+	} else {
+		b.record(fmt.Sprintf("spread along y=%d, x=%d..%d", left.Row, left.Col, right.Col))
+	}
+}
+
+// flow pours water from the spring onto the clay, and counts how many tiles it reaches, and how many of those it
+// settles in. If a recorder is given, the board is recorded before the water starts, and every time it moves.
+func flow(clayBoard board, recorder replay.Recorder, steps *aoc.Steps) (total int, numStatic int, err error) {
+	waterBoard := clayBoard
+	waterBoard.tiles = clayBoard.tiles.Clone()
+	waterBoard.recorder = recorder
+	waterBoard.steps = steps
+	waterBoard.record("spring turned on")
+	if err := waterBoard.pour(grid.Point{Row: 1, Col: initialCol}); err != nil {
+		return 0, 0, err
+	}
+
+	for pos, t := range waterBoard.tiles.All() {
+		if pos.Row < waterBoard.minRow || pos.Row > waterBoard.maxRow {
+			continue
+		}
+
+		if t == flowingTile || t == settledTile {
+			total++
+		}
+		if t == settledTile {
+			numStatic++
+		}
+	}
+
+	return
+}
+
+func init() {
+	aoc.Register(17, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(17, generate.Generator{Size: "veins of clay", DefaultSize: 1300, Generate: Generate})
+	replay.Register(17, replay.Simulation{
+		Palette: palette,
+		Run: func(input interface{}, part int, recorder replay.Recorder) error {
+			return Simulate(input.(Input), part, recorder)
+		},
+	})
+}
+
+// Input is the scan of where the clay is
+type Input struct {
+	clayBoard board
+}
+
+// Parse parses the scan of the clay
+func Parse(reader io.Reader) (Input, error) {
+	rawTileInfo, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	clayBoard, err := parseInput(rawTileInfo)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{clayBoard: clayBoard}, nil
+}
+
+// Part1 finds how many tiles the water can reach
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	total, _, err := flow(input.clayBoard, nil, budget.Start(ctx))
+
+	return total, err
+}
+
+// Part2 finds how many tiles are left holding water once the spring stops
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	_, numStatic, err := flow(input.clayBoard, nil, budget.Start(ctx))
+
+	return numStatic, err
+}
+
+// Simulate pours the water that both parts are solved from, recording the ground every time the water moves
+func Simulate(input Input, part int, recorder replay.Recorder) error {
+	if part < 1 || part > aoc.NumParts {
+		return fmt.Errorf("part %d: %w", part, aoc.ErrUnknownPart)
+	}
+
+	_, _, err := flow(input.clayBoard, recorder, aoc.Budget{}.Start(context.Background()))
+
+	return err
+}
+
+// Generate makes a scan of size veins of clay, spread below the spring. Most of the veins make up basins, each with a
+// floor, and a wall on either side that may be shorter than the other, and the rest are ledges the water can spread
+// along and fall off.
+func Generate(rng *rand.Rand, size int) string {
+	numRows := max(size*3/2, minGeneratedRows)
+	lines := []string{}
+	for len(lines) < size {
+		left := generate.Between(rng, initialCol-generatedHalfWidth, initialCol+generatedHalfWidth)
+		right := left + generate.Between(rng, 2, maxBasinWidth)
+		bottom := generate.Between(rng, 1, numRows)
+		if rng.Intn(ledgeChance) == 0 {
+			lines = append(lines, fmt.Sprintf(yXRangeFormat, bottom, left, right))
+			continue
+		}
+
+		leftTop := max(bottom-generate.Between(rng, 1, maxBasinDepth), 1)
+		rightTop := max(bottom-generate.Between(rng, 1, maxBasinDepth), 1)
+		lines = append(
+			lines,
+			fmt.Sprintf(xYRangeFormat, left, leftTop, bottom),
+			fmt.Sprintf(xYRangeFormat, right, rightTop, bottom),
+			fmt.Sprintf(yXRangeFormat, bottom, left, right),
+		)
+	}
+
+	lines = lines[:size]
+	rng.Shuffle(len(lines), func(i, j int) {
+		lines[i], lines[j] = lines[j], lines[i]
+	})
+
+	return generate.Lines(lines)
+}
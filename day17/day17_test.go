@@ -0,0 +1,101 @@
+package day17
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const example = `x=495, y=2..7
+y=7, x=495..501
+x=501, y=3..7
+x=498, y=2..4
+x=506, y=1..2
+x=498, y=10..13
+x=504, y=10..13
+y=13, x=498..504
+`
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name string
+		part func(context.Context, Input, aoc.Budget) (int, error)
+		want int
+	}{
+		{"part1", Part1, 57},
+		{"part2", Part2, 29},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(example))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.part(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartsDoNotModifyInput(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := Part1(context.Background(), input, aoc.Budget{})
+	second, _ := Part1(context.Background(), input, aoc.Budget{})
+	if first != second {
+		t.Errorf("solving twice gave %d then %d", first, second)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tape := replay.NewTape()
+	if err := Simulate(input, 1, tape); err != nil {
+		t.Fatal(err)
+	}
+
+	// The water once it has finished flowing, as drawn in the puzzle
+	want := `......+.......
+......|.....#.
+.#..#||||...#.
+.#..#~~#|.....
+.#..#~~#|.....
+.#~~~~~#|.....
+.#~~~~~#|.....
+.#######|.....
+........|.....
+...|||||||||..
+...|#~~~~~#|..
+...|#~~~~~#|..
+...|#~~~~~#|..
+...|#######|..`
+	last := tape.Frame(tape.Len() - 1)
+	if got := last.Cells.Render(func(char rune) rune { return char }); got != want {
+		t.Errorf("last frame was\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 17)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 17)
+}
@@ -0,0 +1,249 @@
+// Package day18 solves the puzzle for day 18 of Advent of Code 2018
+package day18
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/cycle"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const (
+	openChar              = '.'
+	treeChar              = '|'
+	lumberChar            = '#'
+	breedCount            = 3
+	treeFillCount         = 3
+	stationaryRequirement = 1
+	part1Ticks            = 10
+	part2Ticks            = 1000000000
+)
+
+// generatedAcres holds the kinds of acres a generated area is made of, each as often as it appears in the real input
+var generatedAcres = []rune{openChar, openChar, openChar, treeChar, lumberChar}
+
+// palette colours the open ground, trees and lumberyards
+var palette = replay.Palette{
+	openChar:   {R: 0x8b, G: 0x83, B: 0x78, A: 0xff},
+	treeChar:   {R: 0x22, G: 0x8b, B: 0x22, A: 0xff},
+	lumberChar: {R: 0xa0, G: 0x52, B: 0x2d, A: 0xff},
+}
+
+const (
+	openState boardState = iota
+	treeState
+	lumberState
+)
+
+type boardState int
+type board struct {
+	acres *grid.Dense[boardState]
+}
+
+func (state boardState) char() rune {
+	switch state {
+	case treeState:
+		return treeChar
+	case lumberState:
+		return lumberChar
+	default:
+		return openChar
+	}
+}
+
+// frame draws the board as a frame for the given minute
+func (b board) frame(minute int) replay.Frame {
+	return replay.Frame{
+		Caption: fmt.Sprintf("minute %d", minute),
+		Cells:   grid.Map(b.acres, boardState.char),
+	}
+}
+
+func (b board) getValue() int {
+	return grid.Count(b.acres, treeState) * grid.Count(b.acres, lumberState)
+}
+
+func (b board) clone() board {
+	return board{acres: b.acres.Clone()}
+}
+
+func (b board) getAdjacentCounts(pos grid.Point) (trees, lumberyards int) {
+	for _, neighbor := range grid.Neighbors8[boardState](b.acres, pos) {
+		switch b.acres.Get(neighbor) {
+		case treeState:
+			trees++
+		case lumberState:
+			lumberyards++
+		}
+	}
+
+	return
+}
+
+// tick runs a minute of growth, making a new board rather than changing this one
+func (b board) tick() board {
+	next := b.clone()
+	for pos, state := range b.acres.All() {
+		adjacentTrees, adjacentLumberyards := b.getAdjacentCounts(pos)
+		if state == openState && adjacentTrees >= breedCount {
+			next.acres.Set(pos, treeState)
+		} else if state == treeState && adjacentLumberyards >= treeFillCount {
+			next.acres.Set(pos, lumberState)
+		} else if state == lumberState && !(adjacentLumberyards >= stationaryRequirement && adjacentTrees >= stationaryRequirement) {
+			next.acres.Set(pos, openState)
+		}
+	}
+
+	return next
+}
+
+func (b board) hash() uint64 {
+	return b.acres.Hash()
+}
+
+func (b board) isIdentical(b2 board) bool {
+	return b.acres.Equal(b2.acres)
+}
+
+func parseState(char rune) (boardState, error) {
+	switch char {
+	case treeChar:
+		return treeState, nil
+	case lumberChar:
+		return lumberState, nil
+	case openChar:
+		return openState, nil
+	default:
+		return openState, aoc.ErrMalformedInput
+	}
+}
+
+func parseBoard(rawBoard []string) (board, error) {
+	acres, err := grid.ParseDense(rawBoard, parseState)
+	var cellErr *grid.CellError
+	if errors.As(err, &cellErr) {
+		row := cellErr.Point.Row
+		return board{}, aoc.NewParseError(18, row, cellErr.Point.Col, rawBoard[row], cellErr.Err)
+	} else if err != nil {
+		return board{}, err
+	}
+
+	return board{acres: acres}, nil
+}
+
+// runSimulation finds the resource value after the given number of ticks. The area eventually settles into a cycle,
+// so the ticks after the first repeated board can be skipped. A step is taken for each tick that is run. If a recorder
+// is given, the board is recorded after every tick that is run.
+func runSimulation(parsedBoard board, numTicks int, recorder replay.Recorder, steps *aoc.Steps) (int, error) {
+	history := cycle.NewHashedHistory(board.hash, board.isIdentical)
+	minute := 0
+	if recorder != nil {
+		recorder.Record(parsedBoard.frame(minute))
+	}
+
+	finalBoard, err := history.Run(parsedBoard, func(b board) (board, error) {
+		if err := steps.Take(); err != nil {
+			return board{}, err
+		}
+
+		minute++
+		nextBoard := b.tick()
+		if recorder != nil {
+			recorder.Record(nextBoard.frame(minute))
+		}
+
+		return nextBoard, nil
+	}, numTicks)
+	if err != nil {
+		return 0, err
+	}
+
+	return finalBoard.getValue(), nil
+}
+
+func init() {
+	aoc.Register(18, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(18, generate.Generator{Size: "acres along each side of the area", DefaultSize: 50, Generate: Generate})
+	replay.Register(18, replay.Simulation{
+		Palette: palette,
+		Run: func(input interface{}, part int, recorder replay.Recorder) error {
+			return Simulate(input.(Input), part, recorder)
+		},
+	})
+}
+
+// Input is the initial state of the lumber collection area
+type Input struct {
+	board board
+}
+
+// Parse parses the map of the lumber collection area
+func Parse(reader io.Reader) (Input, error) {
+	rawBoard, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	parsedBoard, err := parseBoard(rawBoard)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{board: parsedBoard}, nil
+}
+
+// Part1 finds the resource value after 10 minutes
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return runSimulation(input.board, part1Ticks, nil, budget.Start(ctx))
+}
+
+// Part2 finds the resource value after a billion minutes
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return runSimulation(input.board, part2Ticks, nil, budget.Start(ctx))
+}
+
+// Simulate runs the simulation a part is solved with, recording the area after every minute until it either finishes
+// or starts repeating itself
+func Simulate(input Input, part int, recorder replay.Recorder) error {
+	steps := aoc.Budget{}.Start(context.Background())
+	switch part {
+	case 1:
+		_, err := runSimulation(input.board, part1Ticks, recorder, steps)
+		return err
+	case 2:
+		_, err := runSimulation(input.board, part2Ticks, recorder, steps)
+		return err
+	default:
+		return fmt.Errorf("part %d: %w", part, aoc.ErrUnknownPart)
+	}
+}
+
+// Generate makes a square lumber collection area, size acres wide
+func Generate(rng *rand.Rand, size int) string {
+	rows := make([]string, size)
+	for row := range rows {
+		acres := make([]rune, size)
+		for col := range acres {
+			acres[col] = generatedAcres[rng.Intn(len(generatedAcres))]
+		}
+
+		rows[row] = string(acres)
+	}
+
+	return generate.Lines(rows)
+}
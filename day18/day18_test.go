@@ -0,0 +1,76 @@
+package day18
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/replay"
+)
+
+const example = `.#.#...|#.
+.....#|##|
+.|..|...#.
+..|#.....#
+#.#|||#|#|
+...#.||...
+.|....|...
+||...#|.#|
+|.||||..|.
+...#.|..|.
+`
+
+func TestPart1(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), input, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 1147 {
+		t.Errorf("got %d, want 1147", got)
+	}
+}
+
+func TestParseRaggedBoard(t *testing.T) {
+	if _, err := Parse(strings.NewReader(".#.\n.#\n")); err == nil {
+		t.Error("expected an error for rows of different lengths")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tape := replay.NewTape()
+	if err := Simulate(input, 1, tape); err != nil {
+		t.Fatal(err)
+	}
+
+	// The starting area is recorded as well as each of the ten minutes
+	if tape.Len() != part1Ticks+1 {
+		t.Fatalf("got %d frames, want %d", tape.Len(), part1Ticks+1)
+	}
+
+	last := tape.Frame(tape.Len() - 1)
+	if last.Caption != "minute 10" {
+		t.Errorf("got caption %q for the last frame, want %q", last.Caption, "minute 10")
+	} else if value := grid.Count(last.Cells, treeChar) * grid.Count(last.Cells, lumberChar); value != 1147 {
+		t.Errorf("last frame has a resource value of %d, want 1147", value)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 18)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 18)
+}
@@ -0,0 +1,181 @@
+// Package day19 solves the puzzle for day 19 of Advent of Code 2018
+package day19
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const numRegisters = 6
+
+// maxGeneratedConstant is the largest of the constants a generated program builds the number it sums the divisors of from
+const maxGeneratedConstant = 25
+
+// programTemplate is the background process, which sums the divisors of a number built from the two constants left
+// out of it. Part 2 adds a much larger number to it before summing its divisors.
+const programTemplate = `#ip 5
+addi 5 16 5
+seti 1 8 4
+seti 1 5 3
+mulr 4 3 1
+eqrr 1 2 1
+addr 1 5 5
+addi 5 1 5
+addr 4 0 0
+addi 3 1 3
+gtrr 3 2 1
+addr 5 1 5
+seti 2 5 5
+addi 4 1 4
+gtrr 4 2 1
+addr 1 5 5
+seti 1 2 5
+mulr 5 5 5
+addi 2 2 2
+mulr 2 2 2
+mulr 5 2 2
+muli 2 11 2
+addi 1 %d 1
+mulr 1 5 1
+addi 1 %d 1
+addr 2 1 2
+addr 5 0 5
+seti 0 7 5
+setr 5 0 1
+mulr 1 5 1
+addr 5 1 1
+mulr 5 1 1
+muli 1 14 1
+mulr 1 5 1
+addr 2 1 2
+seti 0 0 0
+seti 0 9 5`
+
+var errMissingIPDirective = errors.New("program does not start with an #ip directive")
+
+func parseInput(rawInstructions []string) (elfcode.Program, error) {
+	program, err := elfcode.ParseProgram(rawInstructions)
+	var lineErr elfcode.AssemblyError
+	if errors.As(err, &lineErr) {
+		return elfcode.Program{}, lineErr.ParseError(19)
+	} else if err != nil {
+		return elfcode.Program{}, err
+	} else if !program.HasIPRegister() {
+		// The instruction pointer must be bound with an #ip directive on the first line
+		firstLine := ""
+		if len(rawInstructions) > 0 {
+			firstLine = rawInstructions[0]
+		}
+
+		return elfcode.Program{}, aoc.NewParseError(19, 0, -1, firstLine, errMissingIPDirective)
+	}
+
+	return program, nil
+}
+
+// runElfcode naively runs the elfcode program and prints a trace - is not used for solution but was used for debugging.
+// It is left in to help future readers determine how the puzzle was solved
+func runElfcode(machine *elfcode.Machine) int {
+	instructions := machine.Program().Instructions
+	for !machine.Halted() {
+		fmt.Print(machine.Registers, " => ")
+		instructionIndex := machine.IP()
+		machine.Step()
+		fmt.Println(instructions[instructionIndex], "=>", machine.Registers)
+	}
+
+	return machine.Registers[0]
+}
+
+// solve runs the program to completion, with its loops replaced by native operations so that it finishes in a reasonable time.
+// A step is taken for each instruction run, as the program may never halt.
+func solve(program elfcode.Program, register0 int, steps *aoc.Steps) (int, error) {
+	machine, err := elfcode.NewMachine(program, numRegisters)
+	if err != nil {
+		return 0, err
+	}
+
+	machine.Optimize()
+	machine.Registers[0] = register0
+	for !machine.Halted() {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		machine.Step()
+	}
+
+	return machine.Registers[0], nil
+}
+
+func init() {
+	aoc.Register(19, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(elfcode.Program), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(elfcode.Program), budget)
+		},
+	})
+	generate.Register(19, generate.Generator{DefaultSize: 1, Generate: Generate})
+}
+
+// Parse parses the background process's program
+func Parse(reader io.Reader) (elfcode.Program, error) {
+	rawInstructions, err := aoc.ReadLines(reader)
+	if err != nil {
+		return elfcode.Program{}, err
+	}
+
+	return parseInput(rawInstructions)
+}
+
+// Part1 finds the value left in register 0 once the program halts
+func Part1(ctx context.Context, program elfcode.Program, budget aoc.Budget) (int, error) {
+	return solve(program, 0, budget.Start(ctx))
+}
+
+// Part2 finds the value left in register 0 once the program halts, if register 0 starts at 1
+func Part2(ctx context.Context, program elfcode.Program, budget aoc.Budget) (int, error) {
+	return solve(program, 1, budget.Start(ctx))
+}
+
+// Generate makes a background process like the real one, with random constants, and its registers other than register 0
+// shuffled. Every program is the same length, so size is ignored.
+func Generate(rng *rand.Rand, size int) string {
+	rawProgram := fmt.Sprintf(programTemplate, generate.Between(rng, 1, maxGeneratedConstant), generate.Between(rng, 1, maxGeneratedConstant))
+	program, err := elfcode.ParseProgram(strings.Split(rawProgram, "\n"))
+	if err != nil {
+		panic(fmt.Sprintf("program template does not parse: %s", err))
+	}
+
+	// Register 0 holds the answer, so it stays where it is
+	registers := append([]int{0}, rng.Perm(numRegisters-1)...)
+	for i := 1; i < numRegisters; i++ {
+		registers[i]++
+	}
+
+	lines := []string{fmt.Sprintf("#ip %d", registers[program.IPRegister])}
+	for _, ins := range program.Instructions {
+		if ins.Op.UsesRegisterA() {
+			ins.A = registers[ins.A]
+		}
+		if ins.Op.UsesRegisterB() {
+			ins.B = registers[ins.B]
+		}
+		ins.C = registers[ins.C]
+
+		lines = append(lines, ins.String())
+	}
+
+	return generate.Lines(lines)
+}
@@ -0,0 +1,62 @@
+package day19
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = `#ip 0
+seti 5 0 1
+seti 6 0 2
+addi 0 1 0
+addr 1 2 3
+setr 1 0 0
+seti 8 0 4
+seti 9 0 5
+`
+
+func TestPart1(t *testing.T) {
+	program, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), program, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 6 {
+		t.Errorf("got %d, want 6", got)
+	}
+}
+
+func TestPart1NeverHalts(t *testing.T) {
+	// The second instruction jumps back to itself
+	program, err := Parse(strings.NewReader("#ip 1\naddi 0 1 0\nseti 0 0 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Part1(context.Background(), program, aoc.Budget{MaxSteps: 1000}); !errors.Is(err, aoc.ErrOutOfSteps) {
+		t.Errorf("got error %v, want %v", err, aoc.ErrOutOfSteps)
+	}
+}
+
+func TestParseMissingIPDirective(t *testing.T) {
+	_, err := Parse(strings.NewReader("seti 5 0 1\n"))
+	if err == nil {
+		t.Fatal("expected an error for a program without an #ip directive")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 19)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 19)
+}
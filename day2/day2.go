@@ -0,0 +1,157 @@
+// Package day2 solves the puzzle for day 2 of Advent of Code 2018
+package day2
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+// boxIDLength is the number of letters in each generated box ID
+const boxIDLength = 26
+
+// getLetters returns (letter that appears twice, letter that appears thrice)
+func getLetters(boxString string) (rune, rune) {
+	counts := make(map[rune]int)
+	for _, letter := range boxString {
+		if _, ok := counts[letter]; !ok {
+			counts[letter] = 0
+		}
+		counts[letter]++
+	}
+	// Get a letter that occurs three times or two times
+	var twoLetter, threeLetter rune
+	for letter, count := range counts {
+		if count == 2 {
+			twoLetter = letter
+		} else if count == 3 {
+			threeLetter = letter
+		}
+	}
+
+	return twoLetter, threeLetter
+}
+
+func getLettersInCommon(box1, box2 string) string {
+	if len(box1) != len(box2) {
+		return ""
+	}
+
+	commonLetters := new(bytes.Buffer)
+	for i, letter := range box1 {
+		if box1[i] == box2[i] {
+			commonLetters.WriteRune(letter)
+		}
+	}
+
+	return commonLetters.String()
+}
+
+func getNumDifferentLetters(box1, box2 string) int {
+	if len(box1) != len(box2) {
+		return -1
+	}
+	diffCount := 0
+	commonString := getLettersInCommon(box1, box2)
+	if len(commonString) == len(box1)-1 {
+		diffCount++
+	}
+
+	return diffCount
+}
+
+func part1(boxes []string, steps *aoc.Steps) (int, error) {
+	twoCount, threeCount := 0, 0
+	for _, box := range boxes {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		twoLetter, threeLetter := getLetters(box)
+		if twoLetter != 0 {
+			twoCount++
+		}
+		if threeLetter != 0 {
+			threeCount++
+		}
+	}
+
+	return threeCount * twoCount, nil
+}
+
+// part2 finds the letters in common between the two boxes that differ by a single letter, taking a step for each pair
+// of boxes compared
+func part2(boxes []string, steps *aoc.Steps) (string, error) {
+	for _, box1 := range boxes {
+		for _, box2 := range boxes {
+			if err := steps.Take(); err != nil {
+				return "", err
+			}
+
+			diffCount := getNumDifferentLetters(box1, box2)
+			if diffCount == 1 {
+				return getLettersInCommon(box1, box2), nil
+			}
+		}
+	}
+
+	return "", aoc.ErrNoAnswer
+}
+
+func init() {
+	aoc.Register(2, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.([]string), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.([]string), budget)
+		},
+	})
+	generate.Register(2, generate.Generator{Size: "box IDs", DefaultSize: 250, Generate: Generate})
+}
+
+// Parse parses the list of box IDs
+func Parse(reader io.Reader) ([]string, error) {
+	return aoc.ReadLines(reader)
+}
+
+// Part1 finds the checksum of the box IDs
+func Part1(ctx context.Context, boxes []string, budget aoc.Budget) (int, error) {
+	return part1(boxes, budget.Start(ctx))
+}
+
+// Part2 finds the letters in common between the two box IDs that differ by a single letter
+func Part2(ctx context.Context, boxes []string, budget aoc.Budget) (string, error) {
+	return part2(boxes, budget.Start(ctx))
+}
+
+// Generate makes a list of size box IDs, or two if size is smaller, with a pair of them that differ by a single letter
+func Generate(rng *rand.Rand, size int) string {
+	boxes := make([]string, max(size, 2))
+	for i := range boxes {
+		box := make([]byte, boxIDLength)
+		for j := range box {
+			box[j] = byte('a' + rng.Intn(26))
+		}
+
+		boxes[i] = string(box)
+	}
+
+	// Copy one box over another, and change one of its letters to any other letter
+	original, changed := rng.Intn(len(boxes)), rng.Intn(len(boxes)-1)
+	if changed >= original {
+		changed++
+	}
+
+	box := []byte(boxes[original])
+	letter := rng.Intn(len(box))
+	box[letter] = byte('a' + (int(box[letter]-'a')+1+rng.Intn(25))%26)
+	boxes[changed] = string(box)
+
+	return generate.Lines(boxes)
+}
@@ -0,0 +1,60 @@
+package day2
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestPart1(t *testing.T) {
+	boxes, err := Parse(strings.NewReader("abcdef\nbababc\nabbcde\nabcccd\naabcdd\nabcdee\nababab\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), boxes, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 12 {
+		t.Errorf("got %d, want 12", got)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"example", "abcde\nfghij\nklmno\npqrst\nfguij\naxcye\nwvxyz\n", "fgij", nil},
+		{"no close boxes", "abcde\nfghij\n", "", aoc.ErrNoAnswer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Part2(context.Background(), boxes, aoc.Budget{})
+			if err != tt.wantErr {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			} else if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 2)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 2)
+}
@@ -0,0 +1,495 @@
+// Package day20 solves the puzzle for day 20 of Advent of Code 2018
+package day20
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+	"github.com/ollien/advent-of-code-2018/search"
+)
+
+const (
+	northChar          = 'N'
+	southChar          = 'S'
+	eastChar           = 'E'
+	westChar           = 'W'
+	branchChar         = '|'
+	branchStartChar    = '('
+	branchEndChar      = ')'
+	startChar          = '^'
+	endChar            = '$'
+	roomChar           = '.'
+	noRoomChar         = ' '
+	wallChar           = '#'
+	verticalDoorChar   = '|'
+	horizontalDoorChar = '-'
+	startPosChar       = 'X'
+)
+
+// maxDetourLength is the most doors a dead end can go through for Generate to write it as a detour
+const maxDetourLength = 4
+
+// generatedDirections are the directions in a generated regex, each opposite the one two along from it
+var generatedDirections = []byte{northChar, eastChar, southChar, westChar}
+
+var (
+	errUnmatchedBranch = errors.New("branch is not closed")
+	errMissingEnd      = errors.New("regex does not end with $")
+)
+
+const (
+	noDirection direction = iota
+	northDirection
+	southDirection
+	eastDirection
+	westDirection
+)
+
+type direction int
+
+type node struct {
+	north,
+	south,
+	east,
+	west *node
+}
+
+type cursor struct {
+	n   *node
+	pos grid.Point
+}
+
+func newNode() *node {
+	return &node{}
+}
+
+func (c *cursor) updateCoord(dir direction) {
+	switch dir {
+	case northDirection:
+		c.pos = c.pos.Add(grid.Up)
+	case eastDirection:
+		c.pos = c.pos.Add(grid.Right)
+	case southDirection:
+		c.pos = c.pos.Add(grid.Down)
+	case westDirection:
+		c.pos = c.pos.Add(grid.Left)
+	}
+}
+
+// Attaches a ndoe to the graph, returning
+func (n *node) attach(dir direction, newNode *node) {
+	switch dir {
+	case northDirection:
+		n.north = newNode
+		newNode.south = n
+	case eastDirection:
+		n.east = newNode
+		newNode.west = n
+	case southDirection:
+		n.south = newNode
+		newNode.north = n
+	case westDirection:
+		n.west = newNode
+		newNode.east = n
+	}
+}
+
+func (n *node) neighbors() []*node {
+	neighbors := make([]*node, 0, 4)
+	for _, neighbor := range []*node{n.north, n.east, n.south, n.west} {
+		if neighbor != nil {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	return neighbors
+}
+
+func printRooms(rooms *grid.Sparse[*node]) {
+	printRoomsCursor(rooms, grid.Point{})
+}
+
+// printRoomsCursor prints the map of the rooms and the doors between them, with the start and the cursor marked
+func printRoomsCursor(rooms *grid.Sparse[*node], cursorPos grid.Point) {
+	bounds := rooms.Bounds()
+	for row := bounds.Min.Row; row < bounds.Max.Row; row++ {
+		// Each row of rooms has a row of walls and doors above it
+		fmt.Printf("%c", wallChar)
+		for col := bounds.Min.Col; col < bounds.Max.Col; col++ {
+			if above := rooms.Get(grid.Point{Row: row - 1, Col: col}); above != nil && above.south != nil {
+				fmt.Printf("%c%c", horizontalDoorChar, wallChar)
+			} else {
+				fmt.Printf("%c%c", wallChar, wallChar)
+			}
+		}
+		fmt.Print("\n")
+
+		fmt.Printf("%c", wallChar)
+		for col := bounds.Min.Col; col < bounds.Max.Col; col++ {
+			pos := grid.Point{Row: row, Col: col}
+			room, haveRoom := rooms.Lookup(pos)
+			if !haveRoom {
+				fmt.Printf("%c%c", noRoomChar, noRoomChar)
+				continue
+			}
+
+			if pos == (grid.Point{}) || pos == cursorPos {
+				fmt.Printf("%c", startPosChar)
+			} else {
+				fmt.Printf("%c", roomChar)
+			}
+			if room.east == nil {
+				fmt.Printf("%c", wallChar)
+			} else {
+				fmt.Printf("%c", verticalDoorChar)
+			}
+		}
+		fmt.Print("\n")
+	}
+
+	// Close off the bottom row of rooms
+	fmt.Println(strings.Repeat(string(wallChar), bounds.Cols()*2+1))
+}
+
+func getDirectionFromChar(char byte) (direction, error) {
+	switch char {
+	case northChar:
+		return northDirection, nil
+	case eastChar:
+		return eastDirection, nil
+	case southChar:
+		return southDirection, nil
+	case westChar:
+		return westDirection, nil
+	}
+
+	return noDirection, aoc.ErrMalformedInput
+}
+
+// findRegexError finds the index of the first character that makes the regex invalid, or -1 if it is valid.
+// The regex should not have its start and end characters.
+func findRegexError(rawRegex string) (int, error) {
+	var openBranches []int
+	for i := 0; i < len(rawRegex); i++ {
+		switch char := rawRegex[i]; char {
+		case branchStartChar:
+			openBranches = append(openBranches, i)
+		case branchEndChar:
+			if len(openBranches) == 0 {
+				return i, errUnmatchedBranch
+			}
+			openBranches = openBranches[:len(openBranches)-1]
+		case branchChar:
+			continue
+		default:
+			if _, err := getDirectionFromChar(char); err != nil {
+				return i, aoc.ErrMalformedInput
+			}
+		}
+	}
+
+	if len(openBranches) > 0 {
+		return openBranches[len(openBranches)-1], errUnmatchedBranch
+	}
+
+	return -1, nil
+}
+
+func parseInput(rawRegex string) (*node, error) {
+	head, _, _, err := makeGraph(rawRegex, cursor{n: newNode()}, nil)
+
+	return head, err
+}
+
+func makeGraph(rawRegex string, headCursor cursor, roomGrid *grid.Sparse[*node]) (*node, *grid.Sparse[*node], int, error) {
+	graphCursor := headCursor
+	// Keep track of the spaces we've already allocated so we can circle back to existing rooms
+	if roomGrid == nil {
+		roomGrid = grid.NewSparse[*node]()
+		roomGrid.Set(headCursor.pos, headCursor.n)
+	}
+	for i := 0; i < len(rawRegex); i++ {
+		char := rawRegex[i]
+		if char == branchStartChar {
+			_, _, skipCount, err := makeGraph(rawRegex[i+1:], graphCursor, roomGrid)
+			if err != nil {
+				return nil, nil, 0, err
+			}
+			i += skipCount
+		} else if char == branchChar {
+			// By resetting to the head no matter what when we hit a branch char, we are abusing a property of the detours that they always seem to circle back upon themselves.
+			graphCursor = headCursor
+		} else if char == branchEndChar {
+			return headCursor.n, roomGrid, i + 1, nil
+		} else {
+			dir, err := getDirectionFromChar(char)
+			if err != nil {
+				return nil, nil, 0, err
+			}
+
+			graphCursor.updateCoord(dir)
+			gridNode, haveNode := roomGrid.Lookup(graphCursor.pos)
+			if !haveNode {
+				gridNode = newNode()
+				roomGrid.Set(graphCursor.pos, gridNode)
+			}
+			graphCursor.n.attach(dir, gridNode)
+			graphCursor.n = gridNode
+		}
+	}
+
+	return headCursor.n, roomGrid, 0, nil
+}
+
+// maze is a square of rooms with the doors between them forming a tree, so there is exactly one route to every room
+type maze struct {
+	side  int
+	start int
+	// children holds the rooms each room has doors to, other than the one it is reached from
+	children [][]int
+}
+
+// newMaze makes a random maze by walking to rooms that have not been visited yet, backtracking when there are none
+func newMaze(rng *rand.Rand, side int) maze {
+	m := maze{side: side, start: rng.Intn(side * side), children: make([][]int, side*side)}
+	visited := make([]bool, side*side)
+	visited[m.start] = true
+	stack := []int{m.start}
+	for len(stack) > 0 {
+		room := stack[len(stack)-1]
+		var unvisited []int
+		for direction := range generatedDirections {
+			if neighbor, ok := m.neighbor(room, direction); ok && !visited[neighbor] {
+				unvisited = append(unvisited, neighbor)
+			}
+		}
+
+		if len(unvisited) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := unvisited[rng.Intn(len(unvisited))]
+		visited[next] = true
+		m.children[room] = append(m.children[room], next)
+		stack = append(stack, next)
+	}
+
+	return m
+}
+
+// neighbor gets the room next to the given one in the given direction, returning false if it is outside the maze
+func (m maze) neighbor(room int, direction int) (int, bool) {
+	row, col := room/m.side, room%m.side
+	switch generatedDirections[direction] {
+	case northChar:
+		row--
+	case eastChar:
+		col++
+	case southChar:
+		row++
+	case westChar:
+		col--
+	}
+
+	if row < 0 || row >= m.side || col < 0 || col >= m.side {
+		return 0, false
+	}
+
+	return row*m.side + col, true
+}
+
+// direction gets the direction of the door from one room to the other
+func (m maze) direction(from int, to int) int {
+	for direction := range generatedDirections {
+		if neighbor, ok := m.neighbor(from, direction); ok && neighbor == to {
+			return direction
+		}
+	}
+
+	panic(fmt.Sprintf("room %d is not next to room %d", from, to))
+}
+
+// deadEnd gets the directions from one room into a child of it and on to the end of the corridor past it, returning
+// false if the corridor branches or goes through more than maxDetourLength doors
+func (m maze) deadEnd(room int, child int) ([]int, bool) {
+	path := []int{m.direction(room, child)}
+	for len(m.children[child]) == 1 && len(path) < maxDetourLength {
+		path = append(path, m.direction(child, m.children[child][0]))
+		child = m.children[child][0]
+	}
+
+	return path, len(m.children[child]) == 0
+}
+
+// writeRoutes writes the routes from a room to every room past it. Short dead ends are written as detours that come
+// back to the room, but any other branches must be left until the end, as the parser follows whatever comes after
+// them from where they split.
+func (m maze) writeRoutes(builder *strings.Builder, room int) {
+	for {
+		var branches []int
+		for _, child := range m.children[room] {
+			detour, ok := m.deadEnd(room, child)
+			if !ok {
+				branches = append(branches, child)
+				continue
+			}
+
+			builder.WriteByte(branchStartChar)
+			for _, direction := range detour {
+				builder.WriteByte(generatedDirections[direction])
+			}
+			for i := len(detour) - 1; i >= 0; i-- {
+				builder.WriteByte(generatedDirections[(detour[i]+2)%len(generatedDirections)])
+			}
+			builder.WriteByte(branchChar)
+			builder.WriteByte(branchEndChar)
+		}
+
+		if len(branches) == 0 {
+			return
+		} else if len(branches) == 1 {
+			builder.WriteByte(generatedDirections[m.direction(room, branches[0])])
+			room = branches[0]
+			continue
+		}
+
+		builder.WriteByte(branchStartChar)
+		for i, branch := range branches {
+			if i > 0 {
+				builder.WriteByte(branchChar)
+			}
+			builder.WriteByte(generatedDirections[m.direction(room, branch)])
+			m.writeRoutes(builder, branch)
+		}
+		builder.WriteByte(branchEndChar)
+
+		return
+	}
+}
+
+// getShortestDistances gets the shortest distance to every node from a given head. A step is taken for each room
+// visited, and running out stops the search as if it had found a goal.
+func getShortestDistances(head *node, steps *aoc.Steps) (map[*node]int, error) {
+	var stepErr error
+	result := search.BFS(head, (*node).neighbors, func(*node) bool {
+		stepErr = steps.Take()
+
+		return stepErr != nil
+	})
+
+	if stepErr != nil {
+		return nil, stepErr
+	}
+
+	return result.Distances(), nil
+}
+
+func part1(head *node, steps *aoc.Steps) (int, error) {
+	distances, err := getShortestDistances(head, steps)
+	if err != nil {
+		return 0, err
+	}
+
+	maxDistance := 0
+	for _, distance := range distances {
+		if distance > maxDistance {
+			maxDistance = distance
+		}
+	}
+
+	return maxDistance, nil
+}
+
+func part2(head *node, steps *aoc.Steps) (int, error) {
+	distances, err := getShortestDistances(head, steps)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, distance := range distances {
+		if distance >= 1000 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func init() {
+	aoc.Register(20, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(20, generate.Generator{Size: "rooms", DefaultSize: 10000, Generate: Generate})
+}
+
+// Input is the facility's rooms, starting from the one the regex starts at
+type Input struct {
+	head *node
+}
+
+// Parse parses the regex describing the facility
+func Parse(reader io.Reader) (Input, error) {
+	rawRegex, err := aoc.ReadString(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	line := rawRegex
+	if len(rawRegex) == 0 || rawRegex[0] != startChar {
+		return Input{}, aoc.NewParseError(20, 0, 0, line, aoc.ErrMalformedInput)
+	} else if len(rawRegex) < 2 || rawRegex[len(rawRegex)-1] != endChar {
+		return Input{}, aoc.NewParseError(20, 0, len(rawRegex), line, errMissingEnd)
+	}
+
+	rawRegex = rawRegex[1 : len(rawRegex)-1]
+	// Account for the start character when finding the column
+	if badChar, err := findRegexError(rawRegex); err != nil {
+		return Input{}, aoc.NewParseError(20, 0, badChar+1, line, err)
+	}
+
+	head, err := parseInput(rawRegex)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{head: head}, nil
+}
+
+// Part1 finds how many doors must be passed through to reach the furthest room
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part1(input.head, budget.Start(ctx))
+}
+
+// Part2 finds how many rooms are at least 1000 doors away
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part2(input.head, budget.Start(ctx))
+}
+
+// Generate makes a regex for a square facility of at least size rooms, starting from a random one of them
+func Generate(rng *rand.Rand, size int) string {
+	side := int(math.Ceil(math.Sqrt(float64(size))))
+	m := newMaze(rng, side)
+
+	var builder strings.Builder
+	builder.WriteByte(startChar)
+	m.writeRoutes(&builder, m.start)
+	builder.WriteByte(endChar)
+
+	return generate.Lines([]string{builder.String()})
+}
@@ -0,0 +1,87 @@
+package day20
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		regex string
+		want  int
+	}{
+		{"^WNE$", 3},
+		{"^ENWWW(NEEE|SSE(EE|N))$", 10},
+		{"^ENNWSWW(NEWS|)SSSEEN(WNSE|)EE(SWEN|)NNN$", 18},
+		{"^ESSWWN(E|NNENN(EESS(WNSE|)SSS|WWWSSSSE(SW|NNNE)))$", 23},
+		{"^WSSEESWWWNW(S|NENNEEEENN(ESSSSW(NWSW|SSEN)|WSWWN(E|WWS(E|SS))))$", 31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(tt.regex))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Part1(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		regex      string
+		wantColumn int
+	}{
+		{"WNE$", 1},
+		{"^WNE", 5},
+		{"^WN(E|S$", 4},
+		{"^WNX$", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.regex))
+			parseErr, ok := err.(*aoc.ParseError)
+			if !ok {
+				t.Fatalf("got error %v, want a parse error", err)
+			} else if parseErr.Column != tt.wantColumn {
+				t.Errorf("got column %d, want %d", parseErr.Column, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestSearchIsBudgeted(t *testing.T) {
+	// Parsing only builds the rooms, so even a budget too small to search them all doesn't stop it
+	input, err := Parse(strings.NewReader("^ENWWW(NEEE|SSE(EE|N))$"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string]func(context.Context, Input, aoc.Budget) (int, error){"part 1": Part1, "part 2": Part2}
+	for name, part := range parts {
+		if _, err := part(context.Background(), input, aoc.Budget{MaxSteps: 5}); !errors.Is(err, aoc.ErrOutOfSteps) {
+			t.Errorf("%s: got error %v, want %v", name, err, aoc.ErrOutOfSteps)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 20)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 20)
+}
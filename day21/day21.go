@@ -0,0 +1,235 @@
+// Package day21 solves the puzzle for day 21 of Advent of Code 2018
+package day21
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/cycle"
+	"github.com/ollien/advent-of-code-2018/elfcode"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const numRegisters = 6
+
+var errMissingIPDirective = errors.New("program does not start with an #ip directive")
+
+func parseInput(rawInstructions []string) (elfcode.Program, error) {
+	program, err := elfcode.ParseProgram(rawInstructions)
+	var lineErr elfcode.AssemblyError
+	if errors.As(err, &lineErr) {
+		return elfcode.Program{}, lineErr.ParseError(21)
+	} else if err != nil {
+		return elfcode.Program{}, err
+	} else if !program.HasIPRegister() {
+		// The instruction pointer must be bound with an #ip directive on the first line
+		firstLine := ""
+		if len(rawInstructions) > 0 {
+			firstLine = rawInstructions[0]
+		}
+
+		return elfcode.Program{}, aoc.NewParseError(21, 0, -1, firstLine, errMissingIPDirective)
+	}
+
+	return program, nil
+}
+
+// runAsDebug naively runs the elfcode program and prints a trace - is not used for solution but was used for debugging.
+// It is left in to help future readers determine how the puzzle was solved
+func runAsDebug(machine *elfcode.Machine) int {
+	instructions := machine.Program().Instructions
+	for !machine.Halted() {
+		fmt.Print(machine.Registers, " => ")
+		instructionIndex := machine.IP()
+		machine.Step()
+		fmt.Println(instructionIndex, "-", instructions[instructionIndex], "=>", machine.Registers)
+	}
+
+	return machine.Registers[0]
+}
+
+// getNumInstructionsRun runs the program to completion and gets the number of instructions that were executed
+func getNumInstructionsRun(machine *elfcode.Machine) int {
+	machine.Run()
+
+	return machine.NumSteps()
+}
+
+// runUntilHaltCheck runs the machine until it is about to run the halt check, returning false if the program halts first.
+// A step is taken for each instruction run, as the program may never reach the check.
+func runUntilHaltCheck(machine *elfcode.Machine, check elfcode.HaltCheck, steps *aoc.Steps) (bool, error) {
+	for !machine.Halted() {
+		if machine.IP() == check.Index {
+			return true, nil
+		}
+
+		if err := steps.Take(); err != nil {
+			return false, err
+		}
+
+		machine.Step()
+	}
+
+	return false, nil
+}
+
+func makeMachine(program elfcode.Program) (*elfcode.Machine, error) {
+	machine, err := elfcode.NewMachine(program, numRegisters)
+	if err != nil {
+		return nil, err
+	}
+
+	machine.Optimize()
+
+	return machine, nil
+}
+
+// part1 finds the value of register 0 that halts the program after the fewest instructions - the first one it is compared to
+func part1(program elfcode.Program, check elfcode.HaltCheck, steps *aoc.Steps) (int, error) {
+	machine, err := makeMachine(program)
+	if err != nil {
+		return 0, err
+	}
+
+	reachedCheck, err := runUntilHaltCheck(machine, check, steps)
+	if err != nil {
+		return 0, err
+	} else if !reachedCheck {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return machine.Registers[check.Register], nil
+}
+
+// part2 finds the value of register 0 that halts the program after the most instructions.
+// Every time the halt check is reached, the machine's state determines every state after it, so once a state repeats,
+// the program will never compare against a new value. The last new value is the one that takes the longest to reach.
+func part2(program elfcode.Program, check elfcode.HaltCheck, steps *aoc.Steps) (int, error) {
+	machine, err := makeMachine(program)
+	if err != nil {
+		return 0, err
+	}
+
+	steps.SetStage("looking for a repeated halt check")
+	history := cycle.NewHistory(func(state [numRegisters]int) [numRegisters]int { return state })
+	for {
+		reachedCheck, err := runUntilHaltCheck(machine, check, steps)
+		if err != nil {
+			return 0, err
+		} else if !reachedCheck {
+			break
+		}
+
+		var state [numRegisters]int
+		copy(state[:], machine.Registers)
+		if _, ok := history.Add(state); ok {
+			break
+		}
+
+		// Run the check itself, so we don't immediately stop on it again
+		machine.Step()
+	}
+
+	seenValues := map[int]bool{}
+	lastNewValue := 0
+	foundValue := false
+	for step := 0; step < history.Len(); step++ {
+		value := history.State(step)[check.Register]
+		if !seenValues[value] {
+			seenValues[value] = true
+			lastNewValue = value
+			foundValue = true
+		}
+	}
+
+	if !foundValue {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return lastNewValue, nil
+}
+
+func init() {
+	aoc.Register(21, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+		LongRunning: map[int]bool{2: true},
+	})
+	generate.Register(21, generate.Generator{Size: "values the program cycles through", DefaultSize: 10000, Generate: Generate})
+}
+
+// Input is the activation system's program, along with the check that decides whether it halts
+type Input struct {
+	program elfcode.Program
+	check   elfcode.HaltCheck
+}
+
+// Parse parses the program, and finds the check that decides whether it halts
+func Parse(reader io.Reader) (Input, error) {
+	rawInstructions, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	program, err := parseInput(rawInstructions)
+	if err != nil {
+		return Input{}, err
+	}
+
+	check, err := elfcode.FindHaltCheck(program)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{program: program, check: check}, nil
+}
+
+// Part1 finds the value of register 0 that halts the program after the fewest instructions
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part1(input.program, input.check, budget.Start(ctx))
+}
+
+// Part2 finds the value of register 0 that halts the program after the most instructions
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part2(input.program, input.check, budget.Start(ctx))
+}
+
+// Generate makes a program that steps a value through a linear congruential generator, halting once it equals
+// register 0. The value is kept to the fewest bits that hold size values, and the multiplier and increment are picked
+// so that it passes through every one of them before repeating. The bit above them is set before the check, so that it
+// never halts by reaching zero.
+func Generate(rng *rand.Rand, size int) string {
+	mask := 1
+	for mask < size {
+		mask *= 2
+	}
+	mask--
+
+	// The instruction pointer, the value and the result of the check each get their own register, other than register 0
+	registers := rng.Perm(numRegisters - 1)
+	ip, value, flag := registers[0]+1, registers[1]+1, registers[2]+1
+	// A multiplier one more than a multiple of four and an odd increment give a full period
+	multiplier := 4*rng.Intn(mask/4+1) + 1
+	increment := 2*rng.Intn(mask/2+1) + 1
+
+	return generate.Lines([]string{
+		fmt.Sprintf("#ip %d", ip),
+		fmt.Sprintf("seti %d 0 %d", rng.Intn(mask+1), value),
+		fmt.Sprintf("muli %d %d %d", value, multiplier, value),
+		fmt.Sprintf("addi %d %d %d", value, increment, value),
+		fmt.Sprintf("bani %d %d %d", value, mask, value),
+		fmt.Sprintf("bori %d %d %d", value, mask+1, flag),
+		fmt.Sprintf("eqrr %d 0 %d", flag, flag),
+		fmt.Sprintf("addr %d %d %d", flag, ip, ip),
+		fmt.Sprintf("seti 0 0 %d", ip),
+	})
+}
@@ -0,0 +1,52 @@
+package day21
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+// example halts once register 1, counting up by 3 and kept to three bits, equals register 0.
+// It takes the values 3, 6, 1, 4, 7, 2, 5, 0 before repeating.
+const example = `#ip 4
+seti 0 0 1
+addi 1 3 1
+bani 1 7 1
+eqrr 1 0 3
+addr 3 4 4
+seti 0 0 4
+`
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name string
+		part func(context.Context, Input, aoc.Budget) (int, error)
+		want int
+	}{
+		{"part1", Part1, 3},
+		{"part2", Part2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(example))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.part(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkExample(b *testing.B) {
+	aoctest.BenchmarkInput(b, 21, example)
+}
@@ -1,13 +1,18 @@
-package main
+// Package day22 solves the puzzle for day 22 of Advent of Code 2018
+package day22
 
 import (
-	"container/heap"
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"io"
+	"math/rand"
 	"strconv"
 	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/search"
 )
 
 const (
@@ -20,6 +25,14 @@ const (
 	movementTime          = 1
 )
 
+// The ranges of the cave specs made by Generate, which are about those of the real ones
+const (
+	minGeneratedDepth   = 3000
+	maxGeneratedDepth   = 12000
+	minGeneratedTargetX = 5
+	maxGeneratedTargetX = 15
+)
+
 type tool int
 
 const (
@@ -47,12 +60,6 @@ type caveSpec struct {
 	target coordinate
 }
 
-// represents a single movement from room to room
-type movement struct {
-	endingState playerState
-	time        int
-}
-
 // represents the playerState of the player after moving
 // This does _NOT_ include time, and should not, as equality must represent being at the same place, regardless fo time.
 type playerState struct {
@@ -60,60 +67,6 @@ type playerState struct {
 	currentTool tool
 }
 
-// Implements container/heap.Interface
-// The highest priority item is the movement with the lowest distance
-type movementPriorityQueue struct {
-	backingSlice []movement
-	times        map[playerState]int
-}
-
-func (queue *movementPriorityQueue) Len() int {
-	return len(queue.backingSlice)
-}
-
-func (queue *movementPriorityQueue) Less(i, j int) bool {
-	movement1 := queue.backingSlice[i]
-	movement2 := queue.backingSlice[j]
-	movement1Time := queue.times[movement1.endingState]
-	movement2Time := queue.times[movement2.endingState]
-	return movement1Time < movement2Time
-}
-
-func (queue *movementPriorityQueue) Swap(i, j int) {
-	queue.backingSlice[i], queue.backingSlice[j] = queue.backingSlice[j], queue.backingSlice[i]
-}
-
-func (queue *movementPriorityQueue) Push(x interface{}) {
-	queue.backingSlice = append(queue.backingSlice, x.(movement))
-}
-
-func (queue *movementPriorityQueue) Pop() interface{} {
-	el := queue.backingSlice[queue.Len()-1]
-	queue.backingSlice = queue.backingSlice[:queue.Len()-1]
-
-	return el
-}
-
-func (queue *movementPriorityQueue) contains(m movement) bool {
-	for _, item := range queue.backingSlice {
-		if item == m {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (queue *movementPriorityQueue) containsMovementWithDestination(dest coordinate) bool {
-	for _, item := range queue.backingSlice {
-		if item.endingState.destination == dest {
-			return true
-		}
-	}
-
-	return false
-}
-
 func (c coordinate) calculateNeighbors() []coordinate {
 	candidates := []coordinate{
 		{
@@ -146,10 +99,16 @@ func (c coordinate) calculateNeighbors() []coordinate {
 	return res
 }
 
-func getInputLineValue(line string) string {
-	components := strings.Split(line, inputDelim)
+// getInputLineValue gets the value from a line of the input, along with the index of the column it starts at
+func getInputLineValue(line string) (string, int, bool) {
+	delimIndex := strings.Index(line, inputDelim)
+	if delimIndex == -1 {
+		return "", 0, false
+	}
+
+	valueColumn := delimIndex + len(inputDelim)
 
-	return components[1]
+	return line[valueColumn:], valueColumn, true
 }
 
 func parseCoordinate(spec string) (coordinate, error) {
@@ -175,20 +134,39 @@ func parseCoordinate(spec string) (coordinate, error) {
 }
 
 func parseInput(inputLines []string) (caveSpec, error) {
-	if len(inputLines) > 2 {
-		return caveSpec{}, errors.New("input must have two lines")
+	if len(inputLines) != 2 {
+		// Point at the first line that shouldn't be there, or just past the end of the input if a line is missing
+		lineIndex := 2
+		if len(inputLines) < 2 {
+			lineIndex = len(inputLines)
+		}
+
+		text := ""
+		if lineIndex < len(inputLines) {
+			text = inputLines[lineIndex]
+		}
+
+		return caveSpec{}, aoc.NewParseError(22, lineIndex, -1, text, errors.New("input must have two lines"))
+	}
+
+	rawDepth, depthColumn, ok := getInputLineValue(inputLines[0])
+	if !ok {
+		return caveSpec{}, aoc.NewParseError(22, 0, -1, inputLines[0], aoc.ErrMalformedInput)
 	}
 
-	rawDepth := getInputLineValue(inputLines[0])
 	depth, err := strconv.Atoi(rawDepth)
 	if err != nil {
-		return caveSpec{}, fmt.Errorf("could not parse depth: %w", err)
+		return caveSpec{}, aoc.NewParseError(22, 0, depthColumn, inputLines[0], fmt.Errorf("could not parse depth: %w", err))
+	}
+
+	rawTarget, targetColumn, ok := getInputLineValue(inputLines[1])
+	if !ok {
+		return caveSpec{}, aoc.NewParseError(22, 1, -1, inputLines[1], aoc.ErrMalformedInput)
 	}
 
-	rawTarget := getInputLineValue(inputLines[1])
 	target, err := parseCoordinate(rawTarget)
 	if err != nil {
-		return caveSpec{}, fmt.Errorf("could not parse target: %w", err)
+		return caveSpec{}, aoc.NewParseError(22, 1, targetColumn, inputLines[1], fmt.Errorf("could not parse target: %w", err))
 	}
 
 	return caveSpec{depth: depth, target: target}, nil
@@ -260,29 +238,29 @@ func findPossibleToolsForMovement(spec caveSpec, origin coordinate, destination
 	return possibleTools
 }
 
-// Find all possible movements from the visiting location
+// Find all possible movements from the visiting state, along with the time each takes
 // Takes the erosion memo into account. Could technically be omitted but it would take too long without an existing memo.
-func calculateMovementCandidates(spec caveSpec, visiting movement, erosionMemo map[coordinate]int) []movement {
-	movementCandidates := []movement{}
-	for _, neighbor := range visiting.endingState.destination.calculateNeighbors() {
-		for _, toolCandidate := range findPossibleToolsForMovement(spec, visiting.endingState.destination, neighbor, erosionMemo) {
+func calculateMovementCandidates(spec caveSpec, visiting playerState, erosionMemo map[coordinate]int) []search.Edge[playerState] {
+	movementCandidates := []search.Edge[playerState]{}
+	for _, neighbor := range visiting.destination.calculateNeighbors() {
+		for _, toolCandidate := range findPossibleToolsForMovement(spec, visiting.destination, neighbor, erosionMemo) {
 			time := toolSwitchTime + movementTime
-			if toolCandidate == visiting.endingState.currentTool {
+			if toolCandidate == visiting.currentTool {
 				time -= toolSwitchTime
 			}
 
-			candidate := movement{
-				endingState: playerState{
+			candidate := search.Edge[playerState]{
+				To: playerState{
 					destination: neighbor,
 					currentTool: toolCandidate,
 				},
-				time: time,
+				Cost: time,
 			}
 
 			// We need to force a switch to the torch if the destination is the torch
-			if candidate.endingState.destination == spec.target && candidate.endingState.currentTool != toolTorch {
-				candidate.time += toolSwitchTime
-				candidate.endingState.currentTool = toolTorch
+			if candidate.To.destination == spec.target && candidate.To.currentTool != toolTorch {
+				candidate.Cost += toolSwitchTime
+				candidate.To.currentTool = toolTorch
 			}
 
 			movementCandidates = append(movementCandidates, candidate)
@@ -292,90 +270,125 @@ func calculateMovementCandidates(spec caveSpec, visiting movement, erosionMemo m
 	return movementCandidates
 }
 
-func part1(spec caveSpec) int {
+// estimateTime estimates the time it takes to get from a state to the target, which is at least the time it takes
+// to walk there, and then switch to the torch
+func estimateTime(spec caveSpec, state playerState) int {
+	distance := abs(spec.target.x-state.destination.x) + abs(spec.target.y-state.destination.y)
+	estimate := distance * movementTime
+	if state.currentTool != toolTorch {
+		estimate += toolSwitchTime
+	}
+
+	return estimate
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func part1(spec caveSpec, steps *aoc.Steps) (int, error) {
 	totalRisk := 0
 	// Since we're going over coordinates that will likely have been gone over, we need to keep a handle on the memo ourselves
 	// (need is a strong term - in truth, it takes about 5s without, but why wait? :))
 	memo := map[coordinate]int{}
 	for x := 0; x <= spec.target.x; x++ {
 		for y := 0; y <= spec.target.y; y++ {
+			if err := steps.Take(); err != nil {
+				return 0, err
+			}
+
 			cursor := coordinate{x, y}
 			erosionLevel := calculateTotalErosionLevelMemo(spec, cursor, memo)
 			totalRisk += calculateRisk(erosionLevel)
 		}
 	}
 
-	return totalRisk
+	return totalRisk, nil
 }
 
-func part2(spec caveSpec) int {
+func part2(spec caveSpec, steps *aoc.Steps) (int, error) {
 	erosionMemo := map[coordinate]int{}
-	times := map[playerState]int{
-		{destination: coordinate{x: 0, y: 0}, currentTool: toolTorch}: 0,
-	}
-	toVisit := &movementPriorityQueue{
-		backingSlice: []movement{},
-		times:        times,
-	}
+	start := playerState{destination: coordinate{x: 0, y: 0}, currentTool: toolTorch}
+	goal := playerState{destination: spec.target, currentTool: toolTorch}
+	// The cave goes on forever, so the search must stop once it finds the target, and is guided towards it to keep it
+	// from wandering too far. A step is taken for each state visited, and running out stops the search as if it were
+	// the goal.
+	var stepErr error
+	result := search.AStar(
+		start,
+		func(visiting playerState) []search.Edge[playerState] {
+			return calculateMovementCandidates(spec, visiting, erosionMemo)
+		},
+		func(state playerState) bool {
+			stepErr = steps.Take()
 
-	heap.Init(toVisit)
-	heap.Push(toVisit, movement{
-		endingState: playerState{
-			destination: coordinate{x: 0, y: 0},
-			currentTool: toolTorch,
+			return stepErr != nil || state == goal
 		},
-		time: 0,
-	})
-	// Uses Djikstra, specifically a variant that terminates once we find the specific item, rather than searching the full graph (since that's infinite)
-	for toVisit.Len() > 0 && !toVisit.containsMovementWithDestination(spec.target) {
-		visiting := heap.Pop(toVisit).(movement)
-		visitingState := playerState{destination: visiting.endingState.destination, currentTool: visiting.endingState.currentTool}
-		movementCandidates := calculateMovementCandidates(spec, visiting, erosionMemo)
-		currentTime, ok := times[visitingState]
-		if !ok {
-			panic("this can literally never happen, but somehow, we attempted to visit a location without a ne entry in the time table")
-		}
+		func(state playerState) int {
+			return estimateTime(spec, state)
+		},
+	)
 
-		for _, candidate := range movementCandidates {
-			candidateTime := currentTime + candidate.time
-			candidateState := playerState{destination: candidate.endingState.destination, currentTool: candidate.endingState.currentTool}
+	if stepErr != nil {
+		return 0, stepErr
+	}
 
-			currentTimeForCandidate, haveTimeForCandidate := times[candidateState]
-			if haveTimeForCandidate && candidateTime >= currentTimeForCandidate {
-				continue
-			}
+	time, _ := result.Distance(goal)
 
-			times[candidateState] = candidateTime
-			if !toVisit.contains(candidate) {
-				heap.Push(toVisit, candidate)
-			}
-		}
+	return time, nil
+}
 
-	}
+func init() {
+	aoc.Register(22, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(22, generate.Generator{Size: "rows down to the target", DefaultSize: 750, Generate: Generate})
+}
 
-	return times[playerState{destination: spec.target, currentTool: toolTorch}]
+// Input is the cave's depth and the target's location
+type Input struct {
+	spec caveSpec
 }
 
-func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: ./main in_file")
-		return
+// Parse parses the cave's depth and the target's location
+func Parse(reader io.Reader) (Input, error) {
+	inputLines, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
 	}
 
-	inFile := os.Args[1]
-	inFileContents, err := ioutil.ReadFile(inFile)
+	spec, err := parseInput(inputLines)
 	if err != nil {
-		panic(err)
+		return Input{}, err
 	}
 
-	splitContents := strings.Split(string(inFileContents), "\n")
-	splitContents = splitContents[:len(splitContents)-1]
+	return Input{spec: spec}, nil
+}
 
-	spec, err := parseInput(splitContents)
-	if err != nil {
-		panic(err)
-	}
+// Part1 finds the total risk level of the rectangle between the mouth of the cave and the target
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part1(input.spec, budget.Start(ctx))
+}
+
+// Part2 finds the fewest minutes it takes to reach the target
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part2(input.spec, budget.Start(ctx))
+}
 
-	fmt.Println(part1(spec))
-	fmt.Println(part2(spec))
+// Generate makes a cave spec with a random depth, and a target size rows down and a few columns across
+func Generate(rng *rand.Rand, size int) string {
+	return generate.Lines([]string{
+		fmt.Sprintf("depth%s%d", inputDelim, generate.Between(rng, minGeneratedDepth, maxGeneratedDepth)),
+		fmt.Sprintf("target%s%d,%d", inputDelim, generate.Between(rng, minGeneratedTargetX, maxGeneratedTargetX), size),
+	})
 }
@@ -0,0 +1,265 @@
+// Package day3 solves the puzzle for day 3 of Advent of Code 2018
+package day3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"strconv"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+)
+
+const (
+	// fabricSize is the width and height of the fabric generated claims are on
+	fabricSize = 1000
+	// minClaimSize and maxClaimSize bound the width and height of generated claims
+	minClaimSize = 10
+	maxClaimSize = 29
+	// minGeneratedClaims is the fewest claims needed for every claim but one to overlap another
+	minGeneratedClaims = 3
+)
+
+var piecePattern = regexp.MustCompile(`^#(\d+) @ (\d+),(\d+): (\d+)x(\d+)$`)
+
+type piece struct {
+	id     int
+	row    int
+	col    int
+	height int
+	width  int
+}
+
+func parsePuzzleLine(line string) (piece, error) {
+	matches := piecePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return piece{}, aoc.ErrMalformedInput
+	}
+
+	parsedPiece := piece{}
+	var err error
+	parsedPiece.id, err = strconv.Atoi(matches[1])
+	if err != nil {
+		return piece{}, err
+	}
+
+	parsedPiece.col, err = strconv.Atoi(matches[2])
+	if err != nil {
+		return piece{}, err
+	}
+
+	parsedPiece.row, err = strconv.Atoi(matches[3])
+	if err != nil {
+		return piece{}, err
+	}
+
+	parsedPiece.width, err = strconv.Atoi(matches[4])
+	if err != nil {
+		return piece{}, err
+	}
+
+	parsedPiece.height, err = strconv.Atoi(matches[5])
+	if err != nil {
+		return piece{}, err
+	}
+
+	return parsedPiece, nil
+}
+
+func parseInput(rawPieces []string) ([]piece, error) {
+	pieces := make([]piece, 0, len(rawPieces))
+	for i, rawPiece := range rawPieces {
+		parsedPiece, err := parsePuzzleLine(rawPiece)
+		if err != nil {
+			return nil, aoc.NewParseError(3, i, -1, rawPiece, err)
+		}
+
+		pieces = append(pieces, parsedPiece)
+	}
+
+	return pieces, nil
+}
+
+// rect gets the square inches the piece covers
+func (p piece) rect() grid.Rect {
+	return grid.Rect{
+		Min: grid.Point{Row: p.row, Col: p.col},
+		Max: grid.Point{Row: p.row + p.height, Col: p.col + p.width},
+	}
+}
+
+// overlaps checks if two pieces claim any of the same square inches
+func (p piece) overlaps(other piece) bool {
+	return p.col < other.col+other.width && other.col < p.col+p.width &&
+		p.row < other.row+other.height && other.row < p.row+p.height
+}
+
+func isClaimComplete(checkPiece piece, cloth *grid.Dense[int]) bool {
+	for p := range checkPiece.rect().Points() {
+		if cloth.Get(p) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// makeCloth makes a cloth that fits every piece, with the number of pieces that claim each square. A step is taken for
+// each piece.
+func makeCloth(pieces []piece, steps *aoc.Steps) (*grid.Dense[int], error) {
+	bounds := grid.Rect{}
+	for _, clothPiece := range pieces {
+		// Extend out to the bottom right corner, keeping the top left of the cloth at 0,0
+		bounds.Max.Row = max(bounds.Max.Row, clothPiece.rect().Max.Row)
+		bounds.Max.Col = max(bounds.Max.Col, clothPiece.rect().Max.Col)
+	}
+
+	cloth := grid.NewDense[int](bounds)
+	for _, insertingPiece := range pieces {
+		if err := steps.Take(); err != nil {
+			return nil, err
+		}
+
+		for p := range insertingPiece.rect().Points() {
+			cloth.Set(p, cloth.Get(p)+1)
+		}
+	}
+
+	return cloth, nil
+}
+
+func part1(pieces []piece, steps *aoc.Steps) (int, error) {
+	cloth, err := makeCloth(pieces, steps)
+	if err != nil {
+		return 0, err
+	}
+
+	intersectCount := 0
+	for _, numClaims := range cloth.All() {
+		if numClaims > 1 {
+			intersectCount++
+		}
+	}
+
+	return intersectCount, nil
+}
+
+func part2(pieces []piece, steps *aoc.Steps) (int, error) {
+	cloth, err := makeCloth(pieces, steps)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, checkPiece := range pieces {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		if isClaimComplete(checkPiece, cloth) {
+			return checkPiece.id, nil
+		}
+	}
+
+	return 0, aoc.ErrNoAnswer
+}
+
+func init() {
+	aoc.Register(3, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(3, generate.Generator{Size: "claims", DefaultSize: 1300, Generate: Generate})
+}
+
+// Input is the list of claims on the fabric
+type Input struct {
+	pieces []piece
+}
+
+// Parse parses the list of claims
+func Parse(reader io.Reader) (Input, error) {
+	rawPieces, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	pieces, err := parseInput(rawPieces)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{pieces: pieces}, nil
+}
+
+// Part1 finds how many square inches of fabric are within two or more claims
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part1(input.pieces, budget.Start(ctx))
+}
+
+// Part2 finds the ID of the only claim that does not overlap any other
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part2(input.pieces, budget.Start(ctx))
+}
+
+// Generate makes a list of size claims, or three if size is smaller, of which exactly one overlaps no other claim
+func Generate(rng *rand.Rand, size int) string {
+	randomSize := func() (int, int) {
+		return generate.Between(rng, minClaimSize, maxClaimSize), generate.Between(rng, minClaimSize, maxClaimSize)
+	}
+
+	width, height := randomSize()
+	intact := piece{
+		col:    rng.Intn(fabricSize - width + 1),
+		row:    rng.Intn(fabricSize - height + 1),
+		width:  width,
+		height: height,
+	}
+
+	// Every other claim is placed over one of the claims before it, so that the first of them is covered by the second,
+	// keeping them all clear of the intact claim
+	numClaims := max(size, minGeneratedClaims)
+	pieces := make([]piece, 0, numClaims)
+	for len(pieces) < numClaims-1 {
+		width, height := randomSize()
+		candidate := piece{width: width, height: height}
+		if len(pieces) == 0 {
+			candidate.col = rng.Intn(fabricSize - width + 1)
+			candidate.row = rng.Intn(fabricSize - height + 1)
+		} else {
+			below := pieces[rng.Intn(len(pieces))]
+			candidate.col = clamp(generate.Between(rng, below.col-width+1, below.col+below.width-1), 0, fabricSize-width)
+			candidate.row = clamp(generate.Between(rng, below.row-height+1, below.row+below.height-1), 0, fabricSize-height)
+		}
+
+		if candidate.overlaps(intact) {
+			continue
+		}
+
+		pieces = append(pieces, candidate)
+	}
+
+	pieces = append(pieces, intact)
+	rng.Shuffle(len(pieces), func(i, j int) {
+		pieces[i], pieces[j] = pieces[j], pieces[i]
+	})
+
+	lines := make([]string, len(pieces))
+	for i, claim := range pieces {
+		lines[i] = fmt.Sprintf("#%d @ %d,%d: %dx%d", i+1, claim.col, claim.row, claim.width, claim.height)
+	}
+
+	return generate.Lines(lines)
+}
+
+func clamp(n int, low int, high int) int {
+	return min(max(n, low), high)
+}
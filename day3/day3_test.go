@@ -0,0 +1,60 @@
+package day3
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = `#1 @ 1,3: 4x4
+#2 @ 3,1: 4x4
+#3 @ 5,5: 2x2
+`
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name string
+		part func(context.Context, Input, aoc.Budget) (int, error)
+		want int
+	}{
+		{"part1", Part1, 4},
+		{"part2", Part2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Parse(strings.NewReader(example))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.part(context.Background(), input, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse(strings.NewReader("#1 @ 1,3: 4x4\n#2 @ 3,1 4x4\n"))
+	parseErr, ok := err.(*aoc.ParseError)
+	if !ok {
+		t.Fatalf("got error %v, want a parse error", err)
+	} else if parseErr.Line != 2 {
+		t.Errorf("got line %d, want 2", parseErr.Line)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 3)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 3)
+}
@@ -0,0 +1,292 @@
+// Package day4 solves the puzzle for day 4 of Advent of Code 2018
+package day4
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+type guardAction int
+
+const (
+	startShift guardAction = iota
+	fallAsleep
+	wakeUp
+)
+
+const (
+	timeFormat         = "2006-01-02 15:04"
+	logLineFormat      = "[%s] %s"
+	asleepTrigger      = "falls asleep"
+	wakeupTrigger      = "wakes up"
+	shiftTriggerFormat = "Guard #%d begins shift"
+)
+
+const (
+	// shiftsPerGuard is roughly how many shifts each guard works in a generated log
+	shiftsPerGuard = 20
+	// maxGuardID is the largest ID a generated guard can have
+	maxGuardID = 3500
+	// maxNaps is the most times a guard falls asleep during a generated shift
+	maxNaps = 3
+	// earliestShiftStart and latestShiftStart are how many minutes before and after midnight a generated shift can start
+	earliestShiftStart = 15
+	latestShiftStart   = 3
+)
+
+type logLine struct {
+	actionTime time.Time
+	guardID    int
+	action     guardAction
+}
+
+// A list that implements sort's Interface interface
+type logData []logLine
+
+func (data logData) Len() int {
+	return len(data)
+}
+
+func (data logData) Swap(i int, j int) {
+	data[i], data[j] = data[j], data[i]
+}
+
+func (data logData) Less(i int, j int) bool {
+	return data[i].actionTime.Before(data[j].actionTime)
+}
+
+// parseLogLine parses a single line of the log, which is at the given index
+func parseLogLine(lineIndex int, line string) (logLine, error) {
+	// Split the timestamp from the action
+	lineComponents := strings.SplitN(line, "] ", 2)
+	if len(lineComponents) != 2 || !strings.HasPrefix(line, "[") {
+		return logLine{}, aoc.NewParseError(4, lineIndex, -1, line, aoc.ErrMalformedInput)
+	}
+
+	// Remove the leading bracket from the time and store the components
+	rawTime, action := lineComponents[0][1:], lineComponents[1]
+	actionColumn := len(lineComponents[0]) + 2
+	parsedTime, err := time.Parse(timeFormat, rawTime)
+	if err != nil {
+		return logLine{}, aoc.NewParseError(4, lineIndex, 1, line, err)
+	}
+
+	lineInfo := logLine{actionTime: parsedTime, guardID: -1}
+	if action == asleepTrigger {
+		lineInfo.action = fallAsleep
+		return lineInfo, nil
+	} else if action == wakeupTrigger {
+		lineInfo.action = wakeUp
+		return lineInfo, nil
+	}
+
+	// Get guard number for shift start
+	numMatched, err := fmt.Sscanf(action, shiftTriggerFormat, &lineInfo.guardID)
+	if err != nil {
+		return logLine{}, aoc.NewParseError(4, lineIndex, actionColumn, line, err)
+	} else if numMatched != 1 {
+		return logLine{}, aoc.NewParseError(4, lineIndex, actionColumn, line, aoc.ErrMalformedInput)
+	}
+
+	return lineInfo, nil
+}
+
+// parseLog parses all log lines and returns a sorted output
+func parseLog(logLines []string) ([]logLine, error) {
+	result := make(logData, 0, len(logLines))
+	for i, line := range logLines {
+		lineInfo, err := parseLogLine(i, line)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lineInfo)
+	}
+
+	sort.Sort(result)
+
+	return result, nil
+}
+
+// getSleepInfo gets the amount of time the guard is asleep and their sleepiest time
+func getSleepInfo(sleepTimes []int) (totalTimeSlept int, sleepiestTime int) {
+	mostTimeSlept := 0
+	for sleepTime, timeSlept := range sleepTimes {
+		totalTimeSlept += timeSlept
+		if timeSlept > mostTimeSlept {
+			mostTimeSlept = timeSlept
+			sleepiestTime = sleepTime
+		}
+	}
+	return
+}
+
+// constructSleepLog construts a log of the number of times a guard id (key) was asleep at the given time (index in value slice)
+func constructSleepLog(log logData) map[int][]int {
+	sleepLog := make(map[int][]int)
+	currentGuard := -1
+	sleepTime := time.Time{}
+	for _, lineInfo := range log {
+		if lineInfo.guardID != -1 {
+			currentGuard = lineInfo.guardID
+		}
+		if sleepLog[currentGuard] == nil {
+			sleepLog[currentGuard] = make([]int, 60)
+		}
+		if lineInfo.action == fallAsleep {
+			sleepTime = lineInfo.actionTime
+		} else if lineInfo.action == wakeUp {
+			for i := sleepTime.Minute(); i < lineInfo.actionTime.Minute(); i++ {
+				sleepLog[currentGuard][i]++
+			}
+		}
+	}
+
+	return sleepLog
+}
+
+func part1(sleepLog map[int][]int, steps *aoc.Steps) (int, error) {
+	sleepiestGuard := -1
+	sleepiestGuardTime := 0
+	sleepiestTimeForGuard := -1
+	for guardID, guardInfo := range sleepLog {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		totalSleepTime, sleepiestTime := getSleepInfo(guardInfo)
+		if totalSleepTime > sleepiestGuardTime {
+			sleepiestGuard = guardID
+			sleepiestGuardTime = totalSleepTime
+			sleepiestTimeForGuard = sleepiestTime
+		}
+	}
+
+	return sleepiestGuard * sleepiestTimeForGuard, nil
+}
+
+func part2(sleepLog map[int][]int, steps *aoc.Steps) (int, error) {
+	mostTimeSlept := -1
+	sleepiestMinute := -1
+	sleepiestGuard := -1
+	for minute := 0; minute < 60; minute++ {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		for guardID, guardInfo := range sleepLog {
+			timeSlept := guardInfo[minute]
+			if timeSlept > mostTimeSlept {
+				mostTimeSlept = timeSlept
+				sleepiestMinute = minute
+				sleepiestGuard = guardID
+			}
+		}
+	}
+
+	return sleepiestMinute * sleepiestGuard, nil
+}
+
+func init() {
+	aoc.Register(4, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(4, generate.Generator{Size: "shifts", DefaultSize: 350, Generate: Generate})
+}
+
+// Input is the number of times each guard was asleep at each minute of the midnight hour
+type Input struct {
+	sleepLog map[int][]int
+}
+
+// Parse parses the guards' log
+func Parse(reader io.Reader) (Input, error) {
+	logLines, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	parsedLog, err := parseLog(logLines)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{sleepLog: constructSleepLog(parsedLog)}, nil
+}
+
+// Part1 multiplies the ID of the guard that slept the most by the minute they were most often asleep
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	if len(input.sleepLog) == 0 {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return part1(input.sleepLog, budget.Start(ctx))
+}
+
+// Part2 multiplies the ID of the guard that was most often asleep on the same minute by that minute
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	if len(input.sleepLog) == 0 {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return part2(input.sleepLog, budget.Start(ctx))
+}
+
+// Generate makes a log of size shifts, one a night, in no particular order like the real log. Every guard falls asleep
+// and wakes up within the midnight hour, and at least one guard falls asleep.
+func Generate(rng *rand.Rand, size int) string {
+	guardIDs := rng.Perm(maxGuardID)[:min(max(size/shiftsPerGuard, 1), maxGuardID)]
+	firstNight := time.Date(1518, time.January, 1, 0, 0, 0, 0, time.UTC)
+	logLines := []string{}
+	addLine := func(actionTime time.Time, action string) {
+		logLines = append(logLines, fmt.Sprintf(logLineFormat, actionTime.Format(timeFormat), action))
+	}
+
+	for night := 0; night < size; night++ {
+		midnight := firstNight.AddDate(0, 0, night)
+		shiftStart := midnight.Add(time.Duration(generate.Between(rng, -earliestShiftStart, latestShiftStart)) * time.Minute)
+		addLine(shiftStart, fmt.Sprintf(shiftTriggerFormat, guardIDs[rng.Intn(len(guardIDs))]+1))
+
+		// The guard can only fall asleep after their shift starts
+		firstMinute := 0
+		if shiftStart.After(midnight) {
+			firstMinute = shiftStart.Minute() + 1
+		}
+
+		numNaps := rng.Intn(maxNaps + 1)
+		if night == 0 {
+			numNaps = max(numNaps, 1)
+		}
+
+		// Each nap takes two of the minutes, one to fall asleep on, and a later one to wake up on
+		napMinutes := rng.Perm(60 - firstMinute)[:numNaps*2]
+		sort.Ints(napMinutes)
+		for i, minute := range napMinutes {
+			action := asleepTrigger
+			if i%2 == 1 {
+				action = wakeupTrigger
+			}
+
+			addLine(midnight.Add(time.Duration(firstMinute+minute)*time.Minute), action)
+		}
+	}
+
+	rng.Shuffle(len(logLines), func(i, j int) {
+		logLines[i], logLines[j] = logLines[j], logLines[i]
+	})
+
+	return generate.Lines(logLines)
+}
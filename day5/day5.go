@@ -0,0 +1,138 @@
+// Package day5 solves the puzzle for day 5 of Advent of Code 2018
+package day5
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"strings"
+	"unicode"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+func shouldAnihalate(chain string) bool {
+	firstElement := rune(chain[0])
+	secondElement := rune(chain[1])
+	if firstElement == secondElement {
+		return false
+	}
+	if firstElement == unicode.ToLower(secondElement) || firstElement == unicode.ToUpper(secondElement) {
+		return true
+	}
+
+	return false
+}
+
+// performReaction reacts the polymer, taking a step for each unit
+func performReaction(polymer string, steps *aoc.Steps) (int, error) {
+	reaction_buffer := make([]rune, 0, len(polymer))
+	for _, chr := range polymer {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		if len(reaction_buffer) > 0 && shouldAnihalate(string(chr)+string(reaction_buffer[len(reaction_buffer)-1])) {
+			reaction_buffer = reaction_buffer[:len(reaction_buffer)-1]
+		} else {
+			reaction_buffer = append(reaction_buffer, chr)
+		}
+	}
+
+	return len(reaction_buffer), nil
+}
+
+func part1(polymer string, steps *aoc.Steps) (int, error) {
+	return performReaction(polymer, steps)
+}
+
+func part2(polymer string, steps *aoc.Steps) (int, error) {
+	smallestLen := len(polymer)
+	for element := 'a'; element <= 'z'; element++ {
+		strippedPolymer := polymer
+		strippedPolymer = strings.Replace(strippedPolymer, string(element), "", -1)
+		strippedPolymer = strings.Replace(strippedPolymer, strings.ToUpper(string(element)), "", -1)
+		reactedLen, err := part1(strippedPolymer, steps)
+		if err != nil {
+			return 0, err
+		}
+
+		if reactedLen < smallestLen {
+			smallestLen = reactedLen
+		}
+	}
+
+	return smallestLen, nil
+}
+
+func init() {
+	aoc.Register(5, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(string), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(string), budget)
+		},
+	})
+	generate.Register(5, generate.Generator{Size: "units", DefaultSize: 50000, Generate: Generate})
+}
+
+// Parse parses the polymer
+func Parse(reader io.Reader) (string, error) {
+	polymer, err := aoc.ReadString(reader)
+	if err != nil {
+		return "", err
+	}
+
+	// Every unit is a letter, with its case giving its polarity
+	badUnit := strings.IndexFunc(polymer, func(unit rune) bool {
+		return unit > unicode.MaxASCII || !unicode.IsLetter(unit)
+	})
+	if badUnit != -1 {
+		return "", aoc.NewParseError(5, 0, badUnit, polymer, aoc.ErrMalformedInput)
+	}
+
+	return polymer, nil
+}
+
+// Part1 finds the length of the polymer once it has fully reacted
+func Part1(ctx context.Context, polymer string, budget aoc.Budget) (int, error) {
+	return part1(polymer, budget.Start(ctx))
+}
+
+// Part2 finds the length of the shortest polymer that can be made by removing a single unit type and fully reacting it
+func Part2(ctx context.Context, polymer string, budget aoc.Budget) (int, error) {
+	return part2(polymer, budget.Start(ctx))
+}
+
+// Generate makes a polymer of size units. Like the real polymer, most of it reacts away: each unit either starts a new
+// chain, or reacts with the last unit whose chain is still open, leaving behind the units that were never reacted with.
+func Generate(rng *rand.Rand, size int) string {
+	polymer := make([]rune, 0, size)
+	open := []rune{}
+	for len(polymer) < size {
+		if len(open) > 0 && rng.Intn(2) == 0 {
+			unit := open[len(open)-1]
+			open = open[:len(open)-1]
+			if unicode.IsUpper(unit) {
+				polymer = append(polymer, unicode.ToLower(unit))
+			} else {
+				polymer = append(polymer, unicode.ToUpper(unit))
+			}
+
+			continue
+		}
+
+		unit := 'a' + rune(rng.Intn(26))
+		if rng.Intn(2) == 0 {
+			unit = unicode.ToUpper(unit)
+		}
+
+		polymer = append(polymer, unit)
+		open = append(open, unit)
+	}
+
+	return string(polymer) + "\n"
+}
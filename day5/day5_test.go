@@ -0,0 +1,43 @@
+package day5
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name    string
+		polymer string
+		part    func(context.Context, string, aoc.Budget) (int, error)
+		want    int
+	}{
+		{"part1 example", "dabAcCaCBAcCcaDA", Part1, 10},
+		{"part1 fully reacts", "aA", Part1, 0},
+		{"part1 nested reaction", "abBA", Part1, 0},
+		{"part1 same polarity", "aabAAB", Part1, 6},
+		{"part2 example", "dabAcCaCBAcCcaDA", Part2, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.part(context.Background(), tt.polymer, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 5)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 5)
+}
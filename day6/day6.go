@@ -0,0 +1,253 @@
+// Package day6 solves the puzzle for day 6 of Advent of Code 2018
+package day6
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+	"github.com/ollien/advent-of-code-2018/grid"
+)
+
+// safeDistance is the total distance to every coordinate that a location must be under to be in the safe region
+const safeDistance = 10000
+
+// generatedSpread, times the square root of the number of coordinates, is the width of the square that generated
+// coordinates are spread over, so that they are as crowded however many there are
+const generatedSpread = 50
+
+func parseCoords(rawCoords []string) ([]grid.Point, error) {
+	coords := make([]grid.Point, 0, len(rawCoords))
+	for i, rawCoordPair := range rawCoords {
+		var coordPair grid.Point
+		numMatched, err := fmt.Sscanf(rawCoordPair, "%d, %d", &coordPair.Col, &coordPair.Row)
+		if err != nil {
+			return nil, aoc.NewParseError(6, i, -1, rawCoordPair, err)
+		} else if numMatched != 2 || coordPair.Col < 0 || coordPair.Row < 0 {
+			return nil, aoc.NewParseError(6, i, -1, rawCoordPair, aoc.ErrMalformedInput)
+		}
+
+		coords = append(coords, coordPair)
+	}
+
+	return coords, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func manhattanDistance(a, b grid.Point) int {
+	return abs(a.Row-b.Row) + abs(a.Col-b.Col)
+}
+
+// findClosest the index to the closest coordinate from the input file, or -1 if two are equally distant
+func findClosest(loc grid.Point, coords []grid.Point) int {
+	minIndex := -1
+	minDistance := math.MaxInt32
+	multipleDistances := false
+	for i, coord := range coords {
+		distance := manhattanDistance(coord, loc)
+		if distance < minDistance {
+			minDistance = distance
+			minIndex = i
+			multipleDistances = false
+		} else if distance == minDistance {
+			multipleDistances = true
+		}
+	}
+
+	if multipleDistances {
+		return -1
+	} else {
+		return minIndex
+	}
+}
+
+// populateBoardWithNearest marks every location with the coordinate closest to it, taking a step for each location
+func populateBoardWithNearest(board *grid.Dense[int], coords []grid.Point, steps *aoc.Steps) error {
+	for loc := range board.Bounds().Points() {
+		if err := steps.Take(); err != nil {
+			return err
+		}
+
+		board.Set(loc, findClosest(loc, coords))
+	}
+
+	return nil
+}
+
+// findTotalOfSitances finds the total of the distances to a location from the coordinates
+func findTotalOfDistances(loc grid.Point, coords []grid.Point) int {
+	totalDistance := 0
+	for _, coord := range coords {
+		totalDistance += manhattanDistance(coord, loc)
+	}
+
+	return totalDistance
+}
+
+// populateBoardWithDistance marks every location with its total distance to the coordinates, taking a step for each
+// location
+func populateBoardWithDistance(board *grid.Dense[int], coords []grid.Point, steps *aoc.Steps) error {
+	for loc := range board.Bounds().Points() {
+		if err := steps.Take(); err != nil {
+			return err
+		}
+
+		board.Set(loc, findTotalOfDistances(loc, coords))
+	}
+
+	return nil
+}
+
+// isBounded returns true if a board region has a finite area
+func isBounded(board *grid.Dense[int], loc grid.Point) bool {
+	bounds := board.Bounds()
+	region := board.Get(loc)
+	edges := []grid.Point{
+		{Row: loc.Row, Col: bounds.Min.Col},
+		{Row: loc.Row, Col: bounds.Max.Col - 1},
+		{Row: bounds.Min.Row, Col: loc.Col},
+		{Row: bounds.Max.Row - 1, Col: loc.Col},
+	}
+	for _, edge := range edges {
+		if board.Get(edge) == region {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getAreas Returns the areas of all regions, with each region at the index of the returned slice
+func getAreas(board *grid.Dense[int], numCoords int) []int {
+	areas := make([]int, numCoords)
+	for _, closest := range board.All() {
+		if closest != -1 {
+			areas[closest]++
+		}
+	}
+
+	return areas
+}
+
+func part1(board *grid.Dense[int], coords []grid.Point, steps *aoc.Steps) (int, error) {
+	if err := populateBoardWithNearest(board, coords, steps); err != nil {
+		return 0, err
+	}
+
+	areas := getAreas(board, len(coords))
+	largestArea := 0
+	for i, area := range areas {
+		if isBounded(board, coords[i]) && area > largestArea {
+			largestArea = area
+		}
+	}
+
+	return largestArea, nil
+}
+
+// part2 finds the number of locations whose total distance to every coordinate is less than maxDistance
+func part2(board *grid.Dense[int], coords []grid.Point, maxDistance int, steps *aoc.Steps) (int, error) {
+	if err := populateBoardWithDistance(board, coords, steps); err != nil {
+		return 0, err
+	}
+
+	safeTiles := 0
+	for _, totalDistance := range board.All() {
+		if totalDistance < maxDistance {
+			safeTiles++
+		}
+	}
+
+	return safeTiles, nil
+}
+
+// makeBoard makes an empty board that fits every coordinate
+func makeBoard(coords []grid.Point) *grid.Dense[int] {
+	bounds := grid.Rect{}
+	for _, coord := range coords {
+		bounds.Max.Row = max(bounds.Max.Row, coord.Row+1)
+		bounds.Max.Col = max(bounds.Max.Col, coord.Col+1)
+	}
+
+	return grid.NewDense[int](bounds)
+}
+
+func init() {
+	aoc.Register(6, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(6, generate.Generator{Size: "coordinates", DefaultSize: 50, Generate: Generate})
+}
+
+// Input is the list of coordinates
+type Input struct {
+	coords []grid.Point
+}
+
+// Parse parses the list of coordinates
+func Parse(reader io.Reader) (Input, error) {
+	rawCoords, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	coords, err := parseCoords(rawCoords)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{coords: coords}, nil
+}
+
+// Part1 finds the size of the largest area that isn't infinite
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	if len(input.coords) == 0 {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return part1(makeBoard(input.coords), input.coords, budget.Start(ctx))
+}
+
+// Part2 finds the size of the region of locations whose total distance to every coordinate is less than 10000
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	if len(input.coords) == 0 {
+		return 0, aoc.ErrNoAnswer
+	}
+
+	return part2(makeBoard(input.coords), input.coords, safeDistance, budget.Start(ctx))
+}
+
+// Generate makes a list of size distinct coordinates
+func Generate(rng *rand.Rand, size int) string {
+	spread := int(generatedSpread * math.Sqrt(float64(size)))
+	seen := make(map[grid.Point]bool, size)
+	lines := make([]string, 0, size)
+	for len(lines) < size {
+		coord := grid.Point{Row: rng.Intn(spread), Col: rng.Intn(spread)}
+		if seen[coord] {
+			continue
+		}
+
+		seen[coord] = true
+		lines = append(lines, fmt.Sprintf("%d, %d", coord.Col, coord.Row))
+	}
+
+	return generate.Lines(lines)
+}
@@ -0,0 +1,55 @@
+package day6
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = `1, 1
+1, 6
+8, 3
+3, 4
+5, 5
+8, 9
+`
+
+func TestPart1(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), input, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 17 {
+		t.Errorf("got %d, want 17", got)
+	}
+}
+
+func TestPart2(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The example uses a much smaller distance than the real puzzle
+	got, err := part2(makeBoard(input.coords), input.coords, 32, aoc.Budget{}.Start(context.Background()))
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 16 {
+		t.Errorf("got %d, want 16", got)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 6)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 6)
+}
@@ -0,0 +1,364 @@
+// Package day7 solves the puzzle for day 7 of Advent of Code 2018
+package day7
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const (
+	instructionStringFormat = "Step %s must be finished before step %s can begin."
+	noeEntrypointError      = "no entrypoint"
+	numWorkers              = 5
+	baseStepTime            = 60
+	// maxSteps is the number of steps there are letters for
+	maxSteps = 26
+	// maxExtraDependencies is the most dependencies a generated step has beyond the one that every step but the last
+	// is given
+	maxExtraDependencies = 2
+)
+
+// errDependencyCycle is held by the ParseError for steps that depend on each other, so can never be done
+var errDependencyCycle = errors.New("steps depend on each other")
+
+type instructionList map[string][]string
+type workerList []workerJob
+
+type workerJob struct {
+	name           string
+	stepsRemaining int
+}
+
+func parseInstructions(rawInstructions []string) (instructionList, error) {
+	instructions := make(instructionList)
+	// requirements holds the dependency and instruction named on each line, to find the line at fault in a cycle
+	requirements := make([][2]string, len(rawInstructions))
+	for i, rawInstruction := range rawInstructions {
+		var dependencyName, instructionName string
+		numMatched, err := fmt.Sscanf(rawInstruction, instructionStringFormat, &dependencyName, &instructionName)
+		if err != nil {
+			return nil, aoc.NewParseError(7, i, -1, rawInstruction, err)
+		} else if numMatched != 2 {
+			return nil, aoc.NewParseError(7, i, -1, rawInstruction, aoc.ErrMalformedInput)
+		}
+		requirements[i] = [2]string{dependencyName, instructionName}
+		_, hasDependency := instructions[dependencyName]
+		if !hasDependency {
+			instructions[dependencyName] = make([]string, 0)
+		}
+		_, hasInstruction := instructions[instructionName]
+		if hasInstruction {
+			instructions[instructionName] = append(instructions[instructionName], dependencyName)
+		} else {
+			instructions[instructionName] = []string{dependencyName}
+		}
+	}
+
+	for instructionName := range instructions {
+		sort.Strings(instructions[instructionName])
+	}
+
+	stuckSteps := instructions.clone().findStuckSteps()
+	if len(stuckSteps) == 0 {
+		return instructions, nil
+	}
+
+	// Point at the first requirement between two of the stuck steps, which must be part of, or follow on from, a cycle
+	stuck := make(map[string]bool, len(stuckSteps))
+	for _, stepName := range stuckSteps {
+		stuck[stepName] = true
+	}
+	err := fmt.Errorf("%w, so %s can never be done", errDependencyCycle, strings.Join(stuckSteps, ", "))
+	for i, requirement := range requirements {
+		if stuck[requirement[0]] && stuck[requirement[1]] {
+			return nil, aoc.NewParseError(7, i, -1, rawInstructions[i], err)
+		}
+	}
+
+	return nil, err
+}
+
+// clone makes a copy of the instructions, so they can be marked as done without modifying the original
+func (instructions instructionList) clone() instructionList {
+	clonedInstructions := make(instructionList, len(instructions))
+	for instruction, dependencies := range instructions {
+		clonedInstructions[instruction] = make([]string, len(dependencies))
+		copy(clonedInstructions[instruction], dependencies)
+	}
+
+	return clonedInstructions
+}
+
+func (instructions instructionList) findReadySteps() []string {
+	entrypointCandidates := make([]string, 0)
+	for instructionName := range instructions {
+		if len(instructions[instructionName]) == 0 {
+			entrypointCandidates = append(entrypointCandidates, instructionName)
+		}
+	}
+
+	sort.Strings(entrypointCandidates)
+
+	return entrypointCandidates
+}
+
+// findStuckSteps does every step it can, in any order, and gets the sorted names of the steps left that depend on each
+// other, or on those steps
+func (instructions instructionList) findStuckSteps() []string {
+	for readySteps := instructions.findReadySteps(); len(readySteps) > 0; readySteps = instructions.findReadySteps() {
+		for _, readyStep := range readySteps {
+			instructions.markAsDone(readyStep)
+		}
+	}
+
+	stuckSteps := make([]string, 0, len(instructions))
+	for instructionName := range instructions {
+		stuckSteps = append(stuckSteps, instructionName)
+	}
+	sort.Strings(stuckSteps)
+
+	return stuckSteps
+}
+
+func (instructions instructionList) markAsDone(doneInstructionName string) {
+	for instructionName, instruction := range instructions {
+		doneIndex := -1
+		for i, dependencyName := range instruction {
+			if dependencyName == doneInstructionName {
+				doneIndex = i
+				break
+			}
+		}
+		if doneIndex != -1 {
+			instructions[instructionName] = append(instruction[:doneIndex], instruction[doneIndex+1:]...)
+		}
+	}
+
+	delete(instructions, doneInstructionName)
+}
+
+func (instructions instructionList) resolveDependencies(steps *aoc.Steps) (string, error) {
+	instructionSet := ""
+	for len(instructions) > 0 {
+		if err := steps.Take(); err != nil {
+			return "", err
+		}
+
+		// Only the first ready step is done, as finishing it may make an earlier step in the alphabet ready.
+		// There is always one, as Parse rejects steps that depend on each other.
+		readyStep := instructions.findReadySteps()[0]
+		instructions.markAsDone(readyStep)
+		instructionSet += readyStep
+	}
+
+	return instructionSet, nil
+}
+
+func (workers workerList) findReadyWorker() int {
+	for worker, job := range workers {
+		if job.stepsRemaining == 0 {
+			return worker
+		}
+	}
+
+	return -1
+}
+
+func (workers workerList) allIdle() bool {
+	for _, job := range workers {
+		if job.stepsRemaining > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (workers workerList) getDoneWorkers() []int {
+	jobs := make([]int, 0)
+	for i, job := range workers {
+		if job.stepsRemaining == 0 && job.name != "" {
+			jobs = append(jobs, i)
+		}
+	}
+
+	return jobs
+}
+
+func (workers workerList) makingDependency(desiredJob string, instructions instructionList) bool {
+	for _, dependency := range instructions[desiredJob] {
+		for _, job := range workers {
+			if job.name == dependency {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (workers workerList) work() {
+	stepSize := workers.getMinStepsRemaining()
+	for i := range workers {
+		if workers[i].stepsRemaining > 0 {
+			workers[i].stepsRemaining -= stepSize
+		}
+	}
+}
+
+func (workers workerList) getMinStepsRemaining() int {
+	minTime := math.MaxInt32
+	allZero := true
+	for _, worker := range workers {
+		if worker.stepsRemaining != 0 {
+			allZero = false
+		}
+		if worker.stepsRemaining < minTime && worker.stepsRemaining > 0 {
+			minTime = worker.stepsRemaining
+		}
+	}
+
+	if allZero {
+		return 0
+	} else {
+		return minTime
+	}
+}
+
+// Relieve any workers who have finished their work
+func (workers workerList) relieve() {
+	doneJobs := workers.getDoneWorkers()
+	for _, doneJob := range doneJobs {
+		workers[doneJob].name = ""
+	}
+}
+
+func part1(instructions instructionList, steps *aoc.Steps) (string, error) {
+	return instructions.resolveDependencies(steps)
+}
+
+// part2 finds how long it takes the given number of workers to complete every step, if each step takes baseStepTime
+// seconds plus its position in the alphabet
+func part2(instructions instructionList, numWorkers int, baseStepTime int, steps *aoc.Steps) (int, error) {
+	allInstructions := instructions.clone()
+	time := 0
+	workers := make(workerList, numWorkers)
+	workQueue := instructions.findReadySteps()
+	// While workers are working or there is new work to be done
+	for len(workQueue) > 0 || !workers.allIdle() {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		workers.work()
+		workers.relieve()
+		if len(workQueue) == 0 {
+			workQueue = instructions.findReadySteps()
+		}
+		availableWorker := workers.findReadyWorker()
+		// Store all items that cannot be worked on yet
+		invalidItems := make([]string, 0)
+		for len(workQueue) > 0 && availableWorker != -1 {
+			var job string
+			job, workQueue = workQueue[0], workQueue[1:]
+			if workers.makingDependency(job, allInstructions) {
+				invalidItems = append(invalidItems, job)
+				continue
+			}
+			workers[availableWorker].name = job
+			workers[availableWorker].stepsRemaining = baseStepTime + int(rune(job[0])-'A') + 1
+			instructions.markAsDone(job)
+			availableWorker = workers.findReadyWorker()
+		}
+		// Add the items that cannot be worked on yet to the queue
+		workQueue = append(invalidItems, workQueue...)
+		time += workers.getMinStepsRemaining()
+	}
+
+	return time, nil
+}
+
+func init() {
+	aoc.Register(7, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Input), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Input), budget)
+		},
+	})
+	generate.Register(7, generate.Generator{Size: "steps", DefaultSize: maxSteps, Generate: Generate})
+}
+
+// Input is the list of steps, along with the steps each one depends on
+type Input struct {
+	instructions instructionList
+}
+
+// Parse parses the list of step requirements
+func Parse(reader io.Reader) (Input, error) {
+	rawInstructions, err := aoc.ReadLines(reader)
+	if err != nil {
+		return Input{}, err
+	}
+
+	instructions, err := parseInstructions(rawInstructions)
+	if err != nil {
+		return Input{}, err
+	}
+
+	return Input{instructions: instructions}, nil
+}
+
+// Part1 finds the order the steps are completed in
+func Part1(ctx context.Context, input Input, budget aoc.Budget) (string, error) {
+	return part1(input.instructions.clone(), budget.Start(ctx))
+}
+
+// Part2 finds how long it takes for five workers to complete every step
+func Part2(ctx context.Context, input Input, budget aoc.Budget) (int, error) {
+	return part2(input.instructions.clone(), numWorkers, baseStepTime, budget.Start(ctx))
+}
+
+// Generate makes the requirements for size steps, with at least two steps, and no more than there are letters for. The
+// steps are put in a random order, and each one only depends on steps before it, so that they can always be completed.
+func Generate(rng *rand.Rand, size int) string {
+	numSteps := min(max(size, 2), maxSteps)
+	names := rng.Perm(maxSteps)[:numSteps]
+	requirements := map[[2]int]bool{}
+	for i := 0; i < numSteps; i++ {
+		// Every step but the last must come before a later one, or it would be left out of the requirements
+		if i < numSteps-1 {
+			requirements[[2]int{i, generate.Between(rng, i+1, numSteps-1)}] = true
+		}
+
+		for j := rng.Intn(maxExtraDependencies + 1); j > 0 && i > 0; j-- {
+			requirements[[2]int{rng.Intn(i), i}] = true
+		}
+	}
+
+	lines := make([]string, 0, len(requirements))
+	for requirement := range requirements {
+		dependencyName := string(rune('A' + names[requirement[0]]))
+		instructionName := string(rune('A' + names[requirement[1]]))
+		lines = append(lines, fmt.Sprintf(instructionStringFormat, dependencyName, instructionName))
+	}
+
+	// Sort the lines before shuffling them, as the map's order is not decided by the seed
+	sort.Strings(lines)
+	rng.Shuffle(len(lines), func(i, j int) {
+		lines[i], lines[j] = lines[j], lines[i]
+	})
+
+	return generate.Lines(lines)
+}
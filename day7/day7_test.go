@@ -0,0 +1,92 @@
+package day7
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = `Step C must be finished before step A can begin.
+Step C must be finished before step F can begin.
+Step A must be finished before step B can begin.
+Step A must be finished before step D can begin.
+Step B must be finished before step E can begin.
+Step D must be finished before step E can begin.
+Step F must be finished before step E can begin.
+`
+
+func TestPart1(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Part1(context.Background(), input, aoc.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	} else if got != "CABDFE" {
+		t.Errorf("got %q, want %q", got, "CABDFE")
+	}
+}
+
+func TestPart2(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The example has two workers, and steps take no time beyond their letter
+	got, err := part2(input.instructions.clone(), 2, 0, aoc.Budget{}.Start(context.Background()))
+	if err != nil {
+		t.Fatal(err)
+	} else if got != 15 {
+		t.Errorf("got %d, want 15", got)
+	}
+}
+
+func TestPartsDoNotModifyInput(t *testing.T) {
+	input, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := Part1(context.Background(), input, aoc.Budget{})
+	second, _ := Part1(context.Background(), input, aoc.Budget{})
+	if first != second {
+		t.Errorf("solving twice gave %q then %q", first, second)
+	}
+}
+
+func TestParseDependencyCycle(t *testing.T) {
+	// B, C and D depend on each other, and E can only be done after them
+	_, err := Parse(strings.NewReader(`Step A must be finished before step B can begin.
+Step B must be finished before step C can begin.
+Step D must be finished before step E can begin.
+Step C must be finished before step D can begin.
+Step D must be finished before step B can begin.
+`))
+	parseErr, ok := err.(*aoc.ParseError)
+	if !ok {
+		t.Fatalf("got error %v, want a parse error", err)
+	} else if parseErr.Line != 2 {
+		t.Errorf("got line %d, want 2", parseErr.Line)
+	}
+
+	if !errors.Is(err, errDependencyCycle) {
+		t.Errorf("got error %v, want %v", err, errDependencyCycle)
+	} else if !strings.Contains(err.Error(), "B, C, D, E can never be done") {
+		t.Errorf("got error %v, which does not name the steps that can never be done", err)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 7)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 7)
+}
@@ -0,0 +1,207 @@
+// Package day8 solves the puzzle for day 8 of Advent of Code 2018
+package day8
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const (
+	// maxMetadata is the most metadata entries a generated node has
+	maxMetadata = 5
+	// maxMetadataValue is the largest value of a generated metadata entry
+	maxMetadataValue = 9
+)
+
+var errIncompleteTree = errors.New("tree ends before all of its nodes do")
+
+type node struct {
+	value       int
+	childValues []int
+}
+
+func sumUntil(arr []int, max int) int {
+	total := 0
+	for i := 0; i < max; i++ {
+		total += arr[i]
+	}
+
+	return total
+}
+
+func getChildValuesFromMetadata(tree []int, numValues int, children []node) int {
+	total := 0
+	for i := 0; i < numValues; i++ {
+		childIndex := tree[i] - 1
+		if childIndex >= 0 && childIndex < len(children) {
+			total += children[childIndex].value
+		}
+	}
+
+	return total
+}
+
+// parseTree reads numNodes nodes from the start of the tree, taking a step for each node, including their children
+func parseTree(tree []int, numNodes int, steps *aoc.Steps) (int, int, []node, error) {
+	nodes := make([]node, numNodes)
+	cursor := 0
+	total := 0
+	for i := 0; i < numNodes; i++ {
+		if err := steps.Take(); err != nil {
+			return 0, 0, nil, err
+		}
+
+		numChildren, metadataCount := tree[0], tree[1]
+		// Remove the tree header
+		tree = tree[2:]
+		n, subtotal, children, err := parseTree(tree, numChildren, steps)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+
+		// Remove the part that the subtree parsed
+		tree = tree[n:]
+		// Get the data total of the metadata
+		metadataValue := sumUntil(tree, metadataCount)
+		total += subtotal + metadataValue
+		if numChildren == 0 {
+			// Extract the value of the metadata
+			nodes[i].value = metadataValue
+		} else {
+			nodes[i].value = getChildValuesFromMetadata(tree, metadataCount, children)
+			// Copy the values of the children into our node
+			nodes[i].childValues = make([]int, numChildren)
+			for j := 0; j < numChildren; j++ {
+				nodes[i].childValues[j] = children[j].value
+			}
+		}
+		// Remove the metadata from the tree and advance the cursor accordingly
+		tree = tree[metadataCount:]
+		cursor += metadataCount + 2 + n
+	}
+
+	return cursor, total, nodes, nil
+}
+
+// isCompleteTree checks that the tree has exactly enough numbers for its root node and all of its children
+func isCompleteTree(tree []int) bool {
+	n, ok := measureTree(tree)
+
+	return ok && n == len(tree)
+}
+
+// measureTree finds how many numbers the node at the start of the tree is made of, returning false if the tree ends before it does
+func measureTree(tree []int) (int, bool) {
+	if len(tree) < 2 || tree[0] < 0 || tree[1] < 0 {
+		return 0, false
+	}
+
+	numChildren, metadataCount := tree[0], tree[1]
+	cursor := 2
+	for i := 0; i < numChildren; i++ {
+		n, ok := measureTree(tree[cursor:])
+		if !ok {
+			return 0, false
+		}
+		cursor += n
+	}
+
+	if cursor+metadataCount > len(tree) {
+		return 0, false
+	}
+
+	return cursor + metadataCount, true
+}
+
+func parseInput(input io.Reader) ([]int, error) {
+	rawTree, err := aoc.ReadString(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make([]int, 0)
+	column := 0
+	for _, item := range strings.Split(rawTree, " ") {
+		result, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, aoc.NewParseError(8, 0, column, rawTree, err)
+		}
+		tree = append(tree, result)
+		column += len(item) + 1
+	}
+
+	if !isCompleteTree(tree) {
+		return nil, aoc.NewParseError(8, 0, len(rawTree), rawTree, errIncompleteTree)
+	}
+
+	return tree, nil
+}
+
+func init() {
+	aoc.Register(8, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.([]int), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.([]int), budget)
+		},
+	})
+	generate.Register(8, generate.Generator{Size: "nodes", DefaultSize: 2000, Generate: Generate})
+}
+
+// Parse parses the numbers that make up the license's tree
+func Parse(reader io.Reader) ([]int, error) {
+	return parseInput(reader)
+}
+
+// Part1 finds the sum of every node's metadata
+func Part1(ctx context.Context, tree []int, budget aoc.Budget) (int, error) {
+	_, total, _, err := parseTree(tree, 1, budget.Start(ctx))
+
+	return total, err
+}
+
+// Part2 finds the value of the root node
+func Part2(ctx context.Context, tree []int, budget aoc.Budget) (int, error) {
+	_, _, rootedTree, err := parseTree(tree, 1, budget.Start(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	return rootedTree[0].value, nil
+}
+
+// Generate makes a tree of size nodes. Each node after the root is the child of a random node before it, which keeps
+// the tree shallow, as most nodes are near the root.
+func Generate(rng *rand.Rand, size int) string {
+	children := make([][]int, size)
+	for i := 1; i < size; i++ {
+		parent := rng.Intn(i)
+		children[parent] = append(children[parent], i)
+	}
+
+	items := []string{}
+	var writeNode func(n int)
+	writeNode = func(n int) {
+		numMetadata := generate.Between(rng, 1, maxMetadata)
+		items = append(items, strconv.Itoa(len(children[n])), strconv.Itoa(numMetadata))
+		for _, child := range children[n] {
+			writeNode(child)
+		}
+
+		for i := 0; i < numMetadata; i++ {
+			items = append(items, strconv.Itoa(generate.Between(rng, 1, maxMetadataValue)))
+		}
+	}
+	writeNode(0)
+
+	return strings.Join(items, " ") + "\n"
+}
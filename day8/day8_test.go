@@ -0,0 +1,53 @@
+package day8
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+const example = "2 3 0 3 10 11 12 1 1 0 1 99 2 1 1 2\n"
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name string
+		part func(context.Context, []int, aoc.Budget) (int, error)
+		want int
+	}{
+		{"part1", Part1, 138},
+		{"part2", Part2, 66},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := Parse(strings.NewReader(example))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tt.part(context.Background(), tree, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIncompleteTree(t *testing.T) {
+	if _, err := Parse(strings.NewReader("2 3 0 3 10 11 12\n")); err == nil {
+		t.Error("expected an error for a tree that is cut off")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 8)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 8)
+}
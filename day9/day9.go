@@ -0,0 +1,155 @@
+// Package day9 solves the puzzle for day 9 of Advent of Code 2018
+package day9
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+)
+
+const lineFormat = "%d players; last marble is worth %d points"
+
+const (
+	// minPlayers and maxPlayers bound the number of players in a generated game
+	minPlayers = 9
+	maxPlayers = 500
+)
+
+type node struct {
+	value int
+	next  *node
+	prev  *node
+}
+
+func newCircularLinkedList() *node {
+	n := new(node)
+	n.next = n
+	n.prev = n
+
+	return n
+}
+
+func parseInput(input string) (numPlayers, numMarbles int, err error) {
+	numMatched, err := fmt.Sscanf(input, lineFormat, &numPlayers, &numMarbles)
+	if err != nil {
+		return 0, 0, aoc.NewParseError(9, 0, -1, input, err)
+	} else if numMatched != 2 || numPlayers < 1 {
+		return 0, 0, aoc.NewParseError(9, 0, -1, input, aoc.ErrMalformedInput)
+	}
+
+	return
+}
+
+func getMax(arr []int) (max int) {
+	for _, item := range arr {
+		if item > max {
+			max = item
+		}
+	}
+
+	return
+}
+
+func getElementByOffset(n *node, offset int) *node {
+	cursor := n
+	negative := false
+	numJumps := offset
+	if offset < 0 {
+		numJumps *= -1
+		negative = true
+	}
+
+	for i := 0; i < numJumps; i++ {
+		if negative {
+			cursor = cursor.prev
+		} else {
+			cursor = cursor.next
+		}
+	}
+
+	return cursor
+}
+
+// runGame plays the game, taking a step for each marble placed
+func runGame(numPlayers int, numMarbles int, steps *aoc.Steps) (int, error) {
+	scores := make([]int, numPlayers)
+	currentMarble := newCircularLinkedList()
+	currentPlayer := 0
+	for nextMarble := 1; nextMarble <= numMarbles; nextMarble++ {
+		if err := steps.Take(); err != nil {
+			return 0, err
+		}
+
+		if nextMarble%23 == 0 {
+			removeMarble := getElementByOffset(currentMarble, -7)
+			removeMarble.prev.next = removeMarble.next
+			scores[currentPlayer] += nextMarble + removeMarble.value
+			currentMarble = removeMarble.next
+		} else {
+			newMarble := &node{
+				value: nextMarble,
+				next:  currentMarble.next.next,
+				prev:  currentMarble.next,
+			}
+			newMarble.next.prev = newMarble
+			currentMarble.next.next = newMarble
+			currentMarble = newMarble
+		}
+		currentPlayer = (currentPlayer + 1) % numPlayers
+	}
+
+	return getMax(scores), nil
+}
+
+func init() {
+	aoc.Register(9, aoc.Puzzle{
+		Parse: func(reader io.Reader) (interface{}, error) { return Parse(reader) },
+		Part1: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part1(ctx, input.(Game), budget)
+		},
+		Part2: func(ctx context.Context, input interface{}, budget aoc.Budget) (interface{}, error) {
+			return Part2(ctx, input.(Game), budget)
+		},
+	})
+	generate.Register(9, generate.Generator{Size: "points the last marble is worth", DefaultSize: 70000, Generate: Generate})
+}
+
+// Game describes a game of marbles
+type Game struct {
+	NumPlayers int
+	NumMarbles int
+}
+
+// Parse parses the description of the game
+func Parse(reader io.Reader) (Game, error) {
+	rawInput, err := aoc.ReadString(reader)
+	if err != nil {
+		return Game{}, err
+	}
+
+	numPlayers, numMarbles, err := parseInput(rawInput)
+	if err != nil {
+		return Game{}, err
+	}
+
+	return Game{NumPlayers: numPlayers, NumMarbles: numMarbles}, nil
+}
+
+// Part1 finds the winning elf's score
+func Part1(ctx context.Context, game Game, budget aoc.Budget) (int, error) {
+	return runGame(game.NumPlayers, game.NumMarbles, budget.Start(ctx))
+}
+
+// Part2 finds the winning elf's score if the last marble were 100 times larger
+func Part2(ctx context.Context, game Game, budget aoc.Budget) (int, error) {
+	return runGame(game.NumPlayers, game.NumMarbles*100, budget.Start(ctx))
+}
+
+// Generate makes a game whose last marble is worth size points
+func Generate(rng *rand.Rand, size int) string {
+	return fmt.Sprintf(lineFormat, generate.Between(rng, minPlayers, maxPlayers), size) + "\n"
+}
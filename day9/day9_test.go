@@ -0,0 +1,57 @@
+package day9
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/aoc/aoctest"
+)
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		game Game
+		want int
+	}{
+		{Game{NumPlayers: 9, NumMarbles: 25}, 32},
+		{Game{NumPlayers: 10, NumMarbles: 1618}, 8317},
+		{Game{NumPlayers: 13, NumMarbles: 7999}, 146373},
+		{Game{NumPlayers: 17, NumMarbles: 1104}, 2764},
+		{Game{NumPlayers: 21, NumMarbles: 6111}, 54718},
+		{Game{NumPlayers: 30, NumMarbles: 5807}, 37305},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d players, %d marbles", tt.game.NumPlayers, tt.game.NumMarbles)
+		t.Run(name, func(t *testing.T) {
+			got, err := Part1(context.Background(), tt.game, aoc.Budget{})
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	game, err := Parse(strings.NewReader("10 players; last marble is worth 1618 points\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Game{NumPlayers: 10, NumMarbles: 1618}
+	if game != want {
+		t.Errorf("got %+v, want %+v", game, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, 9)
+}
+
+func BenchmarkInput(b *testing.B) {
+	aoctest.Benchmark(b, 9)
+}
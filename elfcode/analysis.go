@@ -0,0 +1,437 @@
+package elfcode
+
+import "sort"
+
+// FlowKind describes how control leaves an instruction
+type FlowKind int
+
+const (
+	// FlowNext indicates the instruction does not write to the instruction pointer and falls through to the next one
+	FlowNext FlowKind = iota
+	// FlowJump indicates the instruction always jumps to a single known target
+	FlowJump
+	// FlowBranch indicates the instruction adds a flag register, set by a comparison, to the instruction pointer, skipping
+	// the next instruction if it is set
+	FlowBranch
+	// FlowIndirect indicates the instruction writes a value to the instruction pointer that can not be determined ahead of time
+	FlowIndirect
+)
+
+// noCondition indicates that a flag was not produced by a known comparison
+const noCondition = -1
+
+// Flow describes where control can go after an instruction is run
+type Flow struct {
+	Kind FlowKind
+	// Targets holds the indices of every instruction that may be run next. For branches, the not-taken target comes first.
+	Targets []int
+	// Flag is the register a FlowBranch is conditioned on
+	Flag int
+	// Condition is the index of the comparison instruction that set Flag, which is why it can only hold 0 or 1
+	Condition int
+}
+
+// BasicBlock is a run of instructions that are always executed in order, with only the last one able to jump
+type BasicBlock struct {
+	// Start and End are the indices of the first instruction, and the one after the last
+	Start int
+	End   int
+	// Successors holds the start of every block that may run after this one
+	Successors   []int
+	Predecessors []int
+	// Halts indicates that the block may jump outside of the program
+	Halts     bool
+	Reachable bool
+}
+
+// Loop is a natural loop found in the control flow graph
+type Loop struct {
+	// Header is the start of the block that every iteration of the loop passes through
+	Header int
+	// Blocks holds the start of every block in the loop, sorted
+	Blocks []int
+	// Latches holds the start of every block that jumps back to the header
+	Latches []int
+}
+
+// ControlFlowGraph holds the result of analysing a program's jumps through the instruction pointer register
+type ControlFlowGraph struct {
+	Program Program
+	Flows   []Flow
+	Blocks  []BasicBlock
+	Loops   []Loop
+	// HasIndirectJumps indicates that at least one jump target could not be determined, so the graph may be missing edges
+	HasIndirectJumps bool
+	// blockIndex maps the start of a block to its index in Blocks
+	blockIndex map[int]int
+	// known holds the registers whose values are known before each instruction is run
+	known []map[int]int
+}
+
+// maxAnalysisPasses bounds how many times constant folding may be used to refine the graph
+const maxAnalysisPasses = 8
+
+// NewControlFlowGraph analyses the program's control flow. Jump targets are resolved by folding constants within
+// each basic block, which is repeated until the graph stops changing.
+func NewControlFlowGraph(program Program) *ControlFlowGraph {
+	cfg := &ControlFlowGraph{Program: program}
+	numInstructions := len(program.Instructions)
+	// Start with nothing known, which gives the most conservative graph
+	cfg.known = make([]map[int]int, numInstructions)
+	for i := range cfg.known {
+		cfg.known[i] = map[int]int{}
+	}
+
+	for pass := 0; pass < maxAnalysisPasses; pass++ {
+		cfg.Flows = make([]Flow, numInstructions)
+		cfg.HasIndirectJumps = false
+		for i := range program.Instructions {
+			cfg.Flows[i] = cfg.flowOf(i)
+			if cfg.Flows[i].Kind == FlowIndirect {
+				cfg.HasIndirectJumps = true
+			}
+		}
+
+		cfg.buildBlocks()
+		// An indirect jump could land anywhere, so nothing can be known across instructions
+		if cfg.HasIndirectJumps || !cfg.propagateConstants() {
+			break
+		}
+	}
+
+	cfg.findReachable()
+	cfg.findLoops()
+
+	return cfg
+}
+
+// Block gets the block starting at the given instruction index
+func (cfg *ControlFlowGraph) Block(start int) (BasicBlock, bool) {
+	index, ok := cfg.blockIndex[start]
+	if !ok {
+		return BasicBlock{}, false
+	}
+
+	return cfg.Blocks[index], true
+}
+
+// BlockContaining gets the block that the instruction at the given index belongs to
+func (cfg *ControlFlowGraph) BlockContaining(instructionIndex int) (BasicBlock, bool) {
+	index := sort.Search(len(cfg.Blocks), func(i int) bool {
+		return cfg.Blocks[i].End > instructionIndex
+	})
+	if index == len(cfg.Blocks) || cfg.Blocks[index].Start > instructionIndex {
+		return BasicBlock{}, false
+	}
+
+	return cfg.Blocks[index], true
+}
+
+// KnownBefore gets the value of a register before the instruction at the given index is run, if it can be determined.
+// Reads of the instruction pointer register are always known.
+func (cfg *ControlFlowGraph) KnownBefore(instructionIndex int, register int) (int, bool) {
+	if register == cfg.Program.IPRegister {
+		return instructionIndex, true
+	}
+
+	value, ok := cfg.known[instructionIndex][register]
+
+	return value, ok
+}
+
+// operandValues gets the values of the instruction's operands, along with whether they are known.
+// Operands that are not read by the instruction are always known.
+func (cfg *ControlFlowGraph) operandValues(instructionIndex int) (a int, aKnown bool, b int, bKnown bool) {
+	ins := cfg.Program.Instructions[instructionIndex]
+	a, aKnown = ins.A, true
+	if ins.Op.UsesRegisterA() {
+		a, aKnown = cfg.KnownBefore(instructionIndex, ins.A)
+	}
+
+	b, bKnown = ins.B, true
+	if ins.Op.UsesRegisterB() {
+		b, bKnown = cfg.KnownBefore(instructionIndex, ins.B)
+	}
+
+	return
+}
+
+// evaluate gets the result of the instruction, if it can be determined without running the program
+func (cfg *ControlFlowGraph) evaluate(instructionIndex int) (int, bool) {
+	ins := cfg.Program.Instructions[instructionIndex]
+	a, aKnown, b, bKnown := cfg.operandValues(instructionIndex)
+	if !aKnown || (ins.Op.UsesB() && !bKnown) {
+		return 0, false
+	}
+
+	return ins.Op.Compute(a, b), true
+}
+
+func (cfg *ControlFlowGraph) flowOf(instructionIndex int) Flow {
+	ins := cfg.Program.Instructions[instructionIndex]
+	if !cfg.Program.HasIPRegister() || ins.C != cfg.Program.IPRegister {
+		return Flow{Kind: FlowNext, Targets: []int{instructionIndex + 1}}
+	}
+
+	// The instruction pointer is incremented after every instruction, including ones that write to it
+	if result, ok := cfg.evaluate(instructionIndex); ok {
+		return Flow{Kind: FlowJump, Targets: []int{result + 1}}
+	}
+
+	ipRegister := cfg.Program.IPRegister
+	flag := noCondition
+	if ins.Op == Addr && ins.A == ipRegister {
+		flag = ins.B
+	} else if ins.Op == Addr && ins.B == ipRegister {
+		flag = ins.A
+	}
+
+	if flag == noCondition {
+		return Flow{Kind: FlowIndirect}
+	}
+
+	// Without a comparison, the flag could hold anything, and so could jump anywhere
+	condition := cfg.findCondition(instructionIndex, flag)
+	if condition == noCondition {
+		return Flow{Kind: FlowIndirect}
+	}
+
+	return Flow{
+		Kind:      FlowBranch,
+		Targets:   []int{instructionIndex + 1, instructionIndex + 2},
+		Flag:      flag,
+		Condition: condition,
+	}
+}
+
+// findCondition finds the comparison that most recently set the given flag before the instruction at the given index.
+// The comparison can only be trusted if it is in the same block as the branch, which is checked once blocks are built.
+func (cfg *ControlFlowGraph) findCondition(instructionIndex int, flag int) int {
+	for i := instructionIndex - 1; i >= 0; i-- {
+		ins := cfg.Program.Instructions[i]
+		if ins.C != flag {
+			continue
+		} else if ins.Op.IsComparison() {
+			return i
+		}
+
+		return noCondition
+	}
+
+	return noCondition
+}
+
+func (cfg *ControlFlowGraph) buildBlocks() {
+	numInstructions := len(cfg.Program.Instructions)
+	leaders := map[int]bool{0: true}
+	for i, flow := range cfg.Flows {
+		if flow.Kind == FlowNext {
+			continue
+		}
+
+		if i+1 < numInstructions {
+			leaders[i+1] = true
+		}
+		for _, target := range flow.Targets {
+			if target >= 0 && target < numInstructions {
+				leaders[target] = true
+			}
+		}
+	}
+
+	starts := sortedKeys(leaders)
+	cfg.Blocks = make([]BasicBlock, 0, len(starts))
+	cfg.blockIndex = make(map[int]int, len(starts))
+	for i, start := range starts {
+		end := numInstructions
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		if start >= end {
+			continue
+		}
+
+		cfg.blockIndex[start] = len(cfg.Blocks)
+		cfg.Blocks = append(cfg.Blocks, BasicBlock{Start: start, End: end})
+	}
+
+	for i := range cfg.Blocks {
+		block := &cfg.Blocks[i]
+		lastFlow := cfg.Flows[block.End-1]
+		// A branch's condition is only meaningful if it's in the same block, otherwise the flag could have been set
+		// to anything on the way in
+		if lastFlow.Kind == FlowBranch && lastFlow.Condition < block.Start {
+			lastFlow = Flow{Kind: FlowIndirect}
+			cfg.Flows[block.End-1] = lastFlow
+			cfg.HasIndirectJumps = true
+		}
+
+		for _, target := range lastFlow.Targets {
+			if target < 0 || target >= numInstructions {
+				block.Halts = true
+			} else {
+				block.Successors = appendUnique(block.Successors, target)
+			}
+		}
+	}
+
+	for _, block := range cfg.Blocks {
+		for _, successor := range block.Successors {
+			successorBlock := &cfg.Blocks[cfg.blockIndex[successor]]
+			successorBlock.Predecessors = appendUnique(successorBlock.Predecessors, block.Start)
+		}
+	}
+}
+
+// propagateConstants works out which registers are known before each instruction, restarting at the top of every block.
+// It returns whether anything changed since the last time it was run.
+func (cfg *ControlFlowGraph) propagateConstants() bool {
+	changed := false
+	for _, block := range cfg.Blocks {
+		known := map[int]int{}
+		for i := block.Start; i < block.End; i++ {
+			if !equalKnown(cfg.known[i], known) {
+				changed = true
+				cfg.known[i] = copyKnown(known)
+			}
+
+			ins := cfg.Program.Instructions[i]
+			if result, ok := cfg.evaluate(i); ok {
+				known[ins.C] = result
+			} else {
+				delete(known, ins.C)
+			}
+		}
+	}
+
+	return changed
+}
+
+func (cfg *ControlFlowGraph) findReachable() {
+	if len(cfg.Blocks) == 0 {
+		return
+	}
+
+	toVisit := []int{0}
+	for len(toVisit) > 0 {
+		start := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		block := &cfg.Blocks[cfg.blockIndex[start]]
+		if block.Reachable {
+			continue
+		}
+
+		block.Reachable = true
+		toVisit = append(toVisit, block.Successors...)
+	}
+
+	// With an indirect jump, any block could be reached
+	if cfg.HasIndirectJumps {
+		for i := range cfg.Blocks {
+			cfg.Blocks[i].Reachable = true
+		}
+	}
+}
+
+// findLoops finds every natural loop by looking for edges back to a block that is still being visited in a depth first search
+func (cfg *ControlFlowGraph) findLoops() {
+	if len(cfg.Blocks) == 0 {
+		return
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int]int, len(cfg.Blocks))
+	latches := map[int][]int{}
+	var visit func(start int)
+	visit = func(start int) {
+		state[start] = visiting
+		block := cfg.Blocks[cfg.blockIndex[start]]
+		for _, successor := range block.Successors {
+			if state[successor] == visiting {
+				latches[successor] = appendUnique(latches[successor], start)
+			} else if state[successor] == unvisited {
+				visit(successor)
+			}
+		}
+		state[start] = visited
+	}
+	visit(0)
+	// With an indirect jump, any block could be where a loop is entered from
+	if cfg.HasIndirectJumps {
+		for _, block := range cfg.Blocks {
+			if state[block.Start] == unvisited {
+				visit(block.Start)
+			}
+		}
+	}
+
+	cfg.Loops = make([]Loop, 0, len(latches))
+	for _, header := range sortedKeys(intKeySet(latches)) {
+		body := map[int]bool{header: true}
+		toVisit := append([]int{}, latches[header]...)
+		for len(toVisit) > 0 {
+			start := toVisit[len(toVisit)-1]
+			toVisit = toVisit[:len(toVisit)-1]
+			if body[start] || !cfg.Blocks[cfg.blockIndex[start]].Reachable {
+				continue
+			}
+
+			body[start] = true
+			toVisit = append(toVisit, cfg.Blocks[cfg.blockIndex[start]].Predecessors...)
+		}
+
+		loopLatches := append([]int{}, latches[header]...)
+		sort.Ints(loopLatches)
+		cfg.Loops = append(cfg.Loops, Loop{
+			Header:  header,
+			Blocks:  sortedKeys(body),
+			Latches: loopLatches,
+		})
+	}
+}
+
+func appendUnique(items []int, item int) []int {
+	for _, existing := range items {
+		if existing == item {
+			return items
+		}
+	}
+
+	return append(items, item)
+}
+
+func intKeySet(items map[int][]int) map[int]bool {
+	set := make(map[int]bool, len(items))
+	for key := range items {
+		set[key] = true
+	}
+
+	return set
+}
+
+func copyKnown(known map[int]int) map[int]int {
+	clone := make(map[int]int, len(known))
+	for register, value := range known {
+		clone[register] = value
+	}
+
+	return clone
+}
+
+func equalKnown(known1 map[int]int, known2 map[int]int) bool {
+	if len(known1) != len(known2) {
+		return false
+	}
+
+	for register, value := range known1 {
+		if otherValue, ok := known2[register]; !ok || otherValue != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package elfcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+// countingLoop counts register 1 up to 5 before halting
+const countingLoop = `#ip 5
+seti 0 0 1
+addi 1 1 1
+eqri 1 5 2
+addr 2 5 5
+seti 0 0 5
+seti 9 0 0
+`
+
+func TestControlFlowGraph(t *testing.T) {
+	cfg := NewControlFlowGraph(mustParseProgram(t, countingLoop))
+	if cfg.HasIndirectJumps {
+		t.Error("expected every jump to be resolved")
+	}
+
+	wantFlows := []Flow{
+		{Kind: FlowNext, Targets: []int{1}},
+		{Kind: FlowNext, Targets: []int{2}},
+		{Kind: FlowNext, Targets: []int{3}},
+		{Kind: FlowBranch, Targets: []int{4, 5}, Flag: 2, Condition: 2},
+		{Kind: FlowJump, Targets: []int{1}},
+		{Kind: FlowNext, Targets: []int{6}},
+	}
+	if !reflect.DeepEqual(cfg.Flows, wantFlows) {
+		t.Errorf("got flows %+v, want %+v", cfg.Flows, wantFlows)
+	}
+
+	wantBlocks := []BasicBlock{
+		{Start: 0, End: 1, Successors: []int{1}, Reachable: true},
+		{Start: 1, End: 4, Successors: []int{4, 5}, Predecessors: []int{0, 4}, Reachable: true},
+		{Start: 4, End: 5, Successors: []int{1}, Predecessors: []int{1}, Reachable: true},
+		{Start: 5, End: 6, Predecessors: []int{1}, Halts: true, Reachable: true},
+	}
+	if !reflect.DeepEqual(cfg.Blocks, wantBlocks) {
+		t.Errorf("got blocks %+v, want %+v", cfg.Blocks, wantBlocks)
+	}
+
+	wantLoops := []Loop{{Header: 1, Blocks: []int{1, 4}, Latches: []int{4}}}
+	if !reflect.DeepEqual(cfg.Loops, wantLoops) {
+		t.Errorf("got loops %+v, want %+v", cfg.Loops, wantLoops)
+	}
+}
+
+func TestControlFlowGraphIndirectJumps(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		index  int
+	}{
+		{"computed jump", "#ip 5\nmulr 1 1 5\nseti 9 0 0\n", 0},
+		// Without a comparison, the flag could hold anything
+		{"flag without a comparison", "#ip 5\naddi 1 1 2\naddr 2 5 5\nseti 9 0 0\n", 1},
+		// The comparison is run before the branch is jumped back to from the last instruction, so the flag may have changed
+		{"comparison in another block", "#ip 5\neqri 1 0 2\naddr 2 5 5\naddi 2 1 2\nseti 0 0 5\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewControlFlowGraph(mustParseProgram(t, tt.source))
+			if !cfg.HasIndirectJumps {
+				t.Error("expected the graph to have indirect jumps")
+			}
+			if kind := cfg.Flows[tt.index].Kind; kind != FlowIndirect {
+				t.Errorf("got flow kind %d at %d, want %d", kind, tt.index, FlowIndirect)
+			}
+			for _, block := range cfg.Blocks {
+				if !block.Reachable {
+					t.Errorf("block %d is unreachable, but an indirect jump could reach it", block.Start)
+				}
+			}
+		})
+	}
+}
+
+func TestControlFlowGraphLoopBehindIndirectJump(t *testing.T) {
+	// The loop at index 2 can only be reached through the jump on register 0
+	cfg := NewControlFlowGraph(mustParseProgram(t, "#ip 5\naddr 5 0 5\nseti 9 0 5\naddi 1 1 1\nseti 1 0 5\n"))
+	wantLoops := []Loop{{Header: 2, Blocks: []int{2}, Latches: []int{2}}}
+	if !reflect.DeepEqual(cfg.Loops, wantLoops) {
+		t.Errorf("got loops %+v, want %+v", cfg.Loops, wantLoops)
+	}
+}
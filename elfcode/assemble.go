@@ -0,0 +1,324 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+)
+
+const (
+	commentDelim   = ";"
+	labelSuffix    = ":"
+	constDirective = ".const"
+	ipDirective    = "#ip"
+	ipName         = "ip"
+	jmpMacro       = "jmp"
+	jzMacro        = "jz"
+	jnzMacro       = "jnz"
+	numOperands    = 3
+)
+
+// ErrUndefinedName is returned when an operand refers to a label or constant that was never defined
+var ErrUndefinedName = errors.New("undefined name")
+
+// macroSizes holds the number of instructions each macro expands to, which is needed to find labels before expanding them
+var macroSizes = map[string]int{
+	jmpMacro: 1,
+	jzMacro:  2,
+	jnzMacro: 3,
+}
+
+// AssemblyError describes a problem with a single line of assembly, or of a program given to ParseProgram
+type AssemblyError struct {
+	// Line is the 1-indexed line number
+	Line int
+	Text string
+	Err  error
+}
+
+// sourceLine is a line of assembly with its comment and label removed
+type sourceLine struct {
+	number int
+	text   string
+	fields []string
+}
+
+// assembler holds the state needed to turn assembly into a program
+type assembler struct {
+	program Program
+	// names holds every label and constant
+	names map[string]int
+}
+
+func (err AssemblyError) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", err.Line, err.Err, strings.TrimSpace(err.Text))
+}
+
+func (err AssemblyError) Unwrap() error {
+	return err.Err
+}
+
+// ParseError converts the error into the aoc package's, so the line can be reported like any other bad puzzle input
+func (err AssemblyError) ParseError(day int) *aoc.ParseError {
+	return aoc.NewParseError(day, err.Line-1, -1, err.Text, err.Err)
+}
+
+// Fatal prints an error with the program in the given file, pointing at the offending line for an AssemblyError, and
+// exits with a non-zero status
+func Fatal(inFile string, err error) {
+	var asmErr AssemblyError
+	if errors.As(err, &asmErr) {
+		err = asmErr.ParseError(0)
+	}
+
+	aoc.Fatal(aoc.InputName(inFile), err)
+}
+
+// Assemble turns elfcode assembly into a program. On top of the plain "#ip N" and "op a b c" lines that ParseProgram reads,
+// assembly may have:
+//   - comments, starting with ";" and running to the end of the line
+//   - labels, written as "name:" either on their own line or before an instruction, which evaluate to the index of the next instruction
+//   - constants, written as ".const NAME value"
+//   - operands that add or subtract labels, constants, and numbers, such as "loop-1". "ip" is the instruction pointer register.
+//   - "jmp label", which jumps to the label
+//   - "jz reg label" and "jnz reg label", which jump to the label if the register is zero or not. The register must hold 0 or 1,
+//     like the result of a comparison.
+//
+// The macros require an "#ip" directive before they are used.
+func Assemble(source []string) (Program, error) {
+	asm := assembler{
+		program: Program{IPRegister: NoIPRegister},
+		names:   map[string]int{},
+	}
+
+	lines, err := asm.collectNames(source)
+	if err != nil {
+		return Program{}, err
+	}
+
+	for _, line := range lines {
+		err := asm.assembleLine(line)
+		if err != nil {
+			return Program{}, AssemblyError{Line: line.number, Text: line.text, Err: err}
+		}
+	}
+
+	return asm.program, nil
+}
+
+// collectNames strips comments and labels, records the index of every label and the value of every constant,
+// and returns the lines that produce instructions
+func (asm *assembler) collectNames(source []string) ([]sourceLine, error) {
+	lines := []sourceLine{}
+	numInstructions := 0
+	for i, text := range source {
+		lineNumber := i + 1
+		makeError := func(err error) error {
+			return AssemblyError{Line: lineNumber, Text: text, Err: err}
+		}
+
+		code := text
+		if commentStart := strings.Index(code, commentDelim); commentStart != -1 {
+			code = code[:commentStart]
+		}
+		code = strings.TrimSpace(code)
+
+		for {
+			labelEnd := strings.Index(code, labelSuffix)
+			if labelEnd == -1 {
+				break
+			}
+
+			label := strings.TrimSpace(code[:labelEnd])
+			if !isName(label) {
+				return nil, makeError(fmt.Errorf("invalid label %q: %w", label, ErrMalformedInput))
+			} else if _, defined := asm.names[label]; defined {
+				return nil, makeError(fmt.Errorf("%s is already defined: %w", label, ErrMalformedInput))
+			}
+			asm.names[label] = numInstructions
+			code = strings.TrimSpace(code[labelEnd+1:])
+		}
+
+		fields := strings.Fields(code)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case ipDirective:
+			err := asm.defineIP(fields)
+			if err != nil {
+				return nil, makeError(err)
+			}
+		case constDirective:
+			err := asm.defineConst(fields)
+			if err != nil {
+				return nil, makeError(err)
+			}
+		default:
+			size, isMacro := macroSizes[fields[0]]
+			if !isMacro {
+				size = 1
+			}
+			numInstructions += size
+			lines = append(lines, sourceLine{number: lineNumber, text: text, fields: fields})
+		}
+	}
+
+	return lines, nil
+}
+
+func (asm *assembler) defineIP(fields []string) error {
+	if len(fields) != 2 {
+		return ErrMalformedInput
+	} else if asm.program.HasIPRegister() {
+		return fmt.Errorf("%s may only be given once: %w", ipDirective, ErrMalformedInput)
+	}
+
+	ipRegister, err := asm.evaluate(fields[1])
+	if err != nil {
+		return err
+	}
+	asm.program.IPRegister = ipRegister
+	asm.names[ipName] = ipRegister
+
+	return nil
+}
+
+func (asm *assembler) defineConst(fields []string) error {
+	if len(fields) != 3 || !isName(fields[1]) {
+		return ErrMalformedInput
+	} else if _, defined := asm.names[fields[1]]; defined {
+		return fmt.Errorf("%s is already defined: %w", fields[1], ErrMalformedInput)
+	}
+
+	value, err := asm.evaluate(fields[2])
+	if err != nil {
+		return err
+	}
+	asm.names[fields[1]] = value
+
+	return nil
+}
+
+func (asm *assembler) assembleLine(line sourceLine) error {
+	name, rawOperands := line.fields[0], line.fields[1:]
+	operands := make([]int, len(rawOperands))
+	for i, rawOperand := range rawOperands {
+		operand, err := asm.evaluate(rawOperand)
+		if err != nil {
+			return err
+		}
+		operands[i] = operand
+	}
+
+	if _, isMacro := macroSizes[name]; isMacro {
+		return asm.expandMacro(name, operands)
+	}
+
+	op, err := ParseOpcode(name)
+	if err != nil {
+		return err
+	} else if len(operands) != numOperands {
+		return fmt.Errorf("%s takes %d operands: %w", name, numOperands, ErrMalformedInput)
+	}
+	asm.emit(Instruction{Op: op, A: operands[0], B: operands[1], C: operands[2]})
+
+	return nil
+}
+
+func (asm *assembler) expandMacro(name string, operands []int) error {
+	if !asm.program.HasIPRegister() {
+		return fmt.Errorf("%s needs %s to be set: %w", name, ipDirective, ErrMalformedInput)
+	}
+
+	ipRegister := asm.program.IPRegister
+	// The instruction pointer is incremented after every instruction, so jumps must land one before their target
+	jumpTo := func(target int) Instruction {
+		return Instruction{Op: Seti, A: target - 1, B: 0, C: ipRegister}
+	}
+
+	switch {
+	case name == jmpMacro && len(operands) == 1:
+		asm.emit(jumpTo(operands[0]))
+	case name == jzMacro && len(operands) == 2:
+		// Skip the jump if the flag is set
+		asm.emit(Instruction{Op: Addr, A: operands[0], B: ipRegister, C: ipRegister})
+		asm.emit(jumpTo(operands[1]))
+	case name == jnzMacro && len(operands) == 2:
+		// Skip the instruction that skips the jump if the flag is set
+		asm.emit(Instruction{Op: Addr, A: operands[0], B: ipRegister, C: ipRegister})
+		asm.emit(Instruction{Op: Addi, A: ipRegister, B: 1, C: ipRegister})
+		asm.emit(jumpTo(operands[1]))
+	default:
+		return fmt.Errorf("wrong number of operands for %s: %w", name, ErrMalformedInput)
+	}
+
+	return nil
+}
+
+func (asm *assembler) emit(ins Instruction) {
+	asm.program.Instructions = append(asm.program.Instructions, ins)
+}
+
+// evaluate finds the value of an operand, which is made of numbers and names joined by + or -
+func (asm *assembler) evaluate(operand string) (int, error) {
+	total := 0
+	sign := 1
+	termStart := 0
+	for i := 0; i <= len(operand); i++ {
+		// A leading sign belongs to a number, not an operator
+		if i < len(operand) && (i == termStart || (operand[i] != '+' && operand[i] != '-')) {
+			continue
+		}
+
+		value, err := asm.evaluateTerm(operand[termStart:i])
+		if err != nil {
+			return 0, err
+		}
+		total += sign * value
+
+		if i < len(operand) && operand[i] == '-' {
+			sign = -1
+		} else {
+			sign = 1
+		}
+		termStart = i + 1
+	}
+
+	return total, nil
+}
+
+func (asm *assembler) evaluateTerm(term string) (int, error) {
+	if value, err := strconv.Atoi(term); err == nil {
+		return value, nil
+	} else if !isName(term) {
+		return 0, fmt.Errorf("invalid operand %q: %w", term, ErrMalformedInput)
+	}
+
+	value, ok := asm.names[term]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", term, ErrUndefinedName)
+	}
+
+	return value, nil
+}
+
+// isName checks if a string can be used as a label or constant name
+func isName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, char := range name {
+		if char != '_' && !unicode.IsLetter(char) && (i == 0 || !unicode.IsDigit(char)) {
+			return false
+		}
+	}
+
+	return true
+}
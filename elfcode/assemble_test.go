@@ -0,0 +1,134 @@
+package elfcode
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func splitLines(text string) []string {
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func TestDecompiledProgramsAssemble(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"day19", "../day19/input.txt"},
+		{"day21", "testdata/day21.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawProgram, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			program := mustParseProgram(t, string(rawProgram))
+			decompiled := &bytes.Buffer{}
+			if err := WriteDecompiled(decompiled, program); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Assemble(splitLines(decompiled.String()))
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(got, program) {
+				t.Errorf("got program\n%s\nwant\n%s", got, program)
+			}
+		})
+	}
+}
+
+func TestAssemble(t *testing.T) {
+	source := `#ip 5
+.const LIMIT 10
+start:
+	seti 0 0 1       ; the counter
+loop: addi 1 1 1
+	gtri 1 LIMIT 2
+	jnz 2 done
+	jz 2 loop
+	jmp start
+done:
+	addi 1 LIMIT-1 3
+	seti done+1 0 ip
+`
+	// The macros expand to jumps that land one before their target, as the instruction pointer is incremented after them
+	want := mustParseProgram(t, `#ip 5
+seti 0 0 1
+addi 1 1 1
+gtri 1 10 2
+addr 2 5 5
+addi 5 1 5
+seti 8 0 5
+addr 2 5 5
+seti 0 0 5
+seti -1 0 5
+addi 1 9 3
+seti 10 0 5
+`)
+
+	got, err := Assemble(splitLines(source))
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, want) {
+		t.Errorf("got program\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		wantLine int
+		wantErr  error
+	}{
+		{"undefined label", "#ip 5\njmp nowhere\n", 2, ErrUndefinedName},
+		{"duplicate label", "here:\nhere: seti 0 0 0\n", 2, ErrMalformedInput},
+		{"duplicate constant", ".const A 1\n.const A 2\n", 2, ErrMalformedInput},
+		{"bad operand", "seti 1 2? 0\n", 1, ErrMalformedInput},
+		{"missing operand", "seti 1 2\n", 1, ErrMalformedInput},
+		{"unknown opcode", "setx 1 2 3\n", 1, ErrMalformedInput},
+		{"macro without ip", "start:\njmp start\n", 2, ErrMalformedInput},
+		{"macro with too many operands", "#ip 5\njz 1 2 3\n", 2, ErrMalformedInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Assemble(splitLines(tt.source))
+			var asmErr AssemblyError
+			if !errors.As(err, &asmErr) {
+				t.Fatalf("got error %v, want an AssemblyError", err)
+			}
+
+			if asmErr.Line != tt.wantLine {
+				t.Errorf("got error on line %d, want line %d", asmErr.Line, tt.wantLine)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssemblyErrorParseError(t *testing.T) {
+	_, err := Assemble(splitLines("#ip 5\n\tjmp nowhere ; a comment\n"))
+	var asmErr AssemblyError
+	if !errors.As(err, &asmErr) {
+		t.Fatalf("got error %v, want an AssemblyError", err)
+	}
+
+	parseErr := asmErr.ParseError(21)
+	if parseErr.Day != 21 || parseErr.Line != 2 || parseErr.Column != 0 || parseErr.Text != "\tjmp nowhere ; a comment" {
+		t.Errorf("got %+v, want day 21, line 2 with no column, and the line's text", parseErr)
+	}
+	if !errors.Is(parseErr, ErrUndefinedName) {
+		t.Errorf("got error %v, want %v", parseErr, ErrUndefinedName)
+	}
+}
@@ -0,0 +1,126 @@
+package elfcode
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+)
+
+// compiledHeader starts every compiled program. It takes the source name and the number of registers.
+const compiledHeader = `// Code generated by elfcomp from %s; DO NOT EDIT.
+
+// This program runs an elfcode program natively. Initial register values may be given as arguments, starting with register 0,
+// and the registers are printed once the program halts.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func main() {
+	var registers [%d]int
+	if len(os.Args)-1 > len(registers) {
+		fmt.Fprintf(os.Stderr, "Usage: %%s [register values...] - there are only %%d registers\n", os.Args[0], len(registers))
+		os.Exit(1)
+	}
+
+	for i, arg := range os.Args[1:] {
+		value, err := strconv.Atoi(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		registers[i] = value
+	}
+
+	run(&registers)
+	fmt.Println(registers[:])
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+`
+
+// WriteGo writes the program as a standalone Go program, where each instruction is a case of a switch on the
+// instruction pointer. Immediate operands, and reads of the instruction pointer register, are inlined as constants.
+// The program halts with the same registers as it would on a Machine. sourceName is only used to note where the
+// program came from.
+func WriteGo(writer io.Writer, program Program, numRegisters int, sourceName string) error {
+	err := program.Validate(numRegisters)
+	if err != nil {
+		return err
+	}
+
+	source := &bytes.Buffer{}
+	fmt.Fprintf(source, compiledHeader, sourceName, numRegisters)
+
+	fmt.Fprintf(source, "\nfunc run(r *[%d]int) {\n", numRegisters)
+	fmt.Fprintln(source, "ip := 0")
+	fmt.Fprintln(source, "for {")
+	fmt.Fprintln(source, "switch ip {")
+	for i, ins := range program.Instructions {
+		fmt.Fprintf(source, "case %d: // %s\n", i, ins)
+		fmt.Fprintf(source, "r[%d] = %s\n", ins.C, compiledExpression(program, i))
+		if program.HasIPRegister() && ins.C == program.IPRegister {
+			fmt.Fprintf(source, "ip = r[%d]\n", ins.C)
+		}
+	}
+	fmt.Fprintln(source, "default:")
+	if program.HasIPRegister() && len(program.Instructions) > 0 {
+		// Reads of the bound register are inlined, so it is only written to when the program halts. It is left holding
+		// the last instruction run, or whatever that instruction wrote to it, which is one before where the program left.
+		fmt.Fprintf(source, "r[%d] = ip - 1\n", program.IPRegister)
+	}
+	fmt.Fprintln(source, "return")
+	fmt.Fprintln(source, "}")
+	fmt.Fprintln(source, "ip++")
+	fmt.Fprintln(source, "}")
+	fmt.Fprintln(source, "}")
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(formatted)
+
+	return err
+}
+
+// compiledExpression renders the value the instruction at the given index computes as a Go expression
+func compiledExpression(program Program, instructionIndex int) string {
+	ins := program.Instructions[instructionIndex]
+	operand := func(value int, isRegister bool) string {
+		if !isRegister {
+			return strconv.Itoa(value)
+		} else if value == program.IPRegister {
+			// The instruction pointer register always holds the index of the instruction being run
+			return strconv.Itoa(instructionIndex)
+		}
+
+		return fmt.Sprintf("r[%d]", value)
+	}
+
+	a := operand(ins.A, ins.Op.UsesRegisterA())
+	if !ins.Op.UsesB() {
+		return a
+	}
+
+	b := operand(ins.B, ins.Op.UsesRegisterB())
+	expression := fmt.Sprintf("%s %s %s", a, operatorSymbols[ins.Op], b)
+	if ins.Op.IsComparison() {
+		return fmt.Sprintf("boolToInt(%s)", expression)
+	}
+
+	return expression
+}
@@ -0,0 +1,67 @@
+package elfcode
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// buildCompiled compiles the program to Go and builds it, returning the path to the executable
+func buildCompiled(t *testing.T, program Program) string {
+	t.Helper()
+	goPath, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is needed to build the compiled program")
+	}
+
+	dir := t.TempDir()
+	source, err := os.Create(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteGo(source, program, 6, "test")
+	source.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executable := filepath.Join(dir, "compiled")
+	build := exec.Command(goPath, "build", "-o", executable, "main.go")
+	build.Dir = dir
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("could not build compiled program: %s\n%s", err, output)
+	}
+
+	return executable
+}
+
+func TestCompiledMatchesMachine(t *testing.T) {
+	rawProgram, err := os.ReadFile("../day19/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := mustParseProgram(t, string(rawProgram))
+	executable := buildCompiled(t, program)
+	// Each of these halts quickly without any shortcuts, taking different paths through the program
+	for _, register0 := range []int{0, 7, 9, 20, 100} {
+		t.Run(fmt.Sprintf("r0=%d", register0), func(t *testing.T) {
+			machine := mustMakeMachine(t, program)
+			machine.Registers[0] = register0
+			machine.Run()
+
+			output, err := exec.Command(executable, strconv.Itoa(register0)).Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := strings.TrimSpace(string(output)), fmt.Sprint(machine.Registers); got != want {
+				t.Errorf("compiled program halted with %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// StopReason describes why the debugger stopped running the program
+type StopReason int
+
+const (
+	// StopStepped indicates that the requested number of instructions were run without anything else happening
+	StopStepped StopReason = iota
+	StopBreakpoint
+	StopWatchpoint
+	StopHalted
+)
+
+// ErrNoHistory is returned when attempting to step back past the oldest entry in the history buffer
+var ErrNoHistory = errors.New("no history to step back to")
+
+// ErrWatchIPRegister is returned when attempting to watch the register the instruction pointer is bound to, which is
+// written to before every instruction. A breakpoint should be used instead.
+var ErrWatchIPRegister = errors.New("can not watch the instruction pointer's register")
+
+// Stop describes the point the debugger stopped at
+type Stop struct {
+	Reason StopReason
+	// IP is the index of the next instruction to be run
+	IP int
+	// Register, OldValue, and NewValue are only set when Reason is StopWatchpoint
+	Register int
+	OldValue int
+	NewValue int
+}
+
+// snapshot is the state of the machine before an instruction was run, so that it can be undone
+type snapshot struct {
+	registers       Registers
+	ip              int
+	numSteps        int
+	numInstructions int
+}
+
+// Debugger wraps a machine, allowing it to be run with breakpoints and watchpoints, and stepped backwards
+type Debugger struct {
+	machine     *Machine
+	breakpoints map[int]bool
+	watchpoints map[int]bool
+	// history holds the snapshots in the order they were taken, with the oldest first
+	history     []snapshot
+	historySize int
+}
+
+func (reason StopReason) String() string {
+	switch reason {
+	case StopStepped:
+		return "stepped"
+	case StopBreakpoint:
+		return "breakpoint"
+	case StopWatchpoint:
+		return "watchpoint"
+	case StopHalted:
+		return "halted"
+	default:
+		return fmt.Sprintf("StopReason(%d)", int(reason))
+	}
+}
+
+func (stop Stop) String() string {
+	switch stop.Reason {
+	case StopBreakpoint:
+		return fmt.Sprintf("breakpoint at %d", stop.IP)
+	case StopWatchpoint:
+		return fmt.Sprintf("register %d changed %d => %d, next instruction %d", stop.Register, stop.OldValue, stop.NewValue, stop.IP)
+	case StopHalted:
+		return fmt.Sprintf("halted with ip %d", stop.IP)
+	default:
+		return fmt.Sprintf("stopped at %d", stop.IP)
+	}
+}
+
+// NewDebugger makes a debugger for the given machine, which remembers up to historySize instructions for stepping back
+func NewDebugger(machine *Machine, historySize int) *Debugger {
+	return &Debugger{
+		machine:     machine,
+		breakpoints: map[int]bool{},
+		watchpoints: map[int]bool{},
+		history:     []snapshot{},
+		historySize: historySize,
+	}
+}
+
+// Machine gets the machine being debugged
+func (d *Debugger) Machine() *Machine {
+	return d.machine
+}
+
+// AddBreakpoint makes the debugger stop before running the instruction at the given index
+func (d *Debugger) AddBreakpoint(instructionIndex int) error {
+	if instructionIndex < 0 || instructionIndex >= len(d.machine.program.Instructions) {
+		return fmt.Errorf("no instruction at %d", instructionIndex)
+	}
+
+	d.breakpoints[instructionIndex] = true
+
+	return nil
+}
+
+// RemoveBreakpoint removes a breakpoint previously added by AddBreakpoint
+func (d *Debugger) RemoveBreakpoint(instructionIndex int) {
+	delete(d.breakpoints, instructionIndex)
+}
+
+// Breakpoints gets the sorted instruction indices of all breakpoints
+func (d *Debugger) Breakpoints() []int {
+	return sortedKeys(d.breakpoints)
+}
+
+// AddWatchpoint makes the debugger stop whenever the given register changes value
+func (d *Debugger) AddWatchpoint(register int) error {
+	if register < 0 || register >= len(d.machine.Registers) {
+		return fmt.Errorf("register %d: %w", register, ErrInvalidRegister)
+	} else if d.machine.program.HasIPRegister() && register == d.machine.program.IPRegister {
+		return fmt.Errorf("register %d: %w", register, ErrWatchIPRegister)
+	}
+
+	d.watchpoints[register] = true
+
+	return nil
+}
+
+// RemoveWatchpoint removes a watchpoint previously added by AddWatchpoint
+func (d *Debugger) RemoveWatchpoint(register int) {
+	delete(d.watchpoints, register)
+}
+
+// Watchpoints gets the sorted register numbers of all watchpoints
+func (d *Debugger) Watchpoints() []int {
+	return sortedKeys(d.watchpoints)
+}
+
+// SetRegister changes the value of a register while the program is paused. Setting the register the instruction pointer
+// is bound to moves the instruction pointer too. This can not be undone with StepBack.
+func (d *Debugger) SetRegister(register int, value int) error {
+	if register < 0 || register >= len(d.machine.Registers) {
+		return fmt.Errorf("register %d: %w", register, ErrInvalidRegister)
+	}
+
+	// The machine overwrites the bound register with its instruction pointer before each instruction
+	if d.machine.program.HasIPRegister() && register == d.machine.program.IPRegister {
+		d.machine.SetIP(value)
+		return nil
+	}
+
+	d.machine.Registers[register] = value
+
+	return nil
+}
+
+// Step runs a single instruction, regardless of any breakpoint on it
+func (d *Debugger) Step() Stop {
+	return d.RunN(1)
+}
+
+// RunN runs up to n instructions, stopping early if a breakpoint or watchpoint is hit, or the program halts.
+// A breakpoint on the instruction the machine is currently paused at is ignored, so that it is possible to continue from it.
+func (d *Debugger) RunN(n int) Stop {
+	for i := 0; i < n; i++ {
+		if d.machine.Halted() {
+			return Stop{Reason: StopHalted, IP: d.machine.IP()}
+		} else if i > 0 && d.breakpoints[d.machine.IP()] {
+			return Stop{Reason: StopBreakpoint, IP: d.machine.IP()}
+		}
+
+		before := d.makeSnapshot()
+		d.recordHistory(before)
+		d.machine.Step()
+		for _, register := range d.Watchpoints() {
+			if before.registers[register] != d.machine.Registers[register] {
+				return Stop{
+					Reason:   StopWatchpoint,
+					IP:       d.machine.IP(),
+					Register: register,
+					OldValue: before.registers[register],
+					NewValue: d.machine.Registers[register],
+				}
+			}
+		}
+	}
+
+	if d.machine.Halted() {
+		return Stop{Reason: StopHalted, IP: d.machine.IP()}
+	}
+
+	return Stop{Reason: StopStepped, IP: d.machine.IP()}
+}
+
+// Continue runs the program until a breakpoint or watchpoint is hit, or the program halts
+func (d *Debugger) Continue() Stop {
+	for {
+		stop := d.RunN(1)
+		if stop.Reason != StopStepped {
+			return stop
+		} else if d.breakpoints[stop.IP] {
+			return Stop{Reason: StopBreakpoint, IP: stop.IP}
+		}
+	}
+}
+
+// StepBack undoes the last instruction that was run, using the history buffer
+func (d *Debugger) StepBack() error {
+	if len(d.history) == 0 {
+		return ErrNoHistory
+	}
+
+	entry := d.history[len(d.history)-1]
+	d.history = d.history[:len(d.history)-1]
+	copy(d.machine.Registers, entry.registers)
+	d.machine.ip = entry.ip
+	d.machine.numSteps = entry.numSteps
+	d.machine.numInstructions = entry.numInstructions
+
+	return nil
+}
+
+// HistoryLen gets the number of instructions that can be undone with StepBack
+func (d *Debugger) HistoryLen() int {
+	return len(d.history)
+}
+
+func (d *Debugger) recordHistory(entry snapshot) {
+	if d.historySize <= 0 {
+		return
+	}
+
+	// Drop the oldest entry once the buffer is full
+	if len(d.history) == d.historySize {
+		d.history = d.history[1:]
+	}
+	d.history = append(d.history, entry)
+}
+
+func (d *Debugger) makeSnapshot() snapshot {
+	return snapshot{
+		registers:       d.machine.Registers.Clone(),
+		ip:              d.machine.ip,
+		numSteps:        d.machine.numSteps,
+		numInstructions: d.machine.numInstructions,
+	}
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
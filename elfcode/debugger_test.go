@@ -0,0 +1,47 @@
+package elfcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetIPRegister(t *testing.T) {
+	debugger := NewDebugger(mustMakeMachine(t, mustParseProgram(t, day19Example)), 0)
+	// Jump straight to the last instruction
+	if err := debugger.SetRegister(0, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := debugger.Step()
+	if stop.Reason != StopHalted {
+		t.Errorf("got stop %v at %d, want %v", stop.Reason, stop.IP, StopHalted)
+	}
+
+	want := Registers{6, 0, 0, 0, 0, 9}
+	if !debugger.machine.Registers.Equal(want) {
+		t.Errorf("got registers %v, want %v", debugger.machine.Registers, want)
+	}
+}
+
+func TestWatchpoint(t *testing.T) {
+	debugger := NewDebugger(mustMakeMachine(t, mustParseProgram(t, day19Example)), 0)
+	if err := debugger.AddWatchpoint(5); err != nil {
+		t.Fatal(err)
+	}
+
+	// Register 5 is only written to by the last instruction
+	want := Stop{Reason: StopWatchpoint, IP: 7, Register: 5, OldValue: 0, NewValue: 9}
+	if stop := debugger.Continue(); stop != want {
+		t.Errorf("got stop %v, want %v", stop, want)
+	}
+}
+
+func TestWatchIPRegister(t *testing.T) {
+	debugger := NewDebugger(mustMakeMachine(t, mustParseProgram(t, day19Example)), 0)
+	if err := debugger.AddWatchpoint(0); !errors.Is(err, ErrWatchIPRegister) {
+		t.Errorf("got error %v, want %v", err, ErrWatchIPRegister)
+	}
+	if watchpoints := debugger.Watchpoints(); len(watchpoints) != 0 {
+		t.Errorf("got watchpoints %v, want none", watchpoints)
+	}
+}
@@ -0,0 +1,172 @@
+package elfcode
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// programCounterName is used in place of the instruction pointer register in pseudo-code
+const programCounterName = "pc"
+
+// Can't use a constant for an array - this is our next best thing
+var operatorSymbols = [NumOpcodes]string{"+", "+", "*", "*", "&", "&", "|", "|", "", "", ">", ">", ">", "==", "==", "=="}
+
+// Label gets the name used for the block starting at the given instruction index, or "halt" if it is outside of the program
+func (cfg *ControlFlowGraph) Label(instructionIndex int) string {
+	if instructionIndex < 0 || instructionIndex >= len(cfg.Program.Instructions) {
+		return "halt"
+	}
+
+	return fmt.Sprintf("L%d", instructionIndex)
+}
+
+// registerName gets the name of a register for use in pseudo-code
+func (cfg *ControlFlowGraph) registerName(register int) string {
+	if register == cfg.Program.IPRegister {
+		return programCounterName
+	}
+
+	return fmt.Sprintf("r%d", register)
+}
+
+// operand renders a single operand of the instruction at the given index, folding reads of the program counter into constants
+func (cfg *ControlFlowGraph) operand(instructionIndex int, value int, isRegister bool) string {
+	if !isRegister {
+		return strconv.Itoa(value)
+	} else if value == cfg.Program.IPRegister {
+		return strconv.Itoa(instructionIndex)
+	}
+
+	return cfg.registerName(value)
+}
+
+// Expression renders the value the instruction at the given index computes, e.g. "r1 * r5".
+// If the result can be determined without running the program, the folded constant is given instead.
+func (cfg *ControlFlowGraph) Expression(instructionIndex int) string {
+	if result, ok := cfg.evaluate(instructionIndex); ok {
+		return strconv.Itoa(result)
+	}
+
+	ins := cfg.Program.Instructions[instructionIndex]
+	a := cfg.operand(instructionIndex, ins.A, ins.Op.UsesRegisterA())
+	if !ins.Op.UsesB() {
+		return a
+	}
+
+	b := cfg.operand(instructionIndex, ins.B, ins.Op.UsesRegisterB())
+
+	return fmt.Sprintf("%s %s %s", a, operatorSymbols[ins.Op], b)
+}
+
+// Statement renders the instruction at the given index as a line of pseudo-code, e.g. "r1 = r1 * r5" or "if r3 > r2 goto L12"
+func (cfg *ControlFlowGraph) Statement(instructionIndex int) string {
+	ins := cfg.Program.Instructions[instructionIndex]
+	flow := cfg.Flows[instructionIndex]
+	switch flow.Kind {
+	case FlowJump:
+		if target := flow.Targets[0]; target == instructionIndex+1 {
+			return "nop"
+		} else if target < 0 || target >= len(cfg.Program.Instructions) {
+			return "halt"
+		}
+
+		return "goto " + cfg.Label(flow.Targets[0])
+	case FlowBranch:
+		return fmt.Sprintf("if %s goto %s", cfg.branchCondition(instructionIndex), cfg.Label(flow.Targets[1]))
+	case FlowIndirect:
+		return fmt.Sprintf("goto (%s) + 1", cfg.Expression(instructionIndex))
+	default:
+		return fmt.Sprintf("%s = %s", cfg.registerName(ins.C), cfg.Expression(instructionIndex))
+	}
+}
+
+// branchCondition renders the condition a branch is taken on.
+// The comparison that set the flag is inlined if it did not overwrite one of its own operands.
+func (cfg *ControlFlowGraph) branchCondition(instructionIndex int) string {
+	flow := cfg.Flows[instructionIndex]
+	flagName := cfg.registerName(flow.Flag)
+	comparison := cfg.Program.Instructions[flow.Condition]
+	overwritesOperand := (comparison.Op.UsesRegisterA() && comparison.A == comparison.C) ||
+		(comparison.Op.UsesRegisterB() && comparison.B == comparison.C)
+	// Any other write between the comparison and the branch would make inlining it wrong
+	for i := flow.Condition + 1; i < instructionIndex; i++ {
+		written := cfg.Program.Instructions[i].C
+		if (comparison.Op.UsesRegisterA() && comparison.A == written) || (comparison.Op.UsesRegisterB() && comparison.B == written) {
+			overwritesOperand = true
+		}
+	}
+
+	if overwritesOperand {
+		return flagName
+	}
+
+	return cfg.Expression(flow.Condition)
+}
+
+// WriteDecompiled writes the program as annotated assembly, with each instruction followed by a pseudo-code comment.
+// Blocks are labelled by the index of their first instruction, and loops are noted on their header.
+// Since every annotation is a comment, the output can be assembled back into the original program.
+func WriteDecompiled(writer io.Writer, program Program) error {
+	cfg := NewControlFlowGraph(program)
+	loopsByHeader := make(map[int]Loop, len(cfg.Loops))
+	for _, loop := range cfg.Loops {
+		loopsByHeader[loop.Header] = loop
+	}
+	shortcutsByStart := map[int]Shortcut{}
+	for _, shortcut := range FindShortcuts(program) {
+		shortcutsByStart[shortcut.Start] = shortcut
+	}
+
+	lines := []string{}
+	if program.HasIPRegister() {
+		lines = append(lines,
+			fmt.Sprintf(ipFormat, program.IPRegister),
+			fmt.Sprintf("; r%d is the program counter, shown as %s", program.IPRegister, programCounterName),
+		)
+	}
+	if cfg.HasIndirectJumps {
+		lines = append(lines, "; warning: the program has jumps that could not be resolved, so blocks and loops may be incomplete")
+	}
+
+	for _, block := range cfg.Blocks {
+		annotations := []string{}
+		if len(block.Predecessors) > 0 {
+			annotations = append(annotations, "from "+cfg.labelList(block.Predecessors))
+		}
+		if loop, ok := loopsByHeader[block.Start]; ok {
+			annotations = append(annotations, fmt.Sprintf("loop over %s, repeated from %s", cfg.labelList(loop.Blocks), cfg.labelList(loop.Latches)))
+		}
+		if !block.Reachable {
+			annotations = append(annotations, "unreachable")
+		}
+
+		lines = append(lines, "")
+		labelLine := cfg.Label(block.Start) + ":"
+		if len(annotations) > 0 {
+			labelLine += " ; " + strings.Join(annotations, "; ")
+		}
+		lines = append(lines, labelLine)
+
+		for i := block.Start; i < block.End; i++ {
+			if shortcut, ok := shortcutsByStart[i]; ok {
+				lines = append(lines, fmt.Sprintf("\t; the optimizer replaces %s with a native operation", shortcut))
+			}
+			lines = append(lines, fmt.Sprintf("\t%-20s ; %3d: %s", program.Instructions[i], i, cfg.Statement(i)))
+		}
+	}
+
+	_, err := io.WriteString(writer, strings.Join(lines, "\n")+"\n")
+
+	return err
+}
+
+func (cfg *ControlFlowGraph) labelList(instructionIndices []int) string {
+	labels := make([]string, len(instructionIndices))
+	for i, instructionIndex := range instructionIndices {
+		labels[i] = cfg.Label(instructionIndex)
+	}
+
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,31 @@
+package elfcode
+
+import "testing"
+
+func TestStatement(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		index  int
+		want   string
+	}{
+		{"assignment", countingLoop, 1, "r1 = r1 + 1"},
+		{"comparison", countingLoop, 2, "r2 = r1 == 5"},
+		{"branch", countingLoop, 3, "if r1 == 5 goto L5"},
+		{"jump", countingLoop, 4, "goto L1"},
+		{"halt", "#ip 5\nseti 9 0 5\n", 0, "halt"},
+		{"nop", "#ip 5\naddi 5 0 5\nseti 9 0 0\n", 0, "nop"},
+		// r1 is overwritten by the comparison, so it can't be inlined into the branch
+		{"branch on an overwritten operand", "#ip 5\neqri 1 5 1\naddr 1 5 5\nseti 9 0 0\n", 1, "if r1 goto halt"},
+		{"flag without a comparison", "#ip 5\naddi 1 1 2\naddr 2 5 5\nseti 9 0 0\n", 1, "goto (r2 + 1) + 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewControlFlowGraph(mustParseProgram(t, tt.source))
+			if got := cfg.Statement(tt.index); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
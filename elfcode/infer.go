@@ -0,0 +1,276 @@
+package elfcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sample is an observation of the device running a single instruction whose opcode number is not yet known
+type Sample struct {
+	Before Registers
+	// Instruction holds the opcode number, followed by the A, B, and C operands
+	Instruction [4]int
+	After       Registers
+}
+
+// Assignment maps opcode numbers to the opcodes they represent
+type Assignment map[int]Opcode
+
+// Inference is the result of working out which opcode each opcode number represents
+type Inference struct {
+	// Candidates maps each opcode number seen in the samples to the opcodes that are consistent with all of its samples
+	Candidates map[int][]Opcode
+	// Assignments holds every assignment consistent with the samples, up to the limit given to InferOpcodes
+	Assignments []Assignment
+	// Truncated indicates that there were more assignments than the limit
+	Truncated bool
+	// Conflict holds the indices of a minimal set of samples that contradict each other, if there are no valid assignments
+	Conflict []int
+}
+
+// AmbiguityError is returned when the samples allow for more than one assignment of opcodes
+type AmbiguityError struct {
+	Inference Inference
+}
+
+// ContradictionError is returned when no assignment of opcodes is consistent with every sample
+type ContradictionError struct {
+	// Samples holds the indices of a minimal set of samples that contradict each other
+	Samples []int
+}
+
+func (err AmbiguityError) Error() string {
+	numAssignments := fmt.Sprintf("%d", len(err.Inference.Assignments))
+	if err.Inference.Truncated {
+		numAssignments = "at least " + numAssignments
+	}
+
+	unresolved := []string{}
+	for _, opcodeNumber := range err.Inference.opcodeNumbers() {
+		possible := err.Inference.possibleOpcodes(opcodeNumber)
+		if len(possible) > 1 {
+			unresolved = append(unresolved, fmt.Sprintf("%d could be %v", opcodeNumber, possible))
+		}
+	}
+
+	return fmt.Sprintf("opcodes are ambiguous, %s assignments are possible: %s", numAssignments, strings.Join(unresolved, "; "))
+}
+
+func (err ContradictionError) Error() string {
+	return fmt.Sprintf("samples %v contradict each other", err.Samples)
+}
+
+// MatchingOpcodes gets every opcode that would produce the sample's result
+func (sample Sample) MatchingOpcodes() []Opcode {
+	matchingOpcodes := []Opcode{}
+	for op := Opcode(0); op < NumOpcodes; op++ {
+		ins := Instruction{Op: op, A: sample.Instruction[1], B: sample.Instruction[2], C: sample.Instruction[3]}
+		// An opcode can't match if it would touch a register the device doesn't have
+		if len(sample.Before) != len(sample.After) || ins.Validate(len(sample.Before)) != nil {
+			continue
+		}
+
+		registers := sample.Before.Clone()
+		ins.Apply(registers)
+		if registers.Equal(sample.After) {
+			matchingOpcodes = append(matchingOpcodes, op)
+		}
+	}
+
+	return matchingOpcodes
+}
+
+// InferOpcodes works out which opcode each opcode number in the samples represents. Candidates are narrowed by
+// elimination, and a backtracking search finds up to maxAssignments assignments of opcodes, each opcode used at most once.
+// maxAssignments must be at least 2 for ambiguity to be detected. If there are no assignments, a minimal set of contradicting samples is found.
+func InferOpcodes(samples []Sample, maxAssignments int) Inference {
+	matches := make([][]Opcode, len(samples))
+	for i, sample := range samples {
+		matches[i] = sample.MatchingOpcodes()
+	}
+
+	allSamples := make([]int, len(samples))
+	for i := range allSamples {
+		allSamples[i] = i
+	}
+
+	inference := Inference{Candidates: findCandidates(samples, matches, allSamples)}
+	inference.Assignments, inference.Truncated = findAssignments(inference.Candidates, maxAssignments)
+	if len(inference.Assignments) == 0 {
+		inference.Conflict = findConflict(samples, matches)
+	}
+
+	return inference
+}
+
+// Assignment gets the only assignment of opcodes consistent with the samples.
+// An AmbiguityError or ContradictionError is returned if there is not exactly one.
+func (inference Inference) Assignment() (Assignment, error) {
+	if len(inference.Assignments) == 0 {
+		return nil, ContradictionError{Samples: inference.Conflict}
+	} else if len(inference.Assignments) > 1 {
+		return nil, AmbiguityError{Inference: inference}
+	}
+
+	return inference.Assignments[0], nil
+}
+
+func (inference Inference) opcodeNumbers() []int {
+	opcodeNumbers := make([]int, 0, len(inference.Candidates))
+	for opcodeNumber := range inference.Candidates {
+		opcodeNumbers = append(opcodeNumbers, opcodeNumber)
+	}
+	sort.Ints(opcodeNumbers)
+
+	return opcodeNumbers
+}
+
+// possibleOpcodes gets the opcodes the given number maps to in at least one of the found assignments
+func (inference Inference) possibleOpcodes(opcodeNumber int) []Opcode {
+	seen := map[Opcode]bool{}
+	possible := []Opcode{}
+	for _, assignment := range inference.Assignments {
+		op := assignment[opcodeNumber]
+		if !seen[op] {
+			seen[op] = true
+			possible = append(possible, op)
+		}
+	}
+	sort.Slice(possible, func(i, j int) bool {
+		return possible[i] < possible[j]
+	})
+
+	return possible
+}
+
+// findCandidates intersects the matching opcodes of every given sample with the same opcode number
+func findCandidates(samples []Sample, matches [][]Opcode, sampleIndices []int) map[int][]Opcode {
+	candidates := map[int][]Opcode{}
+	for _, i := range sampleIndices {
+		opcodeNumber := samples[i].Instruction[0]
+		existing, haveOpcode := candidates[opcodeNumber]
+		if !haveOpcode {
+			candidates[opcodeNumber] = matches[i]
+			continue
+		}
+
+		intersection := []Opcode{}
+		for _, op := range existing {
+			for _, matchingOp := range matches[i] {
+				if op == matchingOp {
+					intersection = append(intersection, op)
+					break
+				}
+			}
+		}
+		candidates[opcodeNumber] = intersection
+	}
+
+	return candidates
+}
+
+// findAssignments finds up to limit assignments, where each opcode number maps to a distinct opcode from its candidates.
+// It returns whether there were more than limit assignments.
+func findAssignments(candidates map[int][]Opcode, limit int) ([]Assignment, bool) {
+	assignments := []Assignment{}
+	truncated := false
+	current := Assignment{}
+	used := map[Opcode]bool{}
+
+	var search func()
+	search = func() {
+		if truncated {
+			return
+		}
+
+		// Pick the unassigned opcode number with the fewest remaining options - this makes the search mostly elimination
+		bestNumber := 0
+		var bestOptions []Opcode
+		haveBest := false
+		for opcodeNumber, options := range candidates {
+			if _, assigned := current[opcodeNumber]; assigned {
+				continue
+			}
+
+			remaining := []Opcode{}
+			for _, op := range options {
+				if !used[op] {
+					remaining = append(remaining, op)
+				}
+			}
+			if !haveBest || len(remaining) < len(bestOptions) || (len(remaining) == len(bestOptions) && opcodeNumber < bestNumber) {
+				haveBest = true
+				bestNumber = opcodeNumber
+				bestOptions = remaining
+			}
+		}
+
+		if !haveBest {
+			if len(assignments) == limit {
+				truncated = true
+				return
+			}
+
+			found := make(Assignment, len(current))
+			for opcodeNumber, op := range current {
+				found[opcodeNumber] = op
+			}
+			assignments = append(assignments, found)
+			return
+		}
+
+		for _, op := range bestOptions {
+			current[bestNumber] = op
+			used[op] = true
+			search()
+			delete(current, bestNumber)
+			delete(used, op)
+		}
+	}
+	search()
+
+	return assignments, truncated
+}
+
+// findConflict finds a minimal set of samples with no valid assignment, by removing every sample that isn't needed for the contradiction
+func findConflict(samples []Sample, matches [][]Opcode) []int {
+	// A sample that matches nothing contradicts itself
+	for i, sampleMatches := range matches {
+		if len(sampleMatches) == 0 {
+			return []int{i}
+		}
+	}
+
+	conflict := make([]int, len(samples))
+	for i := range conflict {
+		conflict[i] = i
+	}
+
+	// If a single opcode number has no candidates, only its samples are needed, which saves searching through the rest
+	for opcodeNumber, options := range findCandidates(samples, matches, conflict) {
+		if len(options) != 0 {
+			continue
+		}
+
+		conflict = []int{}
+		for i, sample := range samples {
+			if sample.Instruction[0] == opcodeNumber {
+				conflict = append(conflict, i)
+			}
+		}
+		break
+	}
+
+	for i := 0; i < len(conflict); {
+		without := append(append([]int{}, conflict[:i]...), conflict[i+1:]...)
+		assignments, _ := findAssignments(findCandidates(samples, matches, without), 1)
+		if len(assignments) == 0 {
+			conflict = without
+		} else {
+			i++
+		}
+	}
+
+	return conflict
+}
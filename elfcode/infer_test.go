@@ -0,0 +1,114 @@
+package elfcode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var (
+	// setiSample can only be seti, as every other opcode would read the nonexistent register 5
+	setiSample = Sample{Before: Registers{0, 0, 0, 0}, Instruction: [4]int{0, 5, 0, 1}, After: Registers{0, 5, 0, 0}}
+	// addOrSample could be addr or borr, since 3 + 4 == 3 | 4
+	addOrSample = Sample{Before: Registers{3, 4, 0, 0}, Instruction: [4]int{1, 0, 1, 2}, After: Registers{3, 4, 7, 0}}
+	// addSample can only be addr, as 1 | 1 == 1
+	addSample = Sample{Before: Registers{1, 1, 0, 0}, Instruction: [4]int{1, 0, 1, 2}, After: Registers{1, 1, 2, 0}}
+)
+
+// withOpcodeNumber copies the sample, giving it a different opcode number
+func withOpcodeNumber(sample Sample, opcodeNumber int) Sample {
+	sample.Instruction[0] = opcodeNumber
+	return sample
+}
+
+func TestInferOpcodesUnique(t *testing.T) {
+	inference := InferOpcodes([]Sample{setiSample, addOrSample, addSample}, 2)
+	wantCandidates := map[int][]Opcode{0: {Seti}, 1: {Addr}}
+	if !reflect.DeepEqual(inference.Candidates, wantCandidates) {
+		t.Errorf("got candidates %v, want %v", inference.Candidates, wantCandidates)
+	}
+
+	assignment, err := inference.Assignment()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Assignment{0: Seti, 1: Addr}
+	if !reflect.DeepEqual(assignment, want) {
+		t.Errorf("got assignment %v, want %v", assignment, want)
+	}
+}
+
+func TestInferOpcodesAmbiguous(t *testing.T) {
+	inference := InferOpcodes([]Sample{setiSample, addOrSample}, 2)
+	wantCandidates := map[int][]Opcode{0: {Seti}, 1: {Addr, Borr}}
+	if !reflect.DeepEqual(inference.Candidates, wantCandidates) {
+		t.Errorf("got candidates %v, want %v", inference.Candidates, wantCandidates)
+	}
+
+	wantAssignments := []Assignment{{0: Seti, 1: Addr}, {0: Seti, 1: Borr}}
+	if !reflect.DeepEqual(inference.Assignments, wantAssignments) {
+		t.Errorf("got assignments %v, want %v", inference.Assignments, wantAssignments)
+	}
+	if inference.Truncated {
+		t.Error("expected every assignment to be found")
+	}
+
+	_, err := inference.Assignment()
+	var ambiguityErr AmbiguityError
+	if !errors.As(err, &ambiguityErr) {
+		t.Errorf("got error %v, want an AmbiguityError", err)
+	}
+}
+
+func TestInferOpcodesTruncated(t *testing.T) {
+	inference := InferOpcodes([]Sample{setiSample, addOrSample}, 1)
+	if len(inference.Assignments) != 1 || !inference.Truncated {
+		t.Errorf("got %d assignments, truncated %t, want 1 assignment, truncated", len(inference.Assignments), inference.Truncated)
+	}
+}
+
+func TestInferOpcodesContradiction(t *testing.T) {
+	tests := []struct {
+		name         string
+		samples      []Sample
+		wantConflict []int
+	}{
+		{
+			"sample matching nothing",
+			[]Sample{
+				setiSample,
+				{Before: Registers{0, 0, 0, 0}, Instruction: [4]int{1, 0, 0, 1}, After: Registers{0, 9, 0, 0}},
+				addSample,
+			},
+			[]int{1},
+		},
+		{
+			"opcode number with no candidates",
+			[]Sample{setiSample, addOrSample, withOpcodeNumber(addSample, 0)},
+			[]int{0, 2},
+		},
+		{
+			"opcode used twice",
+			[]Sample{setiSample, addOrSample, withOpcodeNumber(setiSample, 2), addSample},
+			[]int{0, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inference := InferOpcodes(tt.samples, 2)
+			if len(inference.Assignments) != 0 {
+				t.Fatalf("got assignments %v, want none", inference.Assignments)
+			}
+
+			_, err := inference.Assignment()
+			var contradictionErr ContradictionError
+			if !errors.As(err, &contradictionErr) {
+				t.Fatalf("got error %v, want a ContradictionError", err)
+			} else if !reflect.DeepEqual(contradictionErr.Samples, tt.wantConflict) {
+				t.Errorf("got conflicting samples %v, want %v", contradictionErr.Samples, tt.wantConflict)
+			}
+		})
+	}
+}
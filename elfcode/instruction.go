@@ -0,0 +1,243 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Opcode identifies one of the sixteen operations the device supports
+type Opcode int
+
+// Registers holds the state of every register on the device
+type Registers []int
+
+// Instruction is a single decoded elfcode instruction
+type Instruction struct {
+	Op      Opcode
+	A, B, C int
+}
+
+const (
+	Addr Opcode = iota
+	Addi
+	Mulr
+	Muli
+	Banr
+	Bani
+	Borr
+	Bori
+	Setr
+	Seti
+	Gtir
+	Gtri
+	Gtrr
+	Eqir
+	Eqri
+	Eqrr
+	// NumOpcodes is the number of distinct opcodes, not an opcode itself
+	NumOpcodes
+)
+
+const instructionFormat = "%s %d %d %d"
+
+// ErrMalformedInput is returned when an instruction or program cannot be parsed
+var ErrMalformedInput = errors.New("malformed input")
+
+// ErrInvalidRegister is returned when an instruction refers to a register that does not exist
+var ErrInvalidRegister = errors.New("invalid register")
+
+// Can't use a constant for an array - this is our next best thing
+var opcodeNames = [NumOpcodes]string{"addr", "addi", "mulr", "muli", "banr", "bani", "borr", "bori", "setr", "seti", "gtir", "gtri", "gtrr", "eqir", "eqri", "eqrr"}
+
+// ParseOpcode gets the opcode with the given name (e.g. "addr")
+func ParseOpcode(name string) (Opcode, error) {
+	for i, opcodeName := range opcodeNames {
+		if opcodeName == name {
+			return Opcode(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown opcode %q: %w", name, ErrMalformedInput)
+}
+
+func (op Opcode) String() string {
+	if op < 0 || op >= NumOpcodes {
+		return fmt.Sprintf("Opcode(%d)", int(op))
+	}
+
+	return opcodeNames[op]
+}
+
+// UsesRegisterA indicates whether the A operand of this opcode refers to a register, rather than an immediate value
+func (op Opcode) UsesRegisterA() bool {
+	switch op {
+	case Seti, Gtir, Eqir:
+		return false
+	default:
+		return true
+	}
+}
+
+// UsesRegisterB indicates whether the B operand of this opcode refers to a register, rather than an immediate value
+func (op Opcode) UsesRegisterB() bool {
+	switch op {
+	case Addr, Mulr, Banr, Borr, Gtir, Gtrr, Eqir, Eqrr:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesB indicates whether the B operand of this opcode is read at all. setr and seti ignore it.
+func (op Opcode) UsesB() bool {
+	return op != Setr && op != Seti
+}
+
+// NewRegisters makes a zeroed register set with the given number of registers
+func NewRegisters(numRegisters int) Registers {
+	return make(Registers, numRegisters)
+}
+
+// Clone makes a copy of the register set that can be modified independently
+func (registers Registers) Clone() Registers {
+	clone := make(Registers, len(registers))
+	copy(clone, registers)
+
+	return clone
+}
+
+// Equal checks if two register sets hold identical values
+func (registers Registers) Equal(other Registers) bool {
+	if len(registers) != len(other) {
+		return false
+	}
+
+	for i := range registers {
+		if registers[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseInstruction parses a single instruction of the form "addi 5 16 5"
+func ParseInstruction(rawInstruction string) (Instruction, error) {
+	var operationName string
+	var ins Instruction
+	numMatched, err := fmt.Sscanf(rawInstruction, instructionFormat, &operationName, &ins.A, &ins.B, &ins.C)
+	if err != nil {
+		return Instruction{}, err
+	} else if numMatched != 4 {
+		return Instruction{}, ErrMalformedInput
+	}
+
+	ins.Op, err = ParseOpcode(operationName)
+	if err != nil {
+		return Instruction{}, err
+	}
+
+	return ins, nil
+}
+
+func (ins Instruction) String() string {
+	return fmt.Sprintf(instructionFormat, ins.Op, ins.A, ins.B, ins.C)
+}
+
+// Validate checks that every register this instruction touches exists in a device with the given number of registers
+func (ins Instruction) Validate(numRegisters int) error {
+	if ins.Op < 0 || ins.Op >= NumOpcodes {
+		return fmt.Errorf("%s: %w", ins.Op, ErrMalformedInput)
+	}
+
+	inRange := func(register int) bool {
+		return register >= 0 && register < numRegisters
+	}
+	if ins.Op.UsesRegisterA() && !inRange(ins.A) {
+		return fmt.Errorf("%s: register %d: %w", ins, ins.A, ErrInvalidRegister)
+	} else if ins.Op.UsesRegisterB() && !inRange(ins.B) {
+		return fmt.Errorf("%s: register %d: %w", ins, ins.B, ErrInvalidRegister)
+	} else if !inRange(ins.C) {
+		return fmt.Errorf("%s: register %d: %w", ins, ins.C, ErrInvalidRegister)
+	}
+
+	return nil
+}
+
+// Apply executes the instruction against the given registers, modifying them in place.
+// The instruction must be valid for the register set (see Validate)
+func (ins Instruction) Apply(registers Registers) {
+	var result int
+	switch ins.Op {
+	case Addr:
+		result = registers[ins.A] + registers[ins.B]
+	case Addi:
+		result = registers[ins.A] + ins.B
+	case Mulr:
+		result = registers[ins.A] * registers[ins.B]
+	case Muli:
+		result = registers[ins.A] * ins.B
+	case Banr:
+		result = registers[ins.A] & registers[ins.B]
+	case Bani:
+		result = registers[ins.A] & ins.B
+	case Borr:
+		result = registers[ins.A] | registers[ins.B]
+	case Bori:
+		result = registers[ins.A] | ins.B
+	case Setr:
+		result = registers[ins.A]
+	case Seti:
+		result = ins.A
+	case Gtir:
+		result = boolToInt(ins.A > registers[ins.B])
+	case Gtri:
+		result = boolToInt(registers[ins.A] > ins.B)
+	case Gtrr:
+		result = boolToInt(registers[ins.A] > registers[ins.B])
+	case Eqir:
+		result = boolToInt(ins.A == registers[ins.B])
+	case Eqri:
+		result = boolToInt(registers[ins.A] == ins.B)
+	case Eqrr:
+		result = boolToInt(registers[ins.A] == registers[ins.B])
+	}
+
+	registers[ins.C] = result
+}
+
+// Compute gets the result of the opcode given the already resolved values of its operands.
+// For opcodes that take a register as an operand, the value is the contents of the register rather than the register number.
+func (op Opcode) Compute(a int, b int) int {
+	switch op {
+	case Addr, Addi:
+		return a + b
+	case Mulr, Muli:
+		return a * b
+	case Banr, Bani:
+		return a & b
+	case Borr, Bori:
+		return a | b
+	case Setr, Seti:
+		return a
+	case Gtir, Gtri, Gtrr:
+		return boolToInt(a > b)
+	case Eqir, Eqri, Eqrr:
+		return boolToInt(a == b)
+	default:
+		return 0
+	}
+}
+
+// IsComparison indicates whether the opcode is one of the gt or eq family, which always produce 0 or 1
+func (op Opcode) IsComparison() bool {
+	return op >= Gtir && op <= Eqrr
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,127 @@
+// Package elfcode implements the time travel device that runs the elfcode programs from days 16, 19, and 21
+package elfcode
+
+import "errors"
+
+// ErrHalted is returned when attempting to step a machine whose instruction pointer has left the program
+var ErrHalted = errors.New("halted")
+
+// Machine runs an elfcode program against a set of registers
+type Machine struct {
+	Registers Registers
+	program   Program
+	// ip is kept apart from the register it is bound to, which is only written to while an instruction runs.
+	// This leaves the register holding the last instruction that was run once the program halts, as the puzzle describes.
+	ip       int
+	numSteps int
+	// numInstructions differs from numSteps by counting every instruction a shortcut stands in for
+	numInstructions int
+	// shortcuts is indexed by instruction, and is nil until a shortcut is added
+	shortcuts []*Shortcut
+}
+
+// NewMachine makes a machine with zeroed registers that will run the given program
+func NewMachine(program Program, numRegisters int) (*Machine, error) {
+	err := program.Validate(numRegisters)
+	if err != nil {
+		return nil, err
+	}
+
+	machine := &Machine{
+		Registers: NewRegisters(numRegisters),
+		program:   program,
+	}
+
+	return machine, nil
+}
+
+// Clone makes a copy of the machine, in the same state, which can be run without affecting the original
+func (m *Machine) Clone() *Machine {
+	clone := *m
+	clone.Registers = m.Registers.Clone()
+	clone.shortcuts = append([]*Shortcut(nil), m.shortcuts...)
+
+	return &clone
+}
+
+// Program gets the program the machine is running
+func (m *Machine) Program() Program {
+	return m.program
+}
+
+// IP gets the index of the next instruction to be run
+func (m *Machine) IP() int {
+	return m.ip
+}
+
+// SetIP sets the index of the next instruction to be run
+func (m *Machine) SetIP(ip int) {
+	m.ip = ip
+	if m.program.HasIPRegister() {
+		m.Registers[m.program.IPRegister] = ip
+	}
+}
+
+// NumSteps gets the number of instructions that have been run so far. A shortcut counts as a single instruction.
+func (m *Machine) NumSteps() int {
+	return m.numSteps
+}
+
+// NumInstructions gets the number of instructions the program has run so far, including every instruction that a shortcut
+// stood in for. This is the number of steps the program would have taken without shortcuts.
+func (m *Machine) NumInstructions() int {
+	return m.numInstructions
+}
+
+// Halted indicates whether the instruction pointer has left the program
+func (m *Machine) Halted() bool {
+	ip := m.IP()
+
+	return ip < 0 || ip >= len(m.program.Instructions)
+}
+
+// Step runs a single instruction, returning ErrHalted if there are no more to run
+func (m *Machine) Step() error {
+	_, err := m.step()
+
+	return err
+}
+
+// step runs a single instruction, returning the shortcut that was run in its place, if any
+func (m *Machine) step() (*Shortcut, error) {
+	if m.Halted() {
+		return nil, ErrHalted
+	}
+
+	ip := m.IP()
+	m.numSteps++
+	if m.program.HasIPRegister() {
+		m.Registers[m.program.IPRegister] = ip
+	}
+	if m.shortcuts != nil && m.shortcuts[ip] != nil {
+		if next, numInstructions, ok := m.shortcuts[ip].run(m.Registers); ok {
+			m.numInstructions += numInstructions
+			m.ip = next
+			// Leave the bound register as the last of the instructions the shortcut stands in for would have
+			if m.program.HasIPRegister() {
+				m.Registers[m.program.IPRegister] = next - 1
+			}
+			return m.shortcuts[ip], nil
+		}
+	}
+
+	m.numInstructions++
+	m.program.Instructions[ip].Apply(m.Registers)
+	if m.program.HasIPRegister() {
+		m.ip = m.Registers[m.program.IPRegister]
+	}
+	m.ip++
+
+	return nil, nil
+}
+
+// Run runs the program until it halts
+func (m *Machine) Run() {
+	for m.Step() == nil {
+	}
+}
@@ -0,0 +1,73 @@
+package elfcode
+
+import (
+	"strings"
+	"testing"
+)
+
+// day19Example binds the instruction pointer to register 0, and halts after running the instruction at index 6
+const day19Example = `#ip 0
+seti 5 0 1
+seti 6 0 2
+addi 0 1 0
+addr 1 2 3
+setr 1 0 0
+seti 8 0 4
+seti 9 0 5
+`
+
+func mustParseProgram(t *testing.T, source string) Program {
+	t.Helper()
+	program, err := ParseProgram(strings.Split(strings.TrimSuffix(source, "\n"), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return program
+}
+
+func mustMakeMachine(t *testing.T, program Program) *Machine {
+	t.Helper()
+	machine, err := NewMachine(program, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return machine
+}
+
+func TestRunLeavesIPRegisterOnLastInstruction(t *testing.T) {
+	machine := mustMakeMachine(t, mustParseProgram(t, day19Example))
+	machine.Run()
+
+	want := Registers{6, 5, 6, 0, 0, 9}
+	if !machine.Registers.Equal(want) {
+		t.Errorf("got registers %v, want %v", machine.Registers, want)
+	}
+	if machine.IP() != 7 {
+		t.Errorf("got ip %d, want 7", machine.IP())
+	}
+}
+
+func TestClone(t *testing.T) {
+	machine := mustMakeMachine(t, mustParseProgram(t, day19Example))
+	machine.Step()
+	machine.Step()
+
+	clone := machine.Clone()
+	if clone.IP() != machine.IP() || !clone.Registers.Equal(machine.Registers) || clone.NumSteps() != machine.NumSteps() {
+		t.Fatalf("clone is at ip %d with %v after %d steps, want ip %d with %v after %d steps",
+			clone.IP(), clone.Registers, clone.NumSteps(), machine.IP(), machine.Registers, machine.NumSteps())
+	}
+
+	clone.Run()
+	if machine.IP() != 2 || machine.Registers[1] != 5 || machine.Registers[3] != 0 {
+		t.Errorf("running the clone changed the original to ip %d with %v", machine.IP(), machine.Registers)
+	}
+
+	machine.Run()
+	if !clone.Registers.Equal(machine.Registers) || clone.NumSteps() != machine.NumSteps() {
+		t.Errorf("clone halted with %v after %d steps, want %v after %d steps",
+			clone.Registers, clone.NumSteps(), machine.Registers, machine.NumSteps())
+	}
+}
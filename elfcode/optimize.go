@@ -0,0 +1,372 @@
+package elfcode
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+type operandKind int
+
+const (
+	// anyOperand matches any value, and is used for operands that are ignored
+	anyOperand operandKind = iota
+	// registerVar binds a register. Differently named register variables must bind different registers.
+	registerVar
+	// valueVar binds an immediate value
+	valueVar
+	// literalOperand matches exactly one value
+	literalOperand
+	// ipOperand matches the register the instruction pointer is bound to
+	ipOperand
+	// jumpOperand matches a value that will make the instruction pointer land on the given offset into the idiom
+	jumpOperand
+)
+
+// patternOperand is a single operand of an instruction in an idiom
+type patternOperand struct {
+	kind  operandKind
+	name  string
+	value int
+}
+
+type patternInstruction struct {
+	op      Opcode
+	a, b, c patternOperand
+}
+
+// bindings maps the variables in an idiom to the registers or values they matched
+type bindings struct {
+	registers map[string]int
+	values    map[string]int
+}
+
+// idiom is a well known sequence of instructions that can be replaced with a native operation
+type idiom struct {
+	name    string
+	pattern []patternInstruction
+	// run performs the native operation on the registers, given the index the idiom starts at. It returns the index of the
+	// next instruction to run and the number of instructions the operation stands in for, or false if the operation can not
+	// be performed and the instructions must be interpreted.
+	run func(vars bindings, registers Registers, start int) (int, int, bool)
+}
+
+// Shortcut replaces a run of instructions in a program with a native operation that has the same effect on the registers
+type Shortcut struct {
+	Idiom string
+	// Start and End are the indices of the first instruction replaced, and the one after the last
+	Start int
+	End   int
+	// registers holds every register the idiom reads or writes
+	registers []int
+	run       func(registers Registers) (int, int, bool)
+}
+
+func (shortcut Shortcut) String() string {
+	return fmt.Sprintf("%s at %d-%d", shortcut.Idiom, shortcut.Start, shortcut.End-1)
+}
+
+func reg(name string) patternOperand {
+	return patternOperand{kind: registerVar, name: name}
+}
+
+func val(name string) patternOperand {
+	return patternOperand{kind: valueVar, name: name}
+}
+
+func lit(value int) patternOperand {
+	return patternOperand{kind: literalOperand, value: value}
+}
+
+func jumpTo(offset int) patternOperand {
+	return patternOperand{kind: jumpOperand, value: offset}
+}
+
+var (
+	ignored = patternOperand{kind: anyOperand}
+	ip      = patternOperand{kind: ipOperand}
+)
+
+// Can't use a constant for a slice - this is our next best thing
+var idioms = []idiom{
+	{
+		// for i := 1; i <= n; i++ { for j := 1; j <= n; j++ { if i * j == n { sum += i } } }
+		name: "divisor sum",
+		pattern: []patternInstruction{
+			{Seti, lit(1), ignored, reg("i")},
+			{Seti, lit(1), ignored, reg("j")},
+			{Mulr, reg("i"), reg("j"), reg("t")},
+			{Eqrr, reg("t"), reg("n"), reg("t")},
+			{Addr, reg("t"), ip, ip},
+			{Addi, ip, lit(1), ip},
+			{Addr, reg("i"), reg("sum"), reg("sum")},
+			{Addi, reg("j"), lit(1), reg("j")},
+			{Gtrr, reg("j"), reg("n"), reg("t")},
+			{Addr, ip, reg("t"), ip},
+			{Seti, jumpTo(2), ignored, ip},
+			{Addi, reg("i"), lit(1), reg("i")},
+			{Gtrr, reg("i"), reg("n"), reg("t")},
+			{Addr, reg("t"), ip, ip},
+			{Seti, jumpTo(1), ignored, ip},
+		},
+		run: func(vars bindings, registers Registers, start int) (int, int, bool) {
+			n := registers[vars.registers["n"]]
+			// Both loops always run at least once
+			last := n
+			if n < 1 {
+				last = 1
+			} else {
+				registers[vars.registers["sum"]] += divisorSum(n)
+			}
+			registers[vars.registers["i"]] = last + 1
+			registers[vars.registers["j"]] = last + 1
+			registers[vars.registers["t"]] = 1
+
+			// Each outer iteration runs the inner loop's 8 instructions per iteration, and 4 of its own.
+			// The last iteration of each loop skips its jump back.
+			return start + 15, last * (8*last + 4), true
+		},
+	},
+	{
+		// for q := 0; (q + 1) * k <= x; q++ {}
+		name: "divide",
+		pattern: []patternInstruction{
+			{Seti, lit(0), ignored, reg("q")},
+			{Addi, reg("q"), lit(1), reg("t")},
+			{Muli, reg("t"), val("k"), reg("t")},
+			{Gtrr, reg("t"), reg("x"), reg("t")},
+			{Addr, reg("t"), ip, ip},
+			{Addi, ip, lit(1), ip},
+			{Seti, val("exit"), ignored, ip},
+			{Addi, reg("q"), lit(1), reg("q")},
+			{Seti, jumpTo(1), ignored, ip},
+		},
+		run: func(vars bindings, registers Registers, _ int) (int, int, bool) {
+			k := vars.values["k"]
+			x := registers[vars.registers["x"]]
+			if k <= 0 {
+				return 0, 0, false
+			}
+
+			quotient := 0
+			if x >= 0 {
+				quotient = x / k
+			}
+			registers[vars.registers["q"]] = quotient
+			registers[vars.registers["t"]] = 1
+
+			// Every iteration that increments the quotient runs 7 instructions, and the one that exits runs 6
+			return vars.values["exit"] + 1, 6 + 7*quotient, true
+		},
+	},
+	{
+		// do { product += a; i++ } while (i <= n)
+		name: "multiply",
+		pattern: []patternInstruction{
+			{Addr, reg("a"), reg("product"), reg("product")},
+			{Addi, reg("i"), lit(1), reg("i")},
+			{Gtrr, reg("i"), reg("n"), reg("t")},
+			{Addr, reg("t"), ip, ip},
+			{Seti, jumpTo(0), ignored, ip},
+		},
+		run: func(vars bindings, registers Registers, start int) (int, int, bool) {
+			i := registers[vars.registers["i"]]
+			n := registers[vars.registers["n"]]
+			iterations := n - i + 1
+			// The body always runs at least once
+			if iterations < 1 {
+				iterations = 1
+			}
+
+			registers[vars.registers["product"]] += registers[vars.registers["a"]] * iterations
+			registers[vars.registers["i"]] = i + iterations
+			registers[vars.registers["t"]] = 1
+
+			// The last iteration skips the jump back
+			return start + 5, 5*iterations - 1, true
+		},
+	},
+}
+
+// FindShortcuts finds every known idiom in the program, so that it can be replaced with a native operation.
+// Only programs with an instruction pointer register have loops, so other programs never have shortcuts.
+func FindShortcuts(program Program) []Shortcut {
+	if !program.HasIPRegister() {
+		return nil
+	}
+
+	shortcuts := []Shortcut{}
+	for start := range program.Instructions {
+		for _, candidate := range idioms {
+			vars, ok := candidate.match(program, start)
+			if !ok {
+				continue
+			}
+
+			run := candidate.run
+			regionStart := start
+			registers := make([]int, 0, len(vars.registers))
+			for _, register := range vars.registers {
+				registers = append(registers, register)
+			}
+			sort.Ints(registers)
+
+			shortcuts = append(shortcuts, Shortcut{
+				Idiom:     candidate.name,
+				Start:     start,
+				End:       start + len(candidate.pattern),
+				registers: registers,
+				run: func(registers Registers) (int, int, bool) {
+					return run(vars, registers, regionStart)
+				},
+			})
+		}
+	}
+
+	sort.Slice(shortcuts, func(i, j int) bool {
+		return shortcuts[i].Start < shortcuts[j].Start
+	})
+
+	return shortcuts
+}
+
+// Optimize finds every shortcut in the machine's program and installs them, so that the native operation is run
+// whenever the machine reaches the start of one. The installed shortcuts are returned.
+func (m *Machine) Optimize() []Shortcut {
+	shortcuts := FindShortcuts(m.program)
+	for _, shortcut := range shortcuts {
+		m.AddShortcut(shortcut)
+	}
+
+	return shortcuts
+}
+
+// AddShortcut installs a single shortcut. If one is already installed at the same instruction, it is replaced.
+func (m *Machine) AddShortcut(shortcut Shortcut) {
+	if m.shortcuts == nil {
+		m.shortcuts = make([]*Shortcut, len(m.program.Instructions))
+	}
+
+	m.shortcuts[shortcut.Start] = &shortcut
+}
+
+// match checks if the idiom appears in the program at the given index, returning the bound variables if so
+func (candidate idiom) match(program Program, start int) (bindings, bool) {
+	if start+len(candidate.pattern) > len(program.Instructions) {
+		return bindings{}, false
+	}
+
+	vars := bindings{registers: map[string]int{}, values: map[string]int{}}
+	for i, patternIns := range candidate.pattern {
+		ins := program.Instructions[start+i]
+		if ins.Op != patternIns.op {
+			return bindings{}, false
+		}
+
+		matchOperands := func(a patternOperand, b patternOperand) bool {
+			attempt := vars.clone()
+			ok := attempt.bind(a, ins.A, program.IPRegister, start) &&
+				attempt.bind(b, ins.B, program.IPRegister, start) &&
+				attempt.bind(patternIns.c, ins.C, program.IPRegister, start)
+			if ok {
+				vars = attempt
+			}
+
+			return ok
+		}
+
+		// Commutative operations may have their operands written in either order
+		if !matchOperands(patternIns.a, patternIns.b) && !(ins.Op.isCommutative() && matchOperands(patternIns.b, patternIns.a)) {
+			return bindings{}, false
+		}
+	}
+
+	return vars, true
+}
+
+// bind attempts to match a single operand, recording any variable it binds
+func (vars bindings) bind(operand patternOperand, value int, ipRegister int, start int) bool {
+	switch operand.kind {
+	case anyOperand:
+		return true
+	case literalOperand:
+		return value == operand.value
+	case ipOperand:
+		return value == ipRegister
+	case jumpOperand:
+		// The instruction pointer is incremented after the jump
+		return value+1 == start+operand.value
+	case valueVar:
+		bound, ok := vars.values[operand.name]
+		if !ok {
+			vars.values[operand.name] = value
+		}
+
+		return !ok || bound == value
+	case registerVar:
+		if value == ipRegister {
+			return false
+		} else if bound, ok := vars.registers[operand.name]; ok {
+			return bound == value
+		}
+
+		// Another variable must not have bound this register already
+		for _, bound := range vars.registers {
+			if bound == value {
+				return false
+			}
+		}
+		vars.registers[operand.name] = value
+
+		return true
+	default:
+		return false
+	}
+}
+
+func (vars bindings) clone() bindings {
+	cloned := bindings{
+		registers: make(map[string]int, len(vars.registers)),
+		values:    make(map[string]int, len(vars.values)),
+	}
+	for name, register := range vars.registers {
+		cloned.registers[name] = register
+	}
+	for name, value := range vars.values {
+		cloned.values[name] = value
+	}
+
+	return cloned
+}
+
+func (op Opcode) isCommutative() bool {
+	switch op {
+	case Addr, Mulr, Banr, Borr, Eqrr:
+		return true
+	default:
+		return false
+	}
+}
+
+// divisorSum finds the sum of all of the divisors of a positive number
+func divisorSum(num int) int {
+	// Find the sqrt of the number. math.Sqrt uses float64, which is imprecise for big numbers.
+	bigSqrt := big.NewInt(0)
+	bigSqrt.Sqrt(big.NewInt(int64(num)))
+	sqrt := int(bigSqrt.Int64())
+
+	sum := 0
+	for candidate := 1; candidate <= sqrt; candidate++ {
+		if num%candidate != 0 {
+			continue
+		}
+
+		sum += candidate
+		// Don't count the square root of a perfect square twice
+		if pair := num / candidate; pair != candidate {
+			sum += pair
+		}
+	}
+
+	return sum
+}
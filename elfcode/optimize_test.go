@@ -0,0 +1,110 @@
+package elfcode
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Each of these programs sets up some registers and then runs a single idiom, halting once it is done
+const (
+	// divisorSumProgram sums the divisors of n into register 0
+	divisorSumProgram = `#ip 5
+seti %d 0 4
+seti 1 0 1
+seti 1 0 2
+mulr 1 2 3
+eqrr 3 4 3
+addr 3 5 5
+addi 5 1 5
+addr 1 0 0
+addi 2 1 2
+gtrr 2 4 3
+addr 5 3 5
+seti 2 0 5
+addi 1 1 1
+gtrr 1 4 3
+addr 3 5 5
+seti 1 0 5
+`
+	// divideProgram divides x by k into register 1
+	divideProgram = `#ip 5
+seti %d 0 4
+seti 0 0 1
+addi 1 1 2
+muli 2 %d 2
+gtrr 2 4 2
+addr 2 5 5
+addi 5 1 5
+seti 9 0 5
+addi 1 1 1
+seti 1 0 5
+`
+	// multiplyProgram adds a to register 0 once for each of i through n
+	multiplyProgram = `#ip 5
+seti %d 0 1
+seti %d 0 2
+seti %d 0 3
+addr 1 0 0
+addi 2 1 2
+gtrr 2 3 4
+addr 4 5 5
+seti 2 0 5
+`
+)
+
+func TestShortcutsMatchInterpreter(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		idiom  string
+	}{
+		{"divisor sum", fmt.Sprintf(divisorSumProgram, 10), "divisor sum"},
+		{"divisor sum of a perfect square", fmt.Sprintf(divisorSumProgram, 36), "divisor sum"},
+		{"divisor sum of one", fmt.Sprintf(divisorSumProgram, 1), "divisor sum"},
+		{"divisor sum of zero", fmt.Sprintf(divisorSumProgram, 0), "divisor sum"},
+		{"divide", fmt.Sprintf(divideProgram, 20, 3), "divide"},
+		{"divide exactly", fmt.Sprintf(divideProgram, 21, 3), "divide"},
+		{"divide zero", fmt.Sprintf(divideProgram, 0, 3), "divide"},
+		{"divide negative", fmt.Sprintf(divideProgram, -5, 3), "divide"},
+		// The shortcut can't divide by zero, so the instructions are interpreted instead
+		{"divide by zero", fmt.Sprintf(divideProgram, -1, 0), "divide"},
+		{"multiply", fmt.Sprintf(multiplyProgram, 7, 1, 10), "multiply"},
+		{"multiply once", fmt.Sprintf(multiplyProgram, 7, 5, 2), "multiply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := mustParseProgram(t, tt.source)
+			plain := mustMakeMachine(t, program)
+			plain.Run()
+
+			optimized := mustMakeMachine(t, program)
+			shortcuts := optimized.Optimize()
+			if len(shortcuts) != 1 || shortcuts[0].Idiom != tt.idiom {
+				t.Fatalf("got shortcuts %v, want a single %s", shortcuts, tt.idiom)
+			}
+			optimized.Run()
+
+			if !optimized.Registers.Equal(plain.Registers) {
+				t.Errorf("got registers %v, want %v", optimized.Registers, plain.Registers)
+			}
+			if optimized.NumInstructions() != plain.NumInstructions() {
+				t.Errorf("got %d instructions, want %d", optimized.NumInstructions(), plain.NumInstructions())
+			}
+		})
+	}
+}
+
+func TestShortcutsSkipInstructions(t *testing.T) {
+	machine := mustMakeMachine(t, mustParseProgram(t, fmt.Sprintf(divisorSumProgram, 10)))
+	machine.Optimize()
+	machine.Run()
+
+	// The setup, and the shortcut in place of the whole idiom
+	if machine.NumSteps() != 2 {
+		t.Errorf("got %d steps, want 2", machine.NumSteps())
+	}
+	if machine.Registers[0] != 18 {
+		t.Errorf("got a divisor sum of %d, want 18", machine.Registers[0])
+	}
+}
@@ -0,0 +1,308 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxHaltingTail bounds how many instructions may be run after a halt check to see if the program halts
+const maxHaltingTail = 1000
+
+// numTableHaltingValues is how many halting values are shown at each end of the list in a profile's table
+const numTableHaltingValues = 5
+
+// ErrNoHaltCheck is returned when a program has no instruction that compares a register against register 0
+var ErrNoHaltCheck = errors.New("no eqrr instruction compares against register 0")
+
+// HaltCheck is an "eqrr" instruction that compares a register against register 0, like the one that decides when day 21's program halts
+type HaltCheck struct {
+	// Index is the index of the instruction that performs the check
+	Index int
+	// Register is the register that is compared against register 0
+	Register int
+}
+
+// Profiler runs a machine while recording how often each part of its program is run
+type Profiler struct {
+	machine      *Machine
+	cfg          *ControlFlowGraph
+	hits         []int
+	shortcutRuns []int
+	writes       []int
+	// before holds the registers before a shortcut is run, so that the registers it writes can be found
+	before         Registers
+	haltCheck      HaltCheck
+	hasHaltCheck   bool
+	haltingValues  []HaltingValue
+	seenValues     map[int]bool
+	seenCheckState map[string]bool
+	repeated       bool
+}
+
+// Profile is the result of profiling a program
+type Profile struct {
+	// Steps is the number of instructions that were run. A shortcut counts as a single instruction.
+	Steps int `json:"steps"`
+	// Halted indicates that the program ran to completion
+	Halted bool `json:"halted"`
+	// Repeated indicates that profiling stopped because the machine reached the halt check in a state it had been in before,
+	// meaning it would never halt
+	Repeated       bool                 `json:"repeated"`
+	Instructions   []InstructionProfile `json:"instructions"`
+	Loops          []LoopProfile        `json:"loops"`
+	RegisterWrites []int                `json:"registerWrites"`
+	// HaltingValues holds every distinct value compared against register 0 by the halt check, in the order they were first seen
+	HaltingValues []HaltingValue `json:"haltingValues,omitempty"`
+}
+
+// InstructionProfile records how often a single instruction was run
+type InstructionProfile struct {
+	Index       int    `json:"index"`
+	Instruction string `json:"instruction"`
+	Hits        int    `json:"hits"`
+	// ShortcutRuns is how many of the hits ran a shortcut starting at this instruction instead of the instruction itself
+	ShortcutRuns int `json:"shortcutRuns,omitempty"`
+}
+
+// LoopProfile records how often a loop was run
+type LoopProfile struct {
+	Header int `json:"header"`
+	// Blocks holds the start of every block in the loop
+	Blocks []int `json:"blocks"`
+	// Iterations is the number of times the loop's header was run
+	Iterations int `json:"iterations"`
+	// Instructions is the number of instructions run inside the loop, including any loops nested within it
+	Instructions int `json:"instructions"`
+}
+
+// HaltingValue is a value that would make the program halt if register 0 held it
+type HaltingValue struct {
+	Value int `json:"value"`
+	// Instructions is the number of instructions that would be run before halting
+	Instructions int `json:"instructions"`
+}
+
+// FindHaltCheck finds the first "eqrr" instruction that compares a register to register 0
+func FindHaltCheck(program Program) (HaltCheck, error) {
+	for i, ins := range program.Instructions {
+		if ins.Op != Eqrr {
+			continue
+		} else if ins.A == 0 && ins.B != 0 {
+			return HaltCheck{Index: i, Register: ins.B}, nil
+		} else if ins.B == 0 && ins.A != 0 {
+			return HaltCheck{Index: i, Register: ins.A}, nil
+		}
+	}
+
+	return HaltCheck{}, ErrNoHaltCheck
+}
+
+// NewProfiler makes a profiler for the given machine. If the program has a halt check, the values it compares against
+// register 0 are recorded, and profiling stops once the machine reaches it in a state it has already been in.
+func NewProfiler(machine *Machine) *Profiler {
+	numInstructions := len(machine.program.Instructions)
+	profiler := &Profiler{
+		machine:        machine,
+		cfg:            NewControlFlowGraph(machine.program),
+		hits:           make([]int, numInstructions),
+		shortcutRuns:   make([]int, numInstructions),
+		writes:         make([]int, len(machine.Registers)),
+		before:         NewRegisters(len(machine.Registers)),
+		seenValues:     map[int]bool{},
+		seenCheckState: map[string]bool{},
+	}
+
+	check, err := FindHaltCheck(machine.program)
+	if err == nil && machine.program.HasIPRegister() {
+		profiler.haltCheck = check
+		profiler.hasHaltCheck = true
+	}
+
+	return profiler
+}
+
+// Step runs a single instruction on the machine and records it, returning ErrHalted if there are no more to run
+func (profiler *Profiler) Step() error {
+	machine := profiler.machine
+	if machine.Halted() {
+		return ErrHalted
+	}
+
+	ip := machine.IP()
+	if profiler.hasHaltCheck && ip == profiler.haltCheck.Index {
+		profiler.recordHaltCheck()
+	}
+
+	copy(profiler.before, machine.Registers)
+	shortcut, err := machine.step()
+	if err != nil {
+		return err
+	}
+
+	profiler.hits[ip]++
+	if shortcut == nil {
+		profiler.writes[machine.program.Instructions[ip].C]++
+		return nil
+	}
+
+	profiler.shortcutRuns[ip]++
+	for register, value := range machine.Registers {
+		if register != machine.program.IPRegister && value != profiler.before[register] {
+			profiler.writes[register]++
+		}
+	}
+
+	return nil
+}
+
+// Run runs the machine until it halts, repeats a state at the halt check, or has run maxSteps instructions.
+// A maxSteps of zero or less runs with no limit.
+func (profiler *Profiler) Run(maxSteps int) {
+	for steps := 0; maxSteps <= 0 || steps < maxSteps; steps++ {
+		if profiler.repeated || profiler.Step() != nil {
+			return
+		}
+	}
+}
+
+// recordHaltCheck records the value about to be compared against register 0, and how many instructions it would take to halt
+func (profiler *Profiler) recordHaltCheck() {
+	machine := profiler.machine
+	state := fmt.Sprint(machine.Registers)
+	if profiler.seenCheckState[state] {
+		profiler.repeated = true
+		return
+	}
+	profiler.seenCheckState[state] = true
+
+	value := machine.Registers[profiler.haltCheck.Register]
+	if profiler.seenValues[value] {
+		return
+	}
+	profiler.seenValues[value] = true
+
+	tail, halts := profiler.haltingTail(value)
+	if halts {
+		profiler.haltingValues = append(profiler.haltingValues, HaltingValue{
+			Value:        value,
+			Instructions: machine.NumInstructions() + tail,
+		})
+	}
+}
+
+// haltingTail finds how many instructions are run from the halt check until the program halts, if register 0 holds
+// the given value. It returns false if the program does not halt soon after.
+func (profiler *Profiler) haltingTail(value int) (int, bool) {
+	machine := profiler.machine
+	tailMachine := machine.Clone()
+	tailMachine.Registers[0] = value
+	for steps := 0; !tailMachine.Halted(); steps++ {
+		if steps == maxHaltingTail {
+			return 0, false
+		}
+
+		tailMachine.Step()
+	}
+
+	return tailMachine.NumInstructions() - machine.NumInstructions(), true
+}
+
+// Profile gets everything that has been recorded so far
+func (profiler *Profiler) Profile() Profile {
+	profile := Profile{
+		Steps:          profiler.machine.NumSteps(),
+		Halted:         profiler.machine.Halted(),
+		Repeated:       profiler.repeated,
+		Instructions:   make([]InstructionProfile, len(profiler.hits)),
+		Loops:          make([]LoopProfile, len(profiler.cfg.Loops)),
+		RegisterWrites: append([]int{}, profiler.writes...),
+		HaltingValues:  append([]HaltingValue{}, profiler.haltingValues...),
+	}
+
+	for i, hits := range profiler.hits {
+		profile.Instructions[i] = InstructionProfile{
+			Index:        i,
+			Instruction:  profiler.machine.program.Instructions[i].String(),
+			Hits:         hits,
+			ShortcutRuns: profiler.shortcutRuns[i],
+		}
+	}
+
+	for i, loop := range profiler.cfg.Loops {
+		loopProfile := LoopProfile{Header: loop.Header, Blocks: loop.Blocks, Iterations: profiler.hits[loop.Header]}
+		for _, blockStart := range loop.Blocks {
+			block, _ := profiler.cfg.Block(blockStart)
+			for instructionIndex := block.Start; instructionIndex < block.End; instructionIndex++ {
+				loopProfile.Instructions += profiler.hits[instructionIndex]
+			}
+		}
+		profile.Loops[i] = loopProfile
+	}
+
+	// The hottest loops are the ones worth optimizing, so they go first
+	sort.SliceStable(profile.Loops, func(i, j int) bool {
+		return profile.Loops[i].Instructions > profile.Loops[j].Instructions
+	})
+
+	return profile
+}
+
+// WriteTable writes the profile as a set of human readable tables
+func (profile Profile) WriteTable(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.AlignRight)
+	outcome := "stopped"
+	if profile.Halted {
+		outcome = "halted"
+	} else if profile.Repeated {
+		outcome = "repeated a state at the halt check"
+	}
+	fmt.Fprintf(table, "%d instructions run, %s\n\n", profile.Steps, outcome)
+
+	fmt.Fprintln(table, "index\tinstruction\thits\t%\tshortcut runs\t")
+	for _, ins := range profile.Instructions {
+		fmt.Fprintf(table, "%d\t%s\t%d\t%s\t%d\t\n", ins.Index, ins.Instruction, ins.Hits, percentage(ins.Hits, profile.Steps), ins.ShortcutRuns)
+	}
+
+	fmt.Fprintln(table, "\nloop\tblocks\titerations\tinstructions\t%\t")
+	for _, loop := range profile.Loops {
+		blocks := make([]string, len(loop.Blocks))
+		for i, blockStart := range loop.Blocks {
+			blocks[i] = fmt.Sprintf("L%d", blockStart)
+		}
+		fmt.Fprintf(table, "L%d\t%s\t%d\t%d\t%s\t\n",
+			loop.Header, strings.Join(blocks, ","), loop.Iterations, loop.Instructions, percentage(loop.Instructions, profile.Steps))
+	}
+
+	fmt.Fprintln(table, "\nregister\twrites\t%\t")
+	for register, writes := range profile.RegisterWrites {
+		fmt.Fprintf(table, "r%d\t%d\t%s\t\n", register, writes, percentage(writes, profile.Steps))
+	}
+
+	if len(profile.HaltingValues) > 0 {
+		fmt.Fprintf(table, "\n%d halting values\n", len(profile.HaltingValues))
+		fmt.Fprintln(table, "#\tvalue\tinstructions\t")
+		for i, haltingValue := range profile.HaltingValues {
+			if i == numTableHaltingValues && len(profile.HaltingValues) > 2*numTableHaltingValues {
+				fmt.Fprintln(table, "...\t\t\t")
+			}
+			if i >= numTableHaltingValues && i < len(profile.HaltingValues)-numTableHaltingValues {
+				continue
+			}
+			fmt.Fprintf(table, "%d\t%d\t%d\t\n", i+1, haltingValue.Value, haltingValue.Instructions)
+		}
+	}
+
+	return table.Flush()
+}
+
+func percentage(count int, total int) string {
+	if total == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.2f", 100*float64(count)/float64(total))
+}
@@ -0,0 +1,106 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// day21Example halts once register 1, counting up by 3 and kept to three bits, equals register 0.
+// It compares the values 3, 6, 1, 4, 7, 2, 5, 0 against register 0 before repeating.
+const day21Example = `#ip 4
+seti 0 0 1
+addi 1 3 1
+bani 1 7 1
+eqrr 1 0 3
+addr 3 4 4
+seti 0 0 4
+`
+
+func TestProfilerHaltingValues(t *testing.T) {
+	machine := mustMakeMachine(t, mustParseProgram(t, day21Example))
+	// No value of the counter equals 8, so the program never halts
+	machine.Registers[0] = 8
+	profiler := NewProfiler(machine)
+	profiler.Run(0)
+	profile := profiler.Profile()
+
+	if !profile.Repeated {
+		t.Error("expected the profiler to stop on a repeated state")
+	}
+
+	// The check is first reached after three instructions, and again every five after that. Halting takes two more:
+	// the check itself, and the jump past the end of the program.
+	var want []HaltingValue
+	for i, value := range []int{3, 6, 1, 4, 7, 2, 5, 0} {
+		want = append(want, HaltingValue{Value: value, Instructions: 3 + 5*i + 2})
+	}
+	if !reflect.DeepEqual(profile.HaltingValues, want) {
+		t.Errorf("got halting values %v, want %v", profile.HaltingValues, want)
+	}
+}
+
+func TestFindHaltCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    HaltCheck
+		wantErr error
+	}{
+		{"register first", "seti 1 0 1\neqrr 3 0 2\n", HaltCheck{Index: 1, Register: 3}, nil},
+		{"register 0 first", "eqrr 0 4 2\n", HaltCheck{Index: 0, Register: 4}, nil},
+		{"only the first check", "eqrr 0 4 2\neqrr 1 0 2\n", HaltCheck{Index: 0, Register: 4}, nil},
+		// Comparing register 0 against itself, or an immediate against it, can't be the check
+		{"register 0 against itself", "eqrr 0 0 2\neqri 0 5 2\neqrr 1 0 2\n", HaltCheck{Index: 2, Register: 1}, nil},
+		{"no check", "eqri 0 5 2\n", HaltCheck{}, ErrNoHaltCheck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindHaltCheck(mustParseProgram(t, tt.source))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			} else if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileDay21(t *testing.T) {
+	rawProgram, err := os.ReadFile("testdata/day21.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// This is what running elfprof -optimize on the program does
+	machine := mustMakeMachine(t, mustParseProgram(t, string(rawProgram)))
+	machine.Optimize()
+	profiler := NewProfiler(machine)
+	profiler.Run(0)
+	profile := profiler.Profile()
+
+	if !profile.Repeated {
+		t.Error("expected the profiler to stop on a repeated state")
+	}
+
+	// The first and last values are the answers to both parts of day 21
+	haltingValues := profile.HaltingValues
+	if len(haltingValues) == 0 {
+		t.Fatal("found no halting values")
+	} else if first := haltingValues[0]; first != (HaltingValue{Value: 3115806, Instructions: 1848}) {
+		t.Errorf("got first halting value %+v, want 3115806 after 1848 instructions", first)
+	} else if last := haltingValues[len(haltingValues)-1]; last.Value != 13959373 {
+		t.Errorf("got last halting value %d, want 13959373", last.Value)
+	}
+
+	table := &strings.Builder{}
+	if err := profile.WriteTable(table); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(table.String(), fmt.Sprintf("%d halting values", len(haltingValues))) {
+		t.Errorf("table does not list the halting values:\n%s", table)
+	}
+}
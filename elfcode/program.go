@@ -0,0 +1,81 @@
+package elfcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NoIPRegister indicates that a program's instruction pointer is not bound to any register
+const NoIPRegister = -1
+
+const ipFormat = "#ip %d"
+
+// Program is a list of instructions, along with the register the instruction pointer is bound to (if any)
+type Program struct {
+	IPRegister   int
+	Instructions []Instruction
+}
+
+// ParseProgram parses a program, with an optional leading "#ip N" directive, followed by one instruction per line.
+// Any error is an AssemblyError, giving the line that could not be parsed.
+func ParseProgram(rawProgram []string) (Program, error) {
+	program := Program{IPRegister: NoIPRegister}
+	firstInstruction := 0
+	if len(rawProgram) > 0 && strings.HasPrefix(rawProgram[0], "#ip") {
+		numMatched, err := fmt.Sscanf(rawProgram[0], ipFormat, &program.IPRegister)
+		if err != nil {
+			return Program{}, AssemblyError{Line: 1, Text: rawProgram[0], Err: err}
+		} else if numMatched != 1 {
+			return Program{}, AssemblyError{Line: 1, Text: rawProgram[0], Err: ErrMalformedInput}
+		}
+		firstInstruction = 1
+	}
+
+	program.Instructions = make([]Instruction, len(rawProgram)-firstInstruction)
+	for i, rawInstruction := range rawProgram[firstInstruction:] {
+		ins, err := ParseInstruction(rawInstruction)
+		if err != nil {
+			return Program{}, AssemblyError{Line: firstInstruction + i + 1, Text: rawInstruction, Err: err}
+		}
+
+		program.Instructions[i] = ins
+	}
+
+	return program, nil
+}
+
+// HasIPRegister indicates whether the instruction pointer is bound to a register
+func (program Program) HasIPRegister() bool {
+	return program.IPRegister != NoIPRegister
+}
+
+// Validate checks that every register the program refers to exists on a device with the given number of registers
+func (program Program) Validate(numRegisters int) error {
+	if program.HasIPRegister() && (program.IPRegister < 0 || program.IPRegister >= numRegisters) {
+		return fmt.Errorf("#ip %d: %w", program.IPRegister, ErrInvalidRegister)
+	}
+
+	for i, ins := range program.Instructions {
+		err := ins.Validate(numRegisters)
+		if err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// String produces the program in the same format ParseProgram accepts
+func (program Program) String() string {
+	builder := strings.Builder{}
+	if program.HasIPRegister() {
+		fmt.Fprintf(&builder, ipFormat+"\n", program.IPRegister)
+	}
+
+	for _, ins := range program.Instructions {
+		builder.WriteString(ins.String())
+		builder.WriteByte('\n')
+	}
+
+	return builder.String()
+}
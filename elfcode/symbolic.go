@@ -0,0 +1,581 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Relation is the way register 0 is compared against a value in a constraint
+type Relation int
+
+// The relations that comparisons can be reduced to
+const (
+	RelationEqual Relation = iota
+	RelationGreater
+	RelationLess
+)
+
+// maxConditionExclusions is how many excluded values are listed when rendering a condition
+const maxConditionExclusions = 3
+
+// ErrUnsupportedSymbolic is returned when a program uses register 0 in a way that symbolic execution can not follow
+var ErrUnsupportedSymbolic = errors.New("register 0 is used in a way that can not be executed symbolically")
+
+var relationSymbols = map[Relation]string{
+	RelationEqual:   "==",
+	RelationGreater: ">",
+	RelationLess:    "<",
+}
+
+var negatedRelationSymbols = map[Relation]string{
+	RelationEqual:   "!=",
+	RelationGreater: "<=",
+	RelationLess:    ">=",
+}
+
+type symbolKind int
+
+const (
+	// knownSymbol holds value
+	knownSymbol symbolKind = iota
+	// register0Symbol holds the initial value of register 0, plus value
+	register0Symbol
+	// conditionSymbol holds ifTrue if register 0 has relation to value, and ifFalse otherwise
+	conditionSymbol
+)
+
+// symbol is the contents of a register during symbolic execution, which may depend on the initial value of register 0
+type symbol struct {
+	kind     symbolKind
+	value    int
+	relation Relation
+	ifTrue   int
+	ifFalse  int
+}
+
+// Constraint is a single comparison of the initial value of register 0 that a path depends on
+type Constraint struct {
+	Relation Relation
+	Value    int
+	// Holds indicates whether the path requires the comparison to be true or false
+	Holds bool
+}
+
+// Condition is the conjunction of every constraint a path depends on
+type Condition struct {
+	node *conditionNode
+}
+
+// conditionNode is a constraint, linked to the constraints added before it, so that paths can share their common prefix
+type conditionNode struct {
+	constraint Constraint
+	parent     *conditionNode
+	depth      int
+	// lo and hi bound the initial value of register 0, inclusively
+	lo int
+	hi int
+	// pinned indicates that only a single value, lo, is possible
+	pinned bool
+	// state is a hash of the registers at the branch that added this constraint
+	state uint64
+}
+
+// HaltingPath is a way through the program that halts, taken when register 0 starts with a value that meets its condition
+type HaltingPath struct {
+	Condition    Condition
+	Instructions int
+}
+
+// SymbolicResult holds every halting path found by symbolic execution
+type SymbolicResult struct {
+	// HaltingPaths is sorted by the number of instructions run
+	HaltingPaths []HaltingPath
+	// Repeated is the number of paths that were abandoned because they reached a branch in a state they had been in before,
+	// meaning they never halt
+	Repeated int
+	// Truncated indicates that the step limit was reached before every path was explored
+	Truncated bool
+}
+
+// symbolicPath is a single path being explored
+type symbolicPath struct {
+	registers       []symbol
+	ip              int
+	condition       *conditionNode
+	numInstructions int
+}
+
+func (relation Relation) String() string {
+	return relationSymbols[relation]
+}
+
+func (constraint Constraint) String() string {
+	symbol := relationSymbols[constraint.Relation]
+	if !constraint.Holds {
+		symbol = negatedRelationSymbols[constraint.Relation]
+	}
+
+	return fmt.Sprintf("r0 %s %d", symbol, constraint.Value)
+}
+
+func known(value int) symbol {
+	return symbol{kind: knownSymbol, value: value}
+}
+
+// ExecuteSymbolic runs the program with an unknown value in register 0, and every other register zeroed. Whenever a jump depends
+// on register 0, both outcomes are explored, and every path that halts is returned with the condition on register 0 that leads
+// to it. Paths that reach a branch in a state they have already been in are abandoned, as they will never halt.
+// Shortcuts are used where register 0 is not involved, but instruction counts are as if the program was interpreted.
+// At most maxSteps instructions are run across all paths, or no limit if it is zero or less.
+func ExecuteSymbolic(program Program, numRegisters int, maxSteps int) (SymbolicResult, error) {
+	err := program.Validate(numRegisters)
+	if err != nil {
+		return SymbolicResult{}, err
+	} else if program.IPRegister == 0 {
+		return SymbolicResult{}, fmt.Errorf("register 0 is the instruction pointer: %w", ErrUnsupportedSymbolic)
+	}
+
+	shortcuts := make([]*Shortcut, len(program.Instructions))
+	for _, shortcut := range FindShortcuts(program) {
+		shortcut := shortcut
+		shortcuts[shortcut.Start] = &shortcut
+	}
+
+	start := symbolicPath{registers: make([]symbol, numRegisters)}
+	for i := range start.registers {
+		start.registers[i] = known(0)
+	}
+	start.registers[0] = symbol{kind: register0Symbol}
+
+	result := SymbolicResult{}
+	pending := []symbolicPath{start}
+	numSteps := 0
+	for len(pending) > 0 {
+		path := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		var branches []symbolicPath
+		for branches == nil && path.ip >= 0 && path.ip < len(program.Instructions) {
+			if maxSteps > 0 && numSteps >= maxSteps {
+				result.Truncated = true
+				sortHaltingPaths(result.HaltingPaths)
+
+				return result, nil
+			}
+
+			numSteps++
+			branches, err = path.step(program, shortcuts[path.ip])
+			if err != nil {
+				return SymbolicResult{}, fmt.Errorf("instruction %d (%s): %w", path.ip, program.Instructions[path.ip], err)
+			}
+		}
+
+		if branches == nil {
+			result.HaltingPaths = append(result.HaltingPaths, HaltingPath{
+				Condition:    Condition{node: path.condition},
+				Instructions: path.numInstructions,
+			})
+			continue
+		}
+
+		for _, branch := range branches {
+			if branch.condition.repeatsState() {
+				result.Repeated++
+			} else {
+				pending = append(pending, branch)
+			}
+		}
+	}
+
+	sortHaltingPaths(result.HaltingPaths)
+
+	return result, nil
+}
+
+// HaltingValues gets the halting paths that are only taken for a single value of register 0, in the order they halt.
+// This is every halting path for a program like day 21's, which only compares register 0 for equality.
+func (result SymbolicResult) HaltingValues() []HaltingValue {
+	haltingValues := []HaltingValue{}
+	for _, path := range result.HaltingPaths {
+		if value, ok := path.Condition.Value(); ok {
+			haltingValues = append(haltingValues, HaltingValue{Value: value, Instructions: path.Instructions})
+		}
+	}
+
+	return haltingValues
+}
+
+// step runs a single instruction. If a jump depends on register 0, the path is split, and the possible branches are returned.
+func (path *symbolicPath) step(program Program, shortcut *Shortcut) ([]symbolicPath, error) {
+	ipRegister := program.IPRegister
+	if program.HasIPRegister() {
+		path.registers[ipRegister] = known(path.ip)
+	}
+
+	if shortcut != nil && path.runShortcut(shortcut) {
+		return nil, nil
+	}
+
+	ins := program.Instructions[path.ip]
+	result, err := ins.applySymbolic(path.registers)
+	if err != nil {
+		return nil, err
+	}
+	path.registers[ins.C] = result
+	path.numInstructions++
+
+	if !program.HasIPRegister() {
+		path.ip++
+		return nil, nil
+	}
+
+	next := path.registers[ipRegister]
+	switch next.kind {
+	case knownSymbol:
+		path.ip = next.value + 1
+		return nil, nil
+	case register0Symbol:
+		return nil, fmt.Errorf("jump by the value of register 0: %w", ErrUnsupportedSymbolic)
+	}
+
+	state := path.hash()
+	branches := []symbolicPath{}
+	for _, holds := range []bool{false, true} {
+		constraint := Constraint{Relation: next.relation, Value: next.value, Holds: holds}
+		condition, ok := path.condition.with(constraint, state)
+		if !ok {
+			continue
+		}
+
+		branch := symbolicPath{
+			registers:       make([]symbol, len(path.registers)),
+			condition:       condition,
+			numInstructions: path.numInstructions,
+		}
+		copy(branch.registers, path.registers)
+		branch.resolve(constraint)
+		branch.ip = branch.registers[ipRegister].value + 1
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+// runShortcut runs the shortcut if none of its registers depend on register 0, returning whether it was run
+func (path *symbolicPath) runShortcut(shortcut *Shortcut) bool {
+	concrete := NewRegisters(len(path.registers))
+	for register, value := range path.registers {
+		if value.kind == knownSymbol {
+			concrete[register] = value.value
+		}
+	}
+	for _, register := range shortcut.registers {
+		if path.registers[register].kind != knownSymbol {
+			return false
+		}
+	}
+
+	next, numInstructions, ok := shortcut.run(concrete)
+	if !ok {
+		return false
+	}
+
+	for _, register := range shortcut.registers {
+		path.registers[register] = known(concrete[register])
+	}
+	path.ip = next
+	path.numInstructions += numInstructions
+
+	return true
+}
+
+// resolve replaces every register that depends on the outcome of the constraint's comparison with that outcome
+func (path *symbolicPath) resolve(constraint Constraint) {
+	for register, value := range path.registers {
+		if value.kind != conditionSymbol || value.relation != constraint.Relation || value.value != constraint.Value {
+			continue
+		}
+
+		if constraint.Holds {
+			path.registers[register] = known(value.ifTrue)
+		} else {
+			path.registers[register] = known(value.ifFalse)
+		}
+	}
+}
+
+func (path *symbolicPath) hash() uint64 {
+	hash := fnv.New64a()
+	fmt.Fprint(hash, path.ip, path.registers)
+
+	return hash.Sum64()
+}
+
+// applySymbolic computes the result of the instruction, tracking how it depends on register 0
+func (ins Instruction) applySymbolic(registers []symbol) (symbol, error) {
+	a, b := known(ins.A), known(ins.B)
+	if ins.Op.UsesRegisterA() {
+		a = registers[ins.A]
+	}
+	if ins.Op.UsesRegisterB() {
+		b = registers[ins.B]
+	}
+
+	switch {
+	case ins.Op == Setr || ins.Op == Seti:
+		return a, nil
+	case a.kind == knownSymbol && b.kind == knownSymbol:
+		return known(ins.Op.Compute(a.value, b.value)), nil
+	case a.kind == conditionSymbol || b.kind == conditionSymbol:
+		return combineConditions(ins.Op, a, b)
+	case a.kind == register0Symbol && b.kind == register0Symbol:
+		if ins.Op.IsComparison() {
+			return known(ins.Op.Compute(a.value, b.value)), nil
+		}
+	case ins.Op == Addr || ins.Op == Addi:
+		return symbol{kind: register0Symbol, value: a.value + b.value}, nil
+	case ins.Op.IsComparison():
+		// Move the offset to the other side of the comparison, so that register 0 is compared against a constant
+		offset, constant := a.value, b.value
+		relation := RelationGreater
+		if b.kind == register0Symbol {
+			offset, constant = b.value, a.value
+			relation = RelationLess
+		}
+		if ins.Op == Eqir || ins.Op == Eqri || ins.Op == Eqrr {
+			relation = RelationEqual
+		}
+
+		return symbol{kind: conditionSymbol, relation: relation, value: constant - offset, ifTrue: 1, ifFalse: 0}, nil
+	}
+
+	return symbol{}, ErrUnsupportedSymbolic
+}
+
+// combineConditions computes the result of an operation where at least one operand depends on a comparison.
+// Both operands must depend on the same comparison, if they are not known.
+func combineConditions(op Opcode, a symbol, b symbol) (symbol, error) {
+	condition := a
+	if a.kind != conditionSymbol {
+		condition = b
+	}
+
+	outcome := func(operand symbol, holds bool) (int, bool) {
+		switch {
+		case operand.kind == knownSymbol:
+			return operand.value, true
+		case operand.kind != conditionSymbol || operand.relation != condition.relation || operand.value != condition.value:
+			return 0, false
+		case holds:
+			return operand.ifTrue, true
+		default:
+			return operand.ifFalse, true
+		}
+	}
+
+	aTrue, aOK := outcome(a, true)
+	bTrue, bOK := outcome(b, true)
+	aFalse, _ := outcome(a, false)
+	bFalse, _ := outcome(b, false)
+	if !aOK || !bOK {
+		return symbol{}, ErrUnsupportedSymbolic
+	}
+
+	ifTrue, ifFalse := op.Compute(aTrue, bTrue), op.Compute(aFalse, bFalse)
+	if ifTrue == ifFalse {
+		return known(ifTrue), nil
+	}
+
+	return symbol{kind: conditionSymbol, relation: condition.relation, value: condition.value, ifTrue: ifTrue, ifFalse: ifFalse}, nil
+}
+
+// with adds a constraint to the condition, returning false if no value of register 0 could meet it.
+// state identifies the state of the machine at the branch the constraint comes from.
+func (node *conditionNode) with(constraint Constraint, state uint64) (*conditionNode, bool) {
+	added := &conditionNode{
+		constraint: constraint,
+		parent:     node,
+		lo:         math.MinInt64,
+		hi:         math.MaxInt64,
+		state:      state,
+	}
+	if node != nil {
+		added.depth = node.depth + 1
+		added.lo, added.hi, added.pinned = node.lo, node.hi, node.pinned
+	}
+
+	value := constraint.Value
+	switch {
+	case constraint.Relation == RelationEqual && constraint.Holds:
+		if value < added.lo || value > added.hi || node.excludes(value) {
+			return nil, false
+		}
+		added.lo, added.hi, added.pinned = value, value, true
+	case constraint.Relation == RelationEqual:
+		// Excluded values are found by walking the condition, rather than being stored
+	case constraint.Relation == RelationGreater && constraint.Holds:
+		if value == math.MaxInt64 {
+			return nil, false
+		}
+		added.lo = maxInt(added.lo, value+1)
+	case constraint.Relation == RelationGreater:
+		added.hi = minInt(added.hi, value)
+	case constraint.Relation == RelationLess && constraint.Holds:
+		if value == math.MinInt64 {
+			return nil, false
+		}
+		added.hi = minInt(added.hi, value-1)
+	case constraint.Relation == RelationLess:
+		added.lo = maxInt(added.lo, value)
+	}
+
+	if added.lo > added.hi || !added.hasUnexcludedValue() {
+		return nil, false
+	}
+
+	return added, true
+}
+
+// excludes checks if the condition requires register 0 to not be the given value
+func (node *conditionNode) excludes(value int) bool {
+	for ; node != nil; node = node.parent {
+		if node.constraint.Relation == RelationEqual && !node.constraint.Holds && node.constraint.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasUnexcludedValue checks if any value in the condition's range is not excluded by it
+func (node *conditionNode) hasUnexcludedValue() bool {
+	if node.pinned {
+		return !node.excludes(node.lo)
+	}
+
+	// There can't be more excluded values than constraints, so a wide enough range must have a value left over
+	width := uint64(node.hi) - uint64(node.lo)
+	if width >= uint64(node.depth) {
+		return true
+	}
+
+	excluded := map[int]bool{}
+	for ancestor := node; ancestor != nil; ancestor = ancestor.parent {
+		constraint := ancestor.constraint
+		if constraint.Relation == RelationEqual && !constraint.Holds && constraint.Value >= node.lo && constraint.Value <= node.hi {
+			excluded[constraint.Value] = true
+		}
+	}
+
+	return uint64(len(excluded)) <= width
+}
+
+// repeatsState checks if any earlier branch on the path was taken in the same state as the one that added this constraint
+func (node *conditionNode) repeatsState() bool {
+	for ancestor := node.parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor.state == node.state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Constraints gets every constraint in the condition, in the order they were added
+func (condition Condition) Constraints() []Constraint {
+	constraints := []Constraint{}
+	for node := condition.node; node != nil; node = node.parent {
+		constraints = append(constraints, node.constraint)
+	}
+
+	for i, j := 0, len(constraints)-1; i < j; i, j = i+1, j-1 {
+		constraints[i], constraints[j] = constraints[j], constraints[i]
+	}
+
+	return constraints
+}
+
+// Value gets the only value of register 0 that meets the condition, if there is only one
+func (condition Condition) Value() (int, bool) {
+	if condition.node == nil || !condition.node.pinned {
+		return 0, false
+	}
+
+	return condition.node.lo, true
+}
+
+// String renders the condition in its simplest form, listing only a few of the values it excludes
+func (condition Condition) String() string {
+	node := condition.node
+	if node == nil {
+		return "always"
+	} else if node.pinned {
+		return fmt.Sprintf("r0 == %d", node.lo)
+	}
+
+	parts := []string{}
+	if node.lo != math.MinInt64 && node.hi != math.MaxInt64 {
+		parts = append(parts, fmt.Sprintf("%d <= r0 <= %d", node.lo, node.hi))
+	} else if node.lo != math.MinInt64 {
+		parts = append(parts, fmt.Sprintf("r0 >= %d", node.lo))
+	} else if node.hi != math.MaxInt64 {
+		parts = append(parts, fmt.Sprintf("r0 <= %d", node.hi))
+	}
+
+	excluded := []int{}
+	seen := map[int]bool{}
+	for _, constraint := range condition.Constraints() {
+		if constraint.Relation == RelationEqual && !constraint.Holds && !seen[constraint.Value] {
+			seen[constraint.Value] = true
+			excluded = append(excluded, constraint.Value)
+		}
+	}
+
+	if len(excluded) > 0 {
+		listed := make([]string, 0, maxConditionExclusions)
+		for _, value := range excluded {
+			if len(listed) == maxConditionExclusions {
+				break
+			}
+			listed = append(listed, fmt.Sprint(value))
+		}
+
+		exclusions := "r0 != " + strings.Join(listed, ", ")
+		if len(excluded) > len(listed) {
+			exclusions += fmt.Sprintf(" or %d other values", len(excluded)-len(listed))
+		}
+		parts = append(parts, exclusions)
+	}
+
+	if len(parts) == 0 {
+		return "always"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func sortHaltingPaths(paths []HaltingPath) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return paths[i].Instructions < paths[j].Instructions
+	})
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
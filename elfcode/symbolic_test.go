@@ -0,0 +1,65 @@
+package elfcode
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestExecuteSymbolicExample(t *testing.T) {
+	result, err := ExecuteSymbolic(mustParseProgram(t, day21Example), 6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// These are the same as the profiler finds by running the program
+	var want []HaltingValue
+	for i, value := range []int{3, 6, 1, 4, 7, 2, 5, 0} {
+		want = append(want, HaltingValue{Value: value, Instructions: 3 + 5*i + 2})
+	}
+	if got := result.HaltingValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got halting values %v, want %v", got, want)
+	}
+	if result.Repeated != 1 || result.Truncated {
+		t.Errorf("got %d repeated paths and truncated %t, want 1 repeated path and not truncated", result.Repeated, result.Truncated)
+	}
+}
+
+func TestExecuteSymbolicDay21(t *testing.T) {
+	rawProgram, err := os.ReadFile("testdata/day21.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ExecuteSymbolic(mustParseProgram(t, string(rawProgram)), 6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first and last values are the answers to both parts of day 21
+	haltingValues := result.HaltingValues()
+	if len(haltingValues) == 0 {
+		t.Fatal("found no halting values")
+	} else if first := haltingValues[0]; first != (HaltingValue{Value: 3115806, Instructions: 1848}) {
+		t.Errorf("got first halting value %+v, want 3115806 after 1848 instructions", first)
+	} else if last := haltingValues[len(haltingValues)-1]; last.Value != 13959373 {
+		t.Errorf("got last halting value %d, want 13959373", last.Value)
+	}
+}
+
+func TestExecuteSymbolicTruncated(t *testing.T) {
+	result, err := ExecuteSymbolic(mustParseProgram(t, day21Example), 6, 10)
+	if err != nil {
+		t.Fatal(err)
+	} else if !result.Truncated {
+		t.Error("expected the result to be truncated")
+	}
+}
+
+func TestExecuteSymbolicIPRegister0(t *testing.T) {
+	_, err := ExecuteSymbolic(mustParseProgram(t, day19Example), 6, 0)
+	if !errors.Is(err, ErrUnsupportedSymbolic) {
+		t.Errorf("got error %v, want %v", err, ErrUnsupportedSymbolic)
+	}
+}
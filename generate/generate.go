@@ -0,0 +1,89 @@
+// Package generate makes random puzzle inputs for every day, so that the solutions can be stress tested, benchmarked
+// on inputs much larger than the real ones, and have their parsers fuzzed
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// ErrNoGenerator is returned when looking up a day that has no registered generator
+var ErrNoGenerator = errors.New("no generator")
+
+// Generator makes random inputs for a day's puzzle. Every input it makes can be parsed by the day's puzzle, and has an
+// answer to both parts.
+type Generator struct {
+	// Size describes what the size of an input counts, such as "claims", or is empty if every input is the same size
+	Size string
+	// DefaultSize is a size close to that of the real puzzle input
+	DefaultSize int
+	// Generate makes an input of the given size, which is at least 1. rng must be its only source of randomness, so
+	// that the same seed always gives the same input.
+	Generate func(rng *rand.Rand, size int) string
+}
+
+// registry maps each day to its generator
+var registry = map[int]Generator{}
+
+// Register adds the generator for a day. It is meant to be called from the init function of each day's package.
+func Register(day int, generator Generator) {
+	if _, exists := registry[day]; exists {
+		panic(fmt.Sprintf("day %d registered twice", day))
+	}
+
+	registry[day] = generator
+}
+
+// Lookup gets the generator for a day
+func Lookup(day int) (Generator, error) {
+	generator, ok := registry[day]
+	if !ok {
+		return Generator{}, fmt.Errorf("day %d: %w", day, ErrNoGenerator)
+	}
+
+	return generator, nil
+}
+
+// Days gets every day that has a registered generator, in order
+func Days() []int {
+	days := make([]int, 0, len(registry))
+	for day := range registry {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	return days
+}
+
+// Input makes a random input for a day's puzzle from the given seed. A size of 0 uses the day's default size.
+func Input(day int, seed int64, size int) (string, error) {
+	generator, err := Lookup(day)
+	if err != nil {
+		return "", err
+	} else if size < 0 {
+		return "", fmt.Errorf("size %d must not be negative", size)
+	} else if size == 0 {
+		size = generator.DefaultSize
+	}
+
+	return generator.Generate(rand.New(rand.NewSource(seed)), size), nil
+}
+
+// Lines joins the lines of an input, ending each of them with a newline like the real puzzle inputs
+func Lines(lines []string) string {
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+
+	return builder.String()
+}
+
+// Between gets a random number from min to max, inclusive
+func Between(rng *rand.Rand, min int, max int) int {
+	return min + rng.Intn(max-min+1)
+}
@@ -0,0 +1,140 @@
+package generate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/aoc"
+	"github.com/ollien/advent-of-code-2018/generate"
+
+	// Importing each day registers its solvers and generator
+	_ "github.com/ollien/advent-of-code-2018/day1"
+	_ "github.com/ollien/advent-of-code-2018/day10"
+	_ "github.com/ollien/advent-of-code-2018/day11"
+	_ "github.com/ollien/advent-of-code-2018/day12"
+	_ "github.com/ollien/advent-of-code-2018/day13"
+	_ "github.com/ollien/advent-of-code-2018/day14"
+	_ "github.com/ollien/advent-of-code-2018/day15"
+	_ "github.com/ollien/advent-of-code-2018/day16"
+	_ "github.com/ollien/advent-of-code-2018/day17"
+	_ "github.com/ollien/advent-of-code-2018/day18"
+	_ "github.com/ollien/advent-of-code-2018/day19"
+	_ "github.com/ollien/advent-of-code-2018/day2"
+	_ "github.com/ollien/advent-of-code-2018/day20"
+	_ "github.com/ollien/advent-of-code-2018/day21"
+	_ "github.com/ollien/advent-of-code-2018/day22"
+	_ "github.com/ollien/advent-of-code-2018/day3"
+	_ "github.com/ollien/advent-of-code-2018/day4"
+	_ "github.com/ollien/advent-of-code-2018/day5"
+	_ "github.com/ollien/advent-of-code-2018/day6"
+	_ "github.com/ollien/advent-of-code-2018/day7"
+	_ "github.com/ollien/advent-of-code-2018/day8"
+	_ "github.com/ollien/advent-of-code-2018/day9"
+)
+
+// partTimeout is how long a part may take on one of the small inputs made by the tests
+const partTimeout = 30 * time.Second
+
+// The inputs made for each day by the tests, which are kept small so that every part solves quickly
+var (
+	testSeeds = []int64{0, 1, 2}
+	testSizes = []int{1, 2, 5}
+)
+
+func TestEveryDayHasGenerator(t *testing.T) {
+	if got, want := generate.Days(), aoc.Days(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got generators for days %v, want %v", got, want)
+	}
+}
+
+func TestInputsSolve(t *testing.T) {
+	for _, day := range generate.Days() {
+		t.Run(fmt.Sprintf("day%d", day), func(t *testing.T) {
+			puzzle, err := aoc.Lookup(day)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, size := range testSizes {
+				for _, seed := range testSeeds {
+					input, err := generate.Input(day, seed, size)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					parsedInput, err := puzzle.Parse(strings.NewReader(input))
+					if err != nil {
+						t.Fatalf("size %d seed %d: could not parse input: %s\n%s", size, seed, err, input)
+					}
+
+					for part := 1; part <= aoc.NumParts; part++ {
+						ctx, cancel := context.WithTimeout(context.Background(), partTimeout)
+						_, err := puzzle.Solve(ctx, part, parsedInput, aoc.Budget{})
+						cancel()
+						if err != nil {
+							t.Errorf("size %d seed %d part %d: %s\n%s", size, seed, part, err, input)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInputIsDeterministic(t *testing.T) {
+	for _, day := range generate.Days() {
+		first, err := generate.Input(day, 1, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		second, err := generate.Input(day, 1, 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if first != second {
+			t.Errorf("day %d made different inputs from the same seed", day)
+		}
+	}
+}
+
+func TestInputUnknownDay(t *testing.T) {
+	_, err := generate.Input(26, 1, 0)
+	if !errors.Is(err, generate.ErrNoGenerator) {
+		t.Errorf("got error %v, want %v", err, generate.ErrNoGenerator)
+	}
+}
+
+func TestInputNegativeSize(t *testing.T) {
+	if _, err := generate.Input(1, 1, -1); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+}
+
+// FuzzParse checks that no day's parser panics, starting from inputs made by each day's generator
+func FuzzParse(f *testing.F) {
+	for _, day := range generate.Days() {
+		for _, size := range testSizes {
+			input, err := generate.Input(day, 1, size)
+			if err != nil {
+				f.Fatal(err)
+			}
+
+			f.Add(day, input)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, day int, input string) {
+		puzzle, err := aoc.Lookup(day)
+		if err != nil {
+			t.Skip()
+		}
+
+		// Errors are fine, as long as the parser returns them rather than panicking
+		puzzle.Parse(strings.NewReader(input))
+	})
+}
@@ -0,0 +1,3 @@
+module github.com/ollien/advent-of-code-2018
+
+go 1.24
@@ -0,0 +1,138 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Dense is a grid with a cell at every point in a rectangle
+type Dense[T comparable] struct {
+	bounds Rect
+	// cells holds each row of the rectangle in turn
+	cells []T
+}
+
+// NewDense makes a grid covering the given rectangle, with every cell set to the zero value of T
+func NewDense[T comparable](bounds Rect) *Dense[T] {
+	if bounds.Empty() {
+		bounds = Rect{Min: bounds.Min, Max: bounds.Min}
+	}
+
+	return &Dense[T]{
+		bounds: bounds,
+		cells:  make([]T, bounds.Rows()*bounds.Cols()),
+	}
+}
+
+func (g *Dense[T]) index(p Point) int {
+	return (p.Row-g.bounds.Min.Row)*g.bounds.Cols() + (p.Col - g.bounds.Min.Col)
+}
+
+// Get gets the cell at p, or the zero value of T if p is outside the grid
+func (g *Dense[T]) Get(p Point) T {
+	if !g.Contains(p) {
+		var zero T
+		return zero
+	}
+
+	return g.cells[g.index(p)]
+}
+
+// Set sets the cell at p, which must be inside the grid
+func (g *Dense[T]) Set(p Point, value T) {
+	if !g.Contains(p) {
+		panic(fmt.Sprintf("%v is outside of the grid's bounds, %v", p, g.bounds))
+	}
+
+	g.cells[g.index(p)] = value
+}
+
+// Contains reports whether p is inside the grid
+func (g *Dense[T]) Contains(p Point) bool {
+	return g.bounds.Contains(p)
+}
+
+// Bounds gets the rectangle the grid covers
+func (g *Dense[T]) Bounds() Rect {
+	return g.bounds
+}
+
+// All iterates over every cell in reading order
+func (g *Dense[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		i := 0
+		for p := range g.bounds.Points() {
+			if !yield(p, g.cells[i]) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Clone makes a copy of the grid. The cells themselves are copied as they are, so pointers will be shared.
+func (g *Dense[T]) Clone() *Dense[T] {
+	cloned := &Dense[T]{bounds: g.bounds, cells: make([]T, len(g.cells))}
+	copy(cloned.cells, g.cells)
+
+	return cloned
+}
+
+// Equal reports whether both grids cover the same rectangle, with the same cells
+func (g *Dense[T]) Equal(other *Dense[T]) bool {
+	if g == nil || other == nil {
+		return g == other
+	} else if g.bounds != other.bounds {
+		return false
+	}
+
+	for i := range g.cells {
+		if g.cells[i] != other.cells[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Changes iterates, in reading order, over the cells of other that differ from the cells of g. Both grids must cover
+// the same rectangle.
+func (g *Dense[T]) Changes(other *Dense[T]) iter.Seq2[Point, T] {
+	if g.bounds != other.bounds {
+		panic(fmt.Sprintf("cannot compare grids with different bounds, %v and %v", g.bounds, other.bounds))
+	}
+
+	return func(yield func(Point, T) bool) {
+		cols := g.bounds.Cols()
+		for i, cell := range other.cells {
+			if cell == g.cells[i] {
+				continue
+			}
+
+			p := Point{Row: g.bounds.Min.Row + i/cols, Col: g.bounds.Min.Col + i%cols}
+			if !yield(p, cell) {
+				return
+			}
+		}
+	}
+}
+
+// Hash hashes the grid, so that equal grids have equal hashes. Hashes are only stable within a single run of the program.
+func (g *Dense[T]) Hash() uint64 {
+	return hashCells[T](g)
+}
+
+// Render draws the grid with a character for each cell, with each row on its own line
+func (g *Dense[T]) Render(cellChar func(T) rune) string {
+	return render[T](g, cellChar)
+}
+
+// Map makes a grid covering the same rectangle, with each cell set to the result of f on the cell of g at that point
+func Map[T, U comparable](g *Dense[T], f func(T) U) *Dense[U] {
+	mapped := &Dense[U]{bounds: g.bounds, cells: make([]U, len(g.cells))}
+	for i, cell := range g.cells {
+		mapped.cells[i] = f(cell)
+	}
+
+	return mapped
+}
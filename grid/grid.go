@@ -0,0 +1,188 @@
+// Package grid implements the 2D grids shared by the days whose puzzles are laid out on a map,
+// with a dense backend for grids that fill a rectangle and a sparse one for grids that don't
+package grid
+
+import (
+	"hash/maphash"
+	"iter"
+)
+
+// Point is a location in a grid. Rows count down from the top, and columns count right from the left.
+type Point struct {
+	Row int
+	Col int
+}
+
+// The offsets to each of a point's neighbors
+var (
+	Up        = Point{Row: -1}
+	Down      = Point{Row: 1}
+	Left      = Point{Col: -1}
+	Right     = Point{Col: 1}
+	UpLeft    = Point{Row: -1, Col: -1}
+	UpRight   = Point{Row: -1, Col: 1}
+	DownLeft  = Point{Row: 1, Col: -1}
+	DownRight = Point{Row: 1, Col: 1}
+)
+
+// neighborOffsets4 and neighborOffsets8 are in reading order, so that the neighbors they find are too
+var (
+	neighborOffsets4 = []Point{Up, Left, Right, Down}
+	neighborOffsets8 = []Point{UpLeft, Up, UpRight, Left, Right, DownLeft, Down, DownRight}
+)
+
+// seed is shared by every grid, so that equal grids hash the same within a run of the program
+var seed = maphash.MakeSeed()
+
+// Add gets the point offset from p by offset
+func (p Point) Add(offset Point) Point {
+	return Point{Row: p.Row + offset.Row, Col: p.Col + offset.Col}
+}
+
+// Neighbors4 gets the points directly above, left of, right of, and below p, in reading order
+func (p Point) Neighbors4() []Point {
+	return p.offsetBy(neighborOffsets4)
+}
+
+// Neighbors8 gets the points around p, including the diagonals, in reading order
+func (p Point) Neighbors8() []Point {
+	return p.offsetBy(neighborOffsets8)
+}
+
+func (p Point) offsetBy(offsets []Point) []Point {
+	points := make([]Point, len(offsets))
+	for i, offset := range offsets {
+		points[i] = p.Add(offset)
+	}
+
+	return points
+}
+
+// ReadingLess reports whether a comes before b in reading order, which goes left to right along each row, top to bottom
+func ReadingLess(a, b Point) bool {
+	if a.Row == b.Row {
+		return a.Col < b.Col
+	}
+
+	return a.Row < b.Row
+}
+
+// Rect is the rectangle of points from Min, up to but not including Max
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// RectOf makes a rectangle of the given number of rows and columns, starting at 0,0
+func RectOf(rows, cols int) Rect {
+	return Rect{Max: Point{Row: rows, Col: cols}}
+}
+
+// Rows gets the number of rows in the rectangle
+func (r Rect) Rows() int {
+	return r.Max.Row - r.Min.Row
+}
+
+// Cols gets the number of columns in the rectangle
+func (r Rect) Cols() int {
+	return r.Max.Col - r.Min.Col
+}
+
+// Empty reports whether the rectangle has no points in it
+func (r Rect) Empty() bool {
+	return r.Rows() <= 0 || r.Cols() <= 0
+}
+
+// Contains reports whether p is in the rectangle
+func (r Rect) Contains(p Point) bool {
+	return p.Row >= r.Min.Row && p.Row < r.Max.Row && p.Col >= r.Min.Col && p.Col < r.Max.Col
+}
+
+// Extend gets the smallest rectangle that holds both r and p
+func (r Rect) Extend(p Point) Rect {
+	if r.Empty() {
+		return Rect{Min: p, Max: p.Add(DownRight)}
+	}
+
+	extended := r
+	extended.Min.Row = min(extended.Min.Row, p.Row)
+	extended.Min.Col = min(extended.Min.Col, p.Col)
+	extended.Max.Row = max(extended.Max.Row, p.Row+1)
+	extended.Max.Col = max(extended.Max.Col, p.Col+1)
+
+	return extended
+}
+
+// Points iterates over every point in the rectangle in reading order
+func (r Rect) Points() iter.Seq[Point] {
+	return func(yield func(Point) bool) {
+		for row := r.Min.Row; row < r.Max.Row; row++ {
+			for col := r.Min.Col; col < r.Max.Col; col++ {
+				if !yield(Point{Row: row, Col: col}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Grid is a grid of cells of type T, which is either a *Dense or a *Sparse
+type Grid[T comparable] interface {
+	// Get gets the cell at p, or the zero value of T if there is no cell there
+	Get(p Point) T
+	// Set sets the cell at p
+	Set(p Point, value T)
+	// Contains reports whether there is a cell at p
+	Contains(p Point) bool
+	// Bounds gets the smallest rectangle that holds every cell
+	Bounds() Rect
+	// All iterates over every cell in reading order
+	All() iter.Seq2[Point, T]
+}
+
+// Neighbors4 gets the points directly above, left of, right of, and below p that have a cell in the grid,
+// in reading order
+func Neighbors4[T comparable](g Grid[T], p Point) []Point {
+	return filterContained(g, p.Neighbors4())
+}
+
+// Neighbors8 gets the points around p, including the diagonals, that have a cell in the grid, in reading order
+func Neighbors8[T comparable](g Grid[T], p Point) []Point {
+	return filterContained(g, p.Neighbors8())
+}
+
+func filterContained[T comparable](g Grid[T], points []Point) []Point {
+	contained := points[:0]
+	for _, p := range points {
+		if g.Contains(p) {
+			contained = append(contained, p)
+		}
+	}
+
+	return contained
+}
+
+// Count gets the number of cells in the grid that hold the given value
+func Count[T comparable](g Grid[T], value T) int {
+	count := 0
+	for _, cell := range g.All() {
+		if cell == value {
+			count++
+		}
+	}
+
+	return count
+}
+
+// hashCells hashes the bounds and cells of a grid, in reading order
+func hashCells[T comparable](g Grid[T]) uint64 {
+	var hash maphash.Hash
+	hash.SetSeed(seed)
+	maphash.WriteComparable(&hash, g.Bounds())
+	for p, cell := range g.All() {
+		maphash.WriteComparable(&hash, p)
+		maphash.WriteComparable(&hash, cell)
+	}
+
+	return hash.Sum64()
+}
@@ -0,0 +1,169 @@
+package grid
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func parseHash(char rune) (bool, error) {
+	switch char {
+	case '#':
+		return true, nil
+	case '.':
+		return false, nil
+	default:
+		return false, errors.New("bad char")
+	}
+}
+
+func renderHash(cell bool) rune {
+	if cell {
+		return '#'
+	}
+
+	return '.'
+}
+
+func TestNeighbors(t *testing.T) {
+	g := NewDense[int](RectOf(3, 3))
+	tests := []struct {
+		name string
+		got  []Point
+		want []Point
+	}{
+		{"4 in the middle", Neighbors4[int](g, Point{1, 1}), []Point{{0, 1}, {1, 0}, {1, 2}, {2, 1}}},
+		{"4 in a corner", Neighbors4[int](g, Point{0, 0}), []Point{{0, 1}, {1, 0}}},
+		{"8 on an edge", Neighbors8[int](g, Point{0, 1}), []Point{{0, 0}, {0, 2}, {1, 0}, {1, 1}, {1, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAndRender(t *testing.T) {
+	lines := []string{"#..", ".#.", "..#"}
+	dense, err := ParseDense(lines, parseHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparse, err := ParseSparse(lines, parseHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "#..\n.#.\n..#"
+	if got := dense.Render(renderHash); got != want {
+		t.Errorf("got dense\n%s\nwant\n%s", got, want)
+	}
+	if got := sparse.Render(renderHash); got != want {
+		t.Errorf("got sparse\n%s\nwant\n%s", got, want)
+	}
+	if sparse.Len() != 3 || Count[bool](dense, true) != 3 {
+		t.Errorf("got %d sparse cells and %d set dense cells, want 3", sparse.Len(), Count[bool](dense, true))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []string
+		wantPoint Point
+		wantErr   error
+	}{
+		{"ragged", []string{"#..", "#."}, Point{1, 2}, ErrRagged},
+		{"bad char", []string{"#..", "#x."}, Point{1, 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDense(tt.lines, parseHash)
+			var cellErr *CellError
+			if !errors.As(err, &cellErr) {
+				t.Fatalf("got error %v, want a cell error", err)
+			} else if cellErr.Point != tt.wantPoint {
+				t.Errorf("got error at %v, want %v", cellErr.Point, tt.wantPoint)
+			} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCloneEqualHash(t *testing.T) {
+	dense := NewDense[int](Rect{Min: Point{-1, -1}, Max: Point{2, 2}})
+	dense.Set(Point{-1, 0}, 5)
+	sparse := NewSparse[int]()
+	sparse.Set(Point{-10, 4}, 5)
+
+	clonedDense := dense.Clone()
+	clonedSparse := sparse.Clone()
+	if !dense.Equal(clonedDense) || dense.Hash() != clonedDense.Hash() {
+		t.Error("cloned dense grid is not equal to the original")
+	}
+	if !sparse.Equal(clonedSparse) || sparse.Hash() != clonedSparse.Hash() {
+		t.Error("cloned sparse grid is not equal to the original")
+	}
+
+	clonedDense.Set(Point{1, 1}, 1)
+	clonedSparse.Set(Point{1, 1}, 1)
+	if dense.Equal(clonedDense) || dense.Get(Point{1, 1}) != 0 {
+		t.Error("changing a cloned dense grid changed the original")
+	}
+	if sparse.Equal(clonedSparse) || sparse.Contains(Point{1, 1}) {
+		t.Error("changing a cloned sparse grid changed the original")
+	}
+}
+
+func TestMapAndChanges(t *testing.T) {
+	g := NewDense[int](Rect{Min: Point{-1, -1}, Max: Point{2, 2}})
+	g.Set(Point{0, 1}, 3)
+	doubled := Map(g, func(n int) int { return n * 2 })
+	if doubled.Bounds() != g.Bounds() || doubled.Get(Point{0, 1}) != 6 {
+		t.Errorf("mapped grid should have the same bounds with every cell doubled, got %v", doubled.Render(func(n int) rune { return rune('0' + n) }))
+	}
+
+	changed := map[Point]int{}
+	for p, cell := range g.Changes(doubled) {
+		changed[p] = cell
+	}
+	if len(changed) != 1 || changed[Point{0, 1}] != 6 {
+		t.Errorf("got changes %v, want only (0, 1) changing to 6", changed)
+	}
+}
+
+func TestSparseBounds(t *testing.T) {
+	g := NewSparse[int]()
+	if !g.Bounds().Empty() {
+		t.Errorf("got bounds %v for an empty grid, want them to be empty", g.Bounds())
+	}
+
+	g.Set(Point{-2, 3}, 1)
+	g.Set(Point{4, -1}, 1)
+	want := Rect{Min: Point{-2, -1}, Max: Point{5, 4}}
+	if got := g.Bounds(); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAllInReadingOrder(t *testing.T) {
+	g := NewSparse[int]()
+	for _, p := range []Point{{2, 0}, {0, 5}, {0, 1}, {1, -3}} {
+		g.Set(p, 1)
+	}
+
+	var got []Point
+	for p := range g.All() {
+		got = append(got, p)
+	}
+
+	want := []Point{{0, 1}, {0, 5}, {1, -3}, {2, 0}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package grid
+
+import (
+	"iter"
+	"sort"
+)
+
+// Sparse is a grid that only has cells at the points they have been set at, which may be anywhere
+type Sparse[T comparable] struct {
+	cells map[Point]T
+}
+
+// NewSparse makes a grid with no cells
+func NewSparse[T comparable]() *Sparse[T] {
+	return &Sparse[T]{cells: make(map[Point]T)}
+}
+
+// Get gets the cell at p, or the zero value of T if there is no cell there
+func (g *Sparse[T]) Get(p Point) T {
+	return g.cells[p]
+}
+
+// Lookup gets the cell at p, and whether there is one
+func (g *Sparse[T]) Lookup(p Point) (T, bool) {
+	value, ok := g.cells[p]
+
+	return value, ok
+}
+
+// Set sets the cell at p
+func (g *Sparse[T]) Set(p Point, value T) {
+	g.cells[p] = value
+}
+
+// Delete removes the cell at p, if there is one
+func (g *Sparse[T]) Delete(p Point) {
+	delete(g.cells, p)
+}
+
+// Contains reports whether there is a cell at p
+func (g *Sparse[T]) Contains(p Point) bool {
+	_, ok := g.cells[p]
+
+	return ok
+}
+
+// Len gets the number of cells in the grid
+func (g *Sparse[T]) Len() int {
+	return len(g.cells)
+}
+
+// Bounds gets the smallest rectangle that holds every cell, which is empty if there are no cells
+func (g *Sparse[T]) Bounds() Rect {
+	bounds := Rect{}
+	for p := range g.cells {
+		bounds = bounds.Extend(p)
+	}
+
+	return bounds
+}
+
+// All iterates over every cell in reading order
+func (g *Sparse[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for _, p := range g.sortedPoints() {
+			if !yield(p, g.cells[p]) {
+				return
+			}
+		}
+	}
+}
+
+func (g *Sparse[T]) sortedPoints() []Point {
+	points := make([]Point, 0, len(g.cells))
+	for p := range g.cells {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return ReadingLess(points[i], points[j])
+	})
+
+	return points
+}
+
+// Clone makes a copy of the grid. The cells themselves are copied as they are, so pointers will be shared.
+func (g *Sparse[T]) Clone() *Sparse[T] {
+	cloned := &Sparse[T]{cells: make(map[Point]T, len(g.cells))}
+	for p, value := range g.cells {
+		cloned.cells[p] = value
+	}
+
+	return cloned
+}
+
+// Equal reports whether both grids have the same cells at the same points
+func (g *Sparse[T]) Equal(other *Sparse[T]) bool {
+	if g == nil || other == nil {
+		return g == other
+	} else if len(g.cells) != len(other.cells) {
+		return false
+	}
+
+	for p, value := range g.cells {
+		otherValue, ok := other.cells[p]
+		if !ok || value != otherValue {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Hash hashes the grid, so that equal grids have equal hashes. Hashes are only stable within a single run of the program.
+func (g *Sparse[T]) Hash() uint64 {
+	return hashCells[T](g)
+}
+
+// Render draws every point within the grid's bounds with a character for each cell, with each row on its own line.
+// Points without a cell are drawn as the zero value of T.
+func (g *Sparse[T]) Render(cellChar func(T) rune) string {
+	return render[T](g, cellChar)
+}
@@ -0,0 +1,90 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRagged is held by a CellError when a row of a character map is not as long as the first row
+var ErrRagged = errors.New("row is not the same length as the first row")
+
+// CellError is an error with a character in a character map
+type CellError struct {
+	Point Point
+	Err   error
+}
+
+func (cellErr *CellError) Error() string {
+	return fmt.Sprintf("row %d, column %d: %s", cellErr.Point.Row, cellErr.Point.Col, cellErr.Err)
+}
+
+func (cellErr *CellError) Unwrap() error {
+	return cellErr.Err
+}
+
+// ParseDense parses a character map, with each line as a row, into a grid starting at 0,0.
+// Every row must be the same length. Errors are returned as a *CellError pointing at the character at fault.
+func ParseDense[T comparable](lines []string, parseCell func(char rune) (T, error)) (*Dense[T], error) {
+	numCols := 0
+	if len(lines) > 0 {
+		numCols = len([]rune(lines[0]))
+	}
+
+	g := NewDense[T](RectOf(len(lines), numCols))
+	for row, line := range lines {
+		chars := []rune(line)
+		if len(chars) != numCols {
+			return nil, &CellError{Point: Point{Row: row, Col: min(len(chars), numCols)}, Err: ErrRagged}
+		}
+
+		for col, char := range chars {
+			p := Point{Row: row, Col: col}
+			value, err := parseCell(char)
+			if err != nil {
+				return nil, &CellError{Point: p, Err: err}
+			}
+
+			g.Set(p, value)
+		}
+	}
+
+	return g, nil
+}
+
+// ParseSparse parses a character map, with each line as a row, into a grid starting at 0,0.
+// Only the cells that don't parse to the zero value of T are kept. Errors are returned as a *CellError pointing at the
+// character at fault.
+func ParseSparse[T comparable](lines []string, parseCell func(char rune) (T, error)) (*Sparse[T], error) {
+	var zero T
+	g := NewSparse[T]()
+	for row, line := range lines {
+		for col, char := range []rune(line) {
+			p := Point{Row: row, Col: col}
+			value, err := parseCell(char)
+			if err != nil {
+				return nil, &CellError{Point: p, Err: err}
+			} else if value != zero {
+				g.Set(p, value)
+			}
+		}
+	}
+
+	return g, nil
+}
+
+func render[T comparable](g Grid[T], cellChar func(T) rune) string {
+	bounds := g.Bounds()
+	var builder strings.Builder
+	for row := bounds.Min.Row; row < bounds.Max.Row; row++ {
+		if row != bounds.Min.Row {
+			builder.WriteByte('\n')
+		}
+
+		for col := bounds.Min.Col; col < bounds.Max.Col; col++ {
+			builder.WriteRune(cellChar(g.Get(Point{Row: row, Col: col})))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,48 @@
+// Package replay records the boards of the grid simulations as they run, so that they can be played back and stepped
+// through afterwards
+package replay
+
+import (
+	"image/color"
+
+	"github.com/ollien/advent-of-code-2018/grid"
+)
+
+// Frame is what a simulation's board looked like after a single tick
+type Frame struct {
+	// Caption describes the tick, such as which round it was
+	Caption string
+	// Cells holds the character for every cell of the board
+	Cells *grid.Dense[rune]
+}
+
+// Recorder receives a frame for every tick of a simulation, starting with the board before the first tick.
+// A frame must not be changed once it has been recorded.
+type Recorder interface {
+	Record(frame Frame)
+}
+
+// Palette gives the colour to show each kind of cell in, by the character the cell is drawn with.
+// Characters without a colour are shown in the default colour.
+type Palette map[rune]color.RGBA
+
+// prefixRecorder adds a prefix to the caption of every frame it records
+type prefixRecorder struct {
+	recorder Recorder
+	prefix   string
+}
+
+func (r prefixRecorder) Record(frame Frame) {
+	frame.Caption = r.prefix + frame.Caption
+	r.recorder.Record(frame)
+}
+
+// Prefix makes a recorder that adds a prefix to every frame's caption before recording it with the given recorder,
+// such as to tell apart several runs of the same simulation. If the given recorder is nil, so is the one returned.
+func Prefix(recorder Recorder, prefix string) Recorder {
+	if recorder == nil {
+		return nil
+	}
+
+	return prefixRecorder{recorder: recorder, prefix: prefix}
+}
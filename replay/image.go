@@ -0,0 +1,320 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ollien/advent-of-code-2018/grid"
+)
+
+// defaultDelay is how long each frame of a GIF is shown for, if not given
+const defaultDelay = 100 * time.Millisecond
+
+// ErrTooManyColours is returned when a palette has more colours than fit in a GIF
+var ErrTooManyColours = errors.New("palette has more than 255 colours")
+
+// ErrMalformedPalette is returned when parsing a palette that isn't a list of char=#rrggbb pairs
+var ErrMalformedPalette = errors.New("palette entries must be written as char=#rrggbb")
+
+// ImageOptions control how frames are drawn as images, where each cell is drawn as a square of a single colour
+type ImageOptions struct {
+	// Palette gives the colour of each kind of cell
+	Palette Palette
+	// Background is the colour of any cells without a colour in the palette
+	Background color.RGBA
+	// CellSize is the width and height of each cell, in pixels. Anything less than one is taken as one.
+	CellSize int
+	// Delay is how long each frame of a GIF is shown for, or 100ms if not set
+	Delay time.Duration
+	// Every is how many ticks pass between each frame of a GIF, so that long simulations can be sped up. The last tick
+	// is always included. Anything less than one is taken as one.
+	Every int
+}
+
+func (options ImageOptions) cellSize() int {
+	return max(options.CellSize, 1)
+}
+
+// colour gets the colour a character is drawn in
+func (options ImageOptions) colour(char rune) color.RGBA {
+	if colour, ok := options.Palette[char]; ok {
+		return colour
+	}
+
+	return options.Background
+}
+
+// ParsePalette parses a palette written as a comma separated list of char=#rrggbb pairs, such as "E=#00ff00,G=#ff0000"
+func ParsePalette(rawPalette string) (Palette, error) {
+	palette := Palette{}
+	if rawPalette == "" {
+		return palette, nil
+	}
+
+	for _, entry := range strings.Split(rawPalette, ",") {
+		rawChar, rawColour, ok := strings.Cut(entry, "=")
+		char := []rune(rawChar)
+		if !ok || len(char) != 1 {
+			return nil, fmt.Errorf("%q: %w", entry, ErrMalformedPalette)
+		}
+
+		colour, err := ParseColour(rawColour)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+
+		palette[char[0]] = colour
+	}
+
+	return palette, nil
+}
+
+// ParseColour parses a colour written as #rrggbb
+func ParseColour(rawColour string) (color.RGBA, error) {
+	hex, ok := strings.CutPrefix(rawColour, "#")
+	if !ok || len(hex) != 6 {
+		return color.RGBA{}, ErrMalformedPalette
+	}
+
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrMalformedPalette
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
+// WriteGIF draws the tape as an animated GIF, the size of its first frame. Each frame after the first only holds the
+// part of the image that changed, with everything in it that didn't change left transparent, so that simulations on
+// large boards that change a little at a time stay small.
+func WriteGIF(w io.Writer, tape *Tape, options ImageOptions) error {
+	if tape.Len() == 0 {
+		return ErrEmptyTape
+	}
+
+	colours, err := newGIFColours(options)
+	if err != nil {
+		return err
+	}
+
+	delay := options.Delay
+	if delay == 0 {
+		delay = defaultDelay
+	}
+
+	canvas := tape.Frame(0).Cells.Bounds()
+	animation := &gif.GIF{}
+	// Frames are drawn with one pixel for each cell, and only scaled up once it is known what changed
+	var previous *image.Paletted
+	var previousCells *grid.Dense[rune]
+	for _, tick := range gifTicks(tape.Len(), options.Every) {
+		frame := tape.Frame(tick)
+		var current *image.Paletted
+		var changed image.Rectangle
+		if previous == nil || previousCells.Bounds() != frame.Cells.Bounds() {
+			current = colours.draw(frame, canvas)
+			changed = current.Rect
+		} else {
+			current, changed = colours.redraw(previous, previousCells, frame, canvas)
+		}
+
+		frameDelay := int(delay / (10 * time.Millisecond))
+		if changed.Empty() {
+			// Nothing changed, so show the last frame for longer instead
+			animation.Delay[len(animation.Delay)-1] += frameDelay
+			continue
+		}
+
+		animation.Image = append(animation.Image, colours.scale(current, previous, changed, options.cellSize()))
+		animation.Delay = append(animation.Delay, frameDelay)
+		animation.Disposal = append(animation.Disposal, gif.DisposalNone)
+		previous = current
+		previousCells = frame.Cells
+	}
+
+	return gif.EncodeAll(w, animation)
+}
+
+// gifTicks gets the ticks that are drawn in a GIF, taking every nth one and always finishing on the last
+func gifTicks(numTicks int, every int) []int {
+	every = max(every, 1)
+	ticks := []int{}
+	for tick := 0; tick < numTicks; tick += every {
+		ticks = append(ticks, tick)
+	}
+
+	if ticks[len(ticks)-1] != numTicks-1 {
+		ticks = append(ticks, numTicks-1)
+	}
+
+	return ticks
+}
+
+// gifColours holds the colours a GIF is drawn with, starting with the background and ending with a transparent
+// colour, along with where each character's colour is in them
+type gifColours struct {
+	palette     color.Palette
+	transparent uint8
+	// ascii holds the index of every ASCII character, which is what almost every cell is drawn with, so that they don't
+	// need looking up in indices
+	ascii   [utf8.RuneSelf]uint8
+	indices map[rune]uint8
+}
+
+func newGIFColours(options ImageOptions) (gifColours, error) {
+	colours := gifColours{palette: color.Palette{options.Background}, indices: map[rune]uint8{}}
+	// Sort the characters, so that the same palette always gives the same image
+	chars := make([]rune, 0, len(options.Palette))
+	for char := range options.Palette {
+		chars = append(chars, char)
+	}
+	slices.Sort(chars)
+
+	for _, char := range chars {
+		index := slices.Index(colours.palette, color.Color(options.Palette[char]))
+		if index == -1 {
+			colours.palette = append(colours.palette, options.Palette[char])
+			index = len(colours.palette) - 1
+		}
+
+		// One colour must be left for the transparent one
+		if index >= 255 {
+			return gifColours{}, ErrTooManyColours
+		} else if char < utf8.RuneSelf {
+			colours.ascii[char] = uint8(index)
+		} else {
+			colours.indices[char] = uint8(index)
+		}
+	}
+
+	colours.transparent = uint8(len(colours.palette))
+	colours.palette = append(colours.palette, color.RGBA{})
+
+	return colours, nil
+}
+
+// index gets the index of the colour a character is drawn in
+func (colours gifColours) index(char rune) uint8 {
+	if char >= 0 && char < utf8.RuneSelf {
+		return colours.ascii[char]
+	}
+
+	// Characters without a colour get the background's, which is the zero index
+	return colours.indices[char]
+}
+
+// draw draws the cells of the frame that fall within the canvas as an image, with a pixel for each cell
+func (colours gifColours) draw(frame Frame, canvas grid.Rect) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, canvas.Cols(), canvas.Rows()), colours.palette)
+	for pos, char := range frame.Cells.All() {
+		if canvas.Contains(pos) {
+			img.Pix[img.PixOffset(pos.Col-canvas.Min.Col, pos.Row-canvas.Min.Row)] = colours.index(char)
+		}
+	}
+
+	return img
+}
+
+// redraw draws a frame by changing the image of the frame before it, which must cover the same rectangle, returning
+// the image along with the rectangle of the image that changed
+func (colours gifColours) redraw(
+	previous *image.Paletted,
+	previousCells *grid.Dense[rune],
+	frame Frame,
+	canvas grid.Rect,
+) (*image.Paletted, image.Rectangle) {
+	img := image.NewPaletted(previous.Rect, colours.palette)
+	copy(img.Pix, previous.Pix)
+	changed := image.Rectangle{}
+	for pos, char := range previousCells.Changes(frame.Cells) {
+		if !canvas.Contains(pos) {
+			continue
+		}
+
+		x, y := pos.Col-canvas.Min.Col, pos.Row-canvas.Min.Row
+		img.Pix[img.PixOffset(x, y)] = colours.index(char)
+		changed = changed.Union(image.Rect(x, y, x+1, y+1))
+	}
+
+	return img, changed
+}
+
+// scale scales up part of an image drawn with a pixel per cell, so that each cell is a square of the given size.
+// Cells that are the same as in the previous image, if there is one, are left transparent.
+func (colours gifColours) scale(img *image.Paletted, previous *image.Paletted, rect image.Rectangle, size int) *image.Paletted {
+	scaled := image.NewPaletted(image.Rect(rect.Min.X*size, rect.Min.Y*size, rect.Max.X*size, rect.Max.Y*size), colours.palette)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			index := img.ColorIndexAt(x, y)
+			if previous != nil && previous.ColorIndexAt(x, y) == index {
+				index = colours.transparent
+			}
+
+			for scaledY := y * size; scaledY < (y+1)*size; scaledY++ {
+				row := scaled.Pix[scaled.PixOffset(x*size, scaledY) : scaled.PixOffset(x*size, scaledY)+size]
+				for i := range row {
+					row[i] = index
+				}
+			}
+		}
+	}
+
+	return scaled
+}
+
+// WriteSVG draws a single frame as an SVG image, with the frame's caption as its title
+func WriteSVG(w io.Writer, frame Frame, options ImageOptions) error {
+	size := options.cellSize()
+	bounds := frame.Cells.Bounds()
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" shape-rendering="crispEdges">`+"\n", bounds.Cols()*size, bounds.Rows()*size)
+	fmt.Fprintf(&svg, "<title>%s</title>\n", html.EscapeString(frame.Caption))
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColour(options.Background))
+	for row := bounds.Min.Row; row < bounds.Max.Row; row++ {
+		// Runs of cells of the same colour are drawn as a single rectangle, to keep the image small
+		runStart := bounds.Min.Col
+		for col := bounds.Min.Col; col < bounds.Max.Col; col++ {
+			colour := options.colour(frame.Cells.Get(grid.Point{Row: row, Col: col}))
+			next := grid.Point{Row: row, Col: col + 1}
+			if col+1 < bounds.Max.Col && options.colour(frame.Cells.Get(next)) == colour {
+				continue
+			}
+
+			if colour != options.Background {
+				fmt.Fprintf(
+					&svg,
+					`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+					(runStart-bounds.Min.Col)*size,
+					(row-bounds.Min.Row)*size,
+					(col-runStart+1)*size,
+					size,
+					hexColour(colour),
+				)
+			}
+			runStart = col + 1
+		}
+	}
+	svg.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, svg.String())
+
+	return err
+}
+
+func hexColour(colour color.RGBA) string {
+	if colour.A == 0 {
+		return "none"
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", colour.R, colour.G, colour.B)
+}
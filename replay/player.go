@@ -0,0 +1,234 @@
+package replay
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ollien/advent-of-code-2018/grid"
+)
+
+const (
+	clearScreen  = "\x1b[H\x1b[2J"
+	resetColour  = "\x1b[0m"
+	colourFormat = "\x1b[38;2;%d;%d;%dm"
+	minFPS       = 0.25
+	maxFPS       = 1000
+	playerHelp   = "p play/pause, n/b step forward/back, t N seek to tick N, +/- faster/slower, v ROW COL move the view, q quit"
+)
+
+// ErrEmptyTape is returned when playing a tape that has no frames on it
+var ErrEmptyTape = errors.New("tape has no frames")
+
+// Player plays a tape back on a terminal, taking commands a line at a time
+type Player struct {
+	// Rows and Cols are the size of the view of the board. Boards larger than this are cropped to the view, which can be
+	// moved around with the v command.
+	Rows, Cols int
+	// FPS is how many ticks are played each second
+	FPS float64
+
+	tape    *Tape
+	palette Palette
+	out     io.Writer
+	tick    int
+	playing bool
+	// view is the top left of the view, relative to the top left of the board
+	view grid.Point
+	// status is the outcome of the last command, if it needs showing
+	status string
+}
+
+// NewPlayer makes a player for the tape, which draws to out
+func NewPlayer(tape *Tape, palette Palette, out io.Writer) *Player {
+	return &Player{
+		Rows:    50,
+		Cols:    160,
+		FPS:     10,
+		tape:    tape,
+		palette: palette,
+		out:     out,
+		playing: true,
+	}
+}
+
+// Play plays the tape from the start, taking commands from the given reader until it is told to quit. If the commands
+// run out, the rest of the tape is played through before returning.
+func (player *Player) Play(commands io.Reader) error {
+	if player.tape.Len() == 0 {
+		return ErrEmptyTape
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(commands)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(player.interval())
+	defer ticker.Stop()
+	for {
+		if err := player.draw(); err != nil {
+			return err
+		}
+
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				// Stop listening for commands, as there won't be any more
+				lines = nil
+				if !player.playing {
+					return nil
+				}
+			} else if quit := player.handle(line); quit {
+				return nil
+			}
+
+			ticker.Reset(player.interval())
+		case <-ticker.C:
+			if !player.playing {
+				continue
+			}
+
+			player.seek(player.tick + 1)
+			if player.tick == player.tape.Len()-1 {
+				player.playing = false
+				if lines == nil {
+					return player.draw()
+				}
+			}
+		}
+	}
+}
+
+// interval is the time between each tick
+func (player *Player) interval() time.Duration {
+	return time.Duration(float64(time.Second) / player.FPS)
+}
+
+// seek moves to a tick, keeping it on the tape
+func (player *Player) seek(tick int) {
+	player.tick = max(0, min(tick, player.tape.Len()-1))
+}
+
+// handle carries out a command, returning true if the player should quit
+func (player *Player) handle(command string) bool {
+	player.status = ""
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return false
+	}
+
+	args, err := parseArgs(fields[1:])
+	if err != nil {
+		player.status = err.Error()
+		return false
+	}
+
+	switch {
+	case fields[0] == "q":
+		return true
+	case fields[0] == "p":
+		player.playing = !player.playing
+		// Playing from the end starts again from the beginning
+		if player.playing && player.tick == player.tape.Len()-1 {
+			player.tick = 0
+		}
+	case fields[0] == "n" || fields[0] == "b":
+		player.playing = false
+		if fields[0] == "n" {
+			player.seek(player.tick + 1)
+		} else {
+			player.seek(player.tick - 1)
+		}
+	case fields[0] == "t" && len(args) == 1:
+		player.seek(args[0])
+	case fields[0] == "+":
+		player.FPS = min(player.FPS*2, maxFPS)
+	case fields[0] == "-":
+		player.FPS = max(player.FPS/2, minFPS)
+	case fields[0] == "v" && len(args) == 2:
+		player.view = grid.Point{Row: max(args[0], 0), Col: max(args[1], 0)}
+	default:
+		player.status = fmt.Sprintf("unknown command %q", command)
+	}
+
+	return false
+}
+
+func parseArgs(rawArgs []string) ([]int, error) {
+	args := make([]int, len(rawArgs))
+	for i, rawArg := range rawArgs {
+		arg, err := strconv.Atoi(rawArg)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", rawArg)
+		}
+
+		args[i] = arg
+	}
+
+	return args, nil
+}
+
+// draw clears the terminal and draws the current tick
+func (player *Player) draw() error {
+	state := "paused"
+	if player.playing {
+		state = "playing"
+	}
+
+	var screen strings.Builder
+	screen.WriteString(clearScreen)
+	frame := player.tape.Frame(player.tick)
+	fmt.Fprintf(&screen, "tick %d/%d  %s  [%s at %g ticks/s]\n", player.tick, player.tape.Len()-1, frame.Caption, state, player.FPS)
+	screen.WriteString(player.render(frame))
+	fmt.Fprintf(&screen, "%s\n%s\n", player.status, playerHelp)
+
+	_, err := io.WriteString(player.out, screen.String())
+
+	return err
+}
+
+// render draws the part of the frame that is in view, colouring each cell by the palette
+func (player *Player) render(frame Frame) string {
+	bounds := frame.Cells.Bounds()
+	top := bounds.Min.Add(player.view)
+	var rendered strings.Builder
+	for row := top.Row; row < min(top.Row+player.Rows, bounds.Max.Row); row++ {
+		var currentColour *color.RGBA
+		for col := top.Col; col < min(top.Col+player.Cols, bounds.Max.Col); col++ {
+			char := frame.Cells.Get(grid.Point{Row: row, Col: col})
+			colour, hasColour := player.palette[char]
+			if hasColour && (currentColour == nil || *currentColour != colour) {
+				fmt.Fprintf(&rendered, colourFormat, colour.R, colour.G, colour.B)
+				currentColour = &colour
+			} else if !hasColour && currentColour != nil {
+				rendered.WriteString(resetColour)
+				currentColour = nil
+			}
+
+			rendered.WriteRune(char)
+		}
+
+		if currentColour != nil {
+			rendered.WriteString(resetColour)
+		}
+		rendered.WriteRune('\n')
+	}
+
+	return rendered.String()
+}
@@ -0,0 +1,52 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoSimulation is returned when looking up a day that has no simulation to record
+var ErrNoSimulation = errors.New("no simulation")
+
+// Simulation records the frames of a day's simulation. Each day exposes a typed version of Run, which is wrapped here
+// so that every simulation can be looked up and recorded the same way.
+type Simulation struct {
+	// Palette is the colour to show each kind of cell in
+	Palette Palette
+	// Run runs the simulation a part is solved with, on input parsed by the day's puzzle, recording every tick
+	Run func(input interface{}, part int, recorder Recorder) error
+}
+
+// registry maps each day to its simulation
+var registry = map[int]Simulation{}
+
+// Register adds the simulation for a day. It is meant to be called from the init function of each day's package.
+func Register(day int, simulation Simulation) {
+	if _, exists := registry[day]; exists {
+		panic(fmt.Sprintf("day %d registered twice", day))
+	}
+
+	registry[day] = simulation
+}
+
+// Lookup gets the simulation for a day
+func Lookup(day int) (Simulation, error) {
+	simulation, ok := registry[day]
+	if !ok {
+		return Simulation{}, fmt.Errorf("day %d: %w", day, ErrNoSimulation)
+	}
+
+	return simulation, nil
+}
+
+// Days gets every day that has a registered simulation, in order
+func Days() []int {
+	days := make([]int, 0, len(registry))
+	for day := range registry {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	return days
+}
@@ -0,0 +1,183 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"strings"
+	"testing"
+
+	"github.com/ollien/advent-of-code-2018/grid"
+)
+
+// countingTape records a dot moving along a row, one column each tick
+func countingTape(numTicks int) *Tape {
+	tape := NewTape()
+	for tick := 0; tick < numTicks; tick++ {
+		cells := grid.NewDense[rune](grid.RectOf(2, numTicks))
+		for pos := range cells.Bounds().Points() {
+			cells.Set(pos, '.')
+		}
+		cells.Set(grid.Point{Row: 1, Col: tick}, '#')
+
+		tape.Record(Frame{Caption: fmt.Sprintf("tick %d", tick), Cells: cells})
+	}
+
+	return tape
+}
+
+func TestTapeFrames(t *testing.T) {
+	// Go past a keyframe, so that frames are built up from both whole frames and changes
+	numTicks := keyframeInterval + 10
+	tape := countingTape(numTicks)
+	if tape.Len() != numTicks {
+		t.Fatalf("got %d frames, want %d", tape.Len(), numTicks)
+	}
+
+	for _, tick := range []int{0, 1, keyframeInterval - 1, keyframeInterval, numTicks - 1} {
+		frame := tape.Frame(tick)
+		if want := fmt.Sprintf("tick %d", tick); frame.Caption != want {
+			t.Errorf("got caption %q for tick %d, want %q", frame.Caption, tick, want)
+		}
+
+		want := strings.Repeat(".", numTicks) + "\n" + strings.Repeat(".", tick) + "#" + strings.Repeat(".", numTicks-tick-1)
+		if got := frame.Cells.Render(func(char rune) rune { return char }); got != want {
+			t.Errorf("tick %d rendered as\n%s\nwant\n%s", tick, got, want)
+		}
+	}
+}
+
+func TestPlayerCommands(t *testing.T) {
+	var out bytes.Buffer
+	player := NewPlayer(countingTape(5), nil, &out)
+	// Keep the player from moving on by itself
+	player.FPS = minFPS
+	if err := player.Play(strings.NewReader("p\nt 3\nb\n+\nq\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	screens := strings.Split(out.String(), clearScreen)
+	last := screens[len(screens)-1]
+	if !strings.HasPrefix(last, "tick 2/4") {
+		t.Errorf("last screen should be tick 2, got\n%s", last)
+	} else if !strings.Contains(last, "..#..") {
+		t.Errorf("last screen should show the dot in the middle, got\n%s", last)
+	} else if !strings.Contains(last, "paused at 0.5 ticks/s") {
+		t.Errorf("last screen should be paused at double speed, got\n%s", last)
+	}
+}
+
+func TestPlayerPlaysToEnd(t *testing.T) {
+	var out bytes.Buffer
+	player := NewPlayer(countingTape(3), nil, &out)
+	player.FPS = maxFPS
+	if err := player.Play(strings.NewReader("")); err != nil {
+		t.Fatal(err)
+	}
+
+	screens := strings.Split(out.String(), clearScreen)
+	if last := screens[len(screens)-1]; !strings.HasPrefix(last, "tick 2/2") {
+		t.Errorf("last screen should be the end of the tape, got\n%s", last)
+	}
+}
+
+func TestRenderColours(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	player := NewPlayer(countingTape(3), Palette{'#': red}, nil)
+	player.Cols = 2
+	got := player.render(player.tape.Frame(1))
+	want := "..\n.\x1b[38;2;255;0;0m#\x1b[0m\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	var out bytes.Buffer
+	options := ImageOptions{
+		Palette:    Palette{'#': {R: 255, A: 255}},
+		Background: color.RGBA{A: 255},
+		CellSize:   3,
+	}
+	if err := WriteGIF(&out, countingTape(4), options); err != nil {
+		t.Fatal(err)
+	}
+
+	animation, err := gif.DecodeAll(&out)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(animation.Image) != 4 {
+		t.Fatalf("got %d frames, want 4", len(animation.Image))
+	} else if animation.Config.Width != 12 || animation.Config.Height != 6 {
+		t.Errorf("got a %dx%d image, want 12x6", animation.Config.Width, animation.Config.Height)
+	}
+
+	// Only the cells the dot moves between change, which are the second row of the first two columns
+	if got, want := animation.Image[1].Rect, image.Rect(0, 3, 6, 6); got != want {
+		t.Errorf("got %v for the second frame, want %v", got, want)
+	}
+}
+
+func TestGIFTicks(t *testing.T) {
+	tests := []struct {
+		numTicks int
+		every    int
+		want     []int
+	}{
+		{3, 0, []int{0, 1, 2}},
+		{10, 4, []int{0, 4, 8, 9}},
+		{9, 4, []int{0, 4, 8}},
+	}
+
+	for _, tt := range tests {
+		got := gifTicks(tt.numTicks, tt.every)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("gifTicks(%d, %d) = %v, want %v", tt.numTicks, tt.every, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	cells := grid.NewDense[rune](grid.RectOf(1, 4))
+	for pos, char := range map[grid.Point]rune{{Col: 0}: '#', {Col: 1}: '#', {Col: 2}: '.', {Col: 3}: '#'} {
+		cells.Set(pos, char)
+	}
+
+	var out bytes.Buffer
+	options := ImageOptions{Palette: Palette{'#': {R: 255, A: 255}}, CellSize: 2}
+	if err := WriteSVG(&out, Frame{Caption: "a < b", Cells: cells}, options); err != nil {
+		t.Fatal(err)
+	}
+
+	svg := out.String()
+	for _, want := range []string{
+		`width="8" height="2"`,
+		"<title>a &lt; b</title>",
+		// The first two cells are drawn as one rectangle
+		`<rect x="0" y="0" width="4" height="2" fill="#ff0000"/>`,
+		`<rect x="6" y="0" width="2" height="2" fill="#ff0000"/>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg does not contain %s:\n%s", want, svg)
+		}
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	palette, err := ParsePalette("E=#00ff00,G=#FF0000")
+	if err != nil {
+		t.Fatal(err)
+	} else if want := (color.RGBA{G: 255, A: 255}); palette['E'] != want {
+		t.Errorf("got %v for E, want %v", palette['E'], want)
+	} else if want := (color.RGBA{R: 255, A: 255}); palette['G'] != want {
+		t.Errorf("got %v for G, want %v", palette['G'], want)
+	}
+
+	for _, malformed := range []string{"E", "EE=#00ff00", "E=00ff00", "E=#00ff0", "E=#00gg00"} {
+		if _, err := ParsePalette(malformed); err == nil {
+			t.Errorf("expected an error parsing %q", malformed)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package replay
+
+import "github.com/ollien/advent-of-code-2018/grid"
+
+// keyframeInterval is how often a whole frame is kept, rather than just the cells that changed since the last one
+const keyframeInterval = 64
+
+// change is a cell that changed between two frames
+type change struct {
+	pos  grid.Point
+	char rune
+}
+
+// recordedFrame is a frame on the tape. Only every keyframeInterval-th frame, or one that doesn't cover the same
+// rectangle as the frame before it, holds its cells; the rest hold the cells that changed since the previous frame.
+type recordedFrame struct {
+	caption string
+	cells   *grid.Dense[rune]
+	changes []change
+}
+
+// Tape is a Recorder that keeps every frame, so that they can be played back.
+// Most frames are stored as the cells that changed since the frame before, as the simulations' boards are large and
+// only change a little each tick.
+type Tape struct {
+	frames []recordedFrame
+	// last is the most recently recorded frame
+	last *grid.Dense[rune]
+}
+
+// NewTape makes an empty tape
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Record adds a frame to the end of the tape
+func (tape *Tape) Record(frame Frame) {
+	recorded := recordedFrame{caption: frame.Caption}
+	if len(tape.frames)%keyframeInterval == 0 || tape.last.Bounds() != frame.Cells.Bounds() {
+		recorded.cells = frame.Cells
+	} else {
+		recorded.changes = []change{}
+		for pos, char := range tape.last.Changes(frame.Cells) {
+			recorded.changes = append(recorded.changes, change{pos: pos, char: char})
+		}
+	}
+
+	tape.frames = append(tape.frames, recorded)
+	tape.last = frame.Cells
+}
+
+// Len gets the number of frames on the tape
+func (tape *Tape) Len() int {
+	return len(tape.frames)
+}
+
+// Frame gets the frame for a tick, which must be on the tape
+func (tape *Tape) Frame(tick int) Frame {
+	// Work forwards from the last frame that was kept whole
+	start := tick
+	for tape.frames[start].cells == nil {
+		start--
+	}
+
+	cells := tape.frames[start].cells.Clone()
+	for _, recorded := range tape.frames[start+1 : tick+1] {
+		for _, c := range recorded.changes {
+			cells.Set(c.pos, c.char)
+		}
+	}
+
+	return Frame{Caption: tape.frames[tick].caption, Cells: cells}
+}
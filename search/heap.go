@@ -0,0 +1,65 @@
+package search
+
+import "container/heap"
+
+// indexedHeap is a min-heap of nodes that knows where each node is, so that a node's priority can be lowered without
+// searching for it. It implements container/heap.Interface, which should not be called directly.
+type indexedHeap[N comparable] struct {
+	nodes      []N
+	priorities map[N]int
+	indices    map[N]int
+}
+
+func newIndexedHeap[N comparable]() *indexedHeap[N] {
+	return &indexedHeap[N]{
+		priorities: map[N]int{},
+		indices:    map[N]int{},
+	}
+}
+
+func (h *indexedHeap[N]) Len() int {
+	return len(h.nodes)
+}
+
+func (h *indexedHeap[N]) Less(i, j int) bool {
+	return h.priorities[h.nodes[i]] < h.priorities[h.nodes[j]]
+}
+
+func (h *indexedHeap[N]) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.indices[h.nodes[i]] = i
+	h.indices[h.nodes[j]] = j
+}
+
+func (h *indexedHeap[N]) Push(item interface{}) {
+	node := item.(N)
+	h.indices[node] = len(h.nodes)
+	h.nodes = append(h.nodes, node)
+}
+
+func (h *indexedHeap[N]) Pop() interface{} {
+	node := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+	delete(h.indices, node)
+	delete(h.priorities, node)
+
+	return node
+}
+
+// pushOrDecrease adds a node with the given priority, or lowers its priority if it is already in the heap.
+// A node already in the heap with a lower priority is left as it is.
+func (h *indexedHeap[N]) pushOrDecrease(node N, priority int) {
+	index, inHeap := h.indices[node]
+	if !inHeap {
+		h.priorities[node] = priority
+		heap.Push(h, node)
+	} else if priority < h.priorities[node] {
+		h.priorities[node] = priority
+		heap.Fix(h, index)
+	}
+}
+
+// pop removes the node with the lowest priority
+func (h *indexedHeap[N]) pop() N {
+	return heap.Pop(h).(N)
+}
@@ -0,0 +1,165 @@
+// Package search finds shortest paths through graphs that are described by a function giving each node's neighbors,
+// so that graphs too large to build up front, or with no end, can be searched
+package search
+
+// Edge is a move to a neighboring node, along with what it costs to make
+type Edge[N comparable] struct {
+	To   N
+	Cost int
+}
+
+// Result is the outcome of a search, holding the distance to every node the search reached and how it got there
+type Result[N comparable] struct {
+	start     N
+	distances map[N]int
+	previous  map[N]N
+	goal      N
+	foundGoal bool
+}
+
+func newResult[N comparable](start N) *Result[N] {
+	return &Result[N]{
+		start:     start,
+		distances: map[N]int{start: 0},
+		previous:  map[N]N{},
+	}
+}
+
+// Distance gets the distance from the start to a node, and whether the search reached it
+func (result *Result[N]) Distance(node N) (int, bool) {
+	distance, ok := result.distances[node]
+
+	return distance, ok
+}
+
+// Distances gets the distance to every node the search reached, including the start. It must not be modified.
+func (result *Result[N]) Distances() map[N]int {
+	return result.distances
+}
+
+// Goal gets the goal the search stopped at, and whether it found one
+func (result *Result[N]) Goal() (N, bool) {
+	return result.goal, result.foundGoal
+}
+
+// Path gets the nodes along the shortest path from the start to a node, including both ends,
+// or nil if the search didn't reach it
+func (result *Result[N]) Path(to N) []N {
+	if _, ok := result.distances[to]; !ok {
+		return nil
+	}
+
+	path := []N{to}
+	for node := to; node != result.start; {
+		node = result.previous[node]
+		path = append(path, node)
+	}
+
+	// The path was built up backwards from the end
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+func (result *Result[N]) stopAt(goal N) {
+	result.goal = goal
+	result.foundGoal = true
+}
+
+// BFS searches breadth first from start, where every move to a neighbor costs 1. Neighbors are visited in the order
+// they are given. If isGoal is not nil, the search stops once it reaches a node for which isGoal returns true;
+// otherwise every node that can be reached is searched.
+func BFS[N comparable](start N, neighbors func(N) []N, isGoal func(N) bool) *Result[N] {
+	result := newResult(start)
+	toVisit := []N{start}
+	for len(toVisit) > 0 {
+		visiting := toVisit[0]
+		toVisit = toVisit[1:]
+		if isGoal != nil && isGoal(visiting) {
+			result.stopAt(visiting)
+			break
+		}
+
+		for _, neighbor := range neighbors(visiting) {
+			if _, visited := result.distances[neighbor]; visited {
+				continue
+			}
+
+			result.distances[neighbor] = result.distances[visiting] + 1
+			result.previous[neighbor] = visiting
+			toVisit = append(toVisit, neighbor)
+		}
+	}
+
+	return result
+}
+
+// Dijkstra searches from start for the cheapest path to every node, stopping early once it finds the cheapest path to
+// a node for which isGoal returns true, if isGoal is not nil. Edge costs must not be negative.
+func Dijkstra[N comparable](start N, neighbors func(N) []Edge[N], isGoal func(N) bool) *Result[N] {
+	return AStar(start, neighbors, isGoal, nil)
+}
+
+// AStar is like Dijkstra, but the heuristic estimates the remaining cost from a node to the nearest goal, so that
+// nodes that lead towards a goal are searched first. The heuristic must never overestimate, or the path found may not
+// be the cheapest. A nil heuristic makes this the same as Dijkstra.
+func AStar[N comparable](start N, neighbors func(N) []Edge[N], isGoal func(N) bool, heuristic func(N) int) *Result[N] {
+	estimate := func(node N) int {
+		if heuristic == nil {
+			return 0
+		}
+
+		return heuristic(node)
+	}
+
+	result := newResult(start)
+	toVisit := newIndexedHeap[N]()
+	toVisit.pushOrDecrease(start, estimate(start))
+	for toVisit.Len() > 0 {
+		visiting := toVisit.pop()
+		if isGoal != nil && isGoal(visiting) {
+			result.stopAt(visiting)
+			break
+		}
+
+		for _, edge := range neighbors(visiting) {
+			distance := result.distances[visiting] + edge.Cost
+			if knownDistance, ok := result.distances[edge.To]; ok && knownDistance <= distance {
+				continue
+			}
+
+			result.distances[edge.To] = distance
+			result.previous[edge.To] = visiting
+			toVisit.pushOrDecrease(edge.To, distance+estimate(edge.To))
+		}
+	}
+
+	return result
+}
+
+// Reachability records which nodes can be reached from which others
+type Reachability[N comparable] struct {
+	reachable map[N]map[N]bool
+}
+
+// AllPairsReachability works out which of the given nodes can reach which others, by searching from each of them.
+// Every node can reach itself.
+func AllPairsReachability[N comparable](nodes []N, neighbors func(N) []N) Reachability[N] {
+	reachability := Reachability[N]{reachable: make(map[N]map[N]bool, len(nodes))}
+	for _, from := range nodes {
+		reached := BFS(from, neighbors, nil)
+		reachability.reachable[from] = make(map[N]bool, len(reached.distances))
+		for to := range reached.distances {
+			reachability.reachable[from][to] = true
+		}
+	}
+
+	return reachability
+}
+
+// Reachable reports whether there is a path from one node to another
+func (reachability Reachability[N]) Reachable(from, to N) bool {
+	return reachability.reachable[from][to]
+}
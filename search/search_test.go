@@ -0,0 +1,132 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+// weighted is a small graph where the direct edge from a to d costs more than going around through b and c
+var weighted = map[string][]Edge[string]{
+	"a": {{To: "b", Cost: 1}, {To: "d", Cost: 10}},
+	"b": {{To: "c", Cost: 2}},
+	"c": {{To: "d", Cost: 3}},
+	"d": {{To: "e", Cost: 1}},
+	"x": {{To: "a", Cost: 1}},
+}
+
+func weightedNeighbors(node string) []Edge[string] {
+	return weighted[node]
+}
+
+func unweightedNeighbors(node string) []string {
+	var neighbors []string
+	for _, edge := range weighted[node] {
+		neighbors = append(neighbors, edge.To)
+	}
+
+	return neighbors
+}
+
+func TestBFS(t *testing.T) {
+	result := BFS("a", unweightedNeighbors, nil)
+	if distance, _ := result.Distance("e"); distance != 2 {
+		t.Errorf("got a distance of %d to e, want 2", distance)
+	}
+	if path, want := result.Path("e"), []string{"a", "d", "e"}; !slices.Equal(path, want) {
+		t.Errorf("got path %v, want %v", path, want)
+	}
+	if _, ok := result.Distance("x"); ok {
+		t.Error("x should not be reachable from a")
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	result := Dijkstra("a", weightedNeighbors, nil)
+	if distance, _ := result.Distance("e"); distance != 7 {
+		t.Errorf("got a distance of %d to e, want 7", distance)
+	}
+	if path, want := result.Path("e"), []string{"a", "b", "c", "d", "e"}; !slices.Equal(path, want) {
+		t.Errorf("got path %v, want %v", path, want)
+	}
+	if path := result.Path("x"); path != nil {
+		t.Errorf("got path %v to x, which can't be reached", path)
+	}
+}
+
+func TestAStarOnGrid(t *testing.T) {
+	type point struct{ x, y int }
+	// An open grid with a wall at x = 5 that has a gap at y = 8
+	neighbors := func(p point) []Edge[point] {
+		var edges []Edge[point]
+		for _, next := range []point{{p.x + 1, p.y}, {p.x - 1, p.y}, {p.x, p.y + 1}, {p.x, p.y - 1}} {
+			if next.x < 0 || next.y < 0 || next.x > 10 || next.y > 10 || (next.x == 5 && next.y != 8) {
+				continue
+			}
+			edges = append(edges, Edge[point]{To: next, Cost: 1})
+		}
+
+		return edges
+	}
+
+	target := point{10, 0}
+	isGoal := func(p point) bool { return p == target }
+	heuristic := func(p point) int { return (target.x - p.x) + max(p.y-target.y, target.y-p.y) }
+
+	aStar := AStar(point{0, 0}, neighbors, isGoal, heuristic)
+	dijkstra := Dijkstra(point{0, 0}, neighbors, isGoal)
+	goal, found := aStar.Goal()
+	if !found || goal != target {
+		t.Fatalf("got goal %v (found: %t), want %v", goal, found, target)
+	}
+
+	aStarDistance, _ := aStar.Distance(target)
+	dijkstraDistance, _ := dijkstra.Distance(target)
+	if aStarDistance != 26 || dijkstraDistance != 26 {
+		t.Errorf("got distances of %d with A* and %d with Dijkstra, want 26", aStarDistance, dijkstraDistance)
+	}
+	if len(aStar.Path(target)) != 27 {
+		t.Errorf("got a path of %d nodes, want 27", len(aStar.Path(target)))
+	}
+	if len(aStar.Distances()) >= len(dijkstra.Distances()) {
+		t.Errorf("A* reached %d nodes, which should be fewer than Dijkstra's %d", len(aStar.Distances()), len(dijkstra.Distances()))
+	}
+}
+
+func TestAllPairsReachability(t *testing.T) {
+	reachability := AllPairsReachability([]string{"a", "c", "x"}, unweightedNeighbors)
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"a", "e", true},
+		{"c", "b", false},
+		{"x", "e", true},
+		{"a", "x", false},
+		{"c", "c", true},
+	}
+
+	for _, tt := range tests {
+		if got := reachability.Reachable(tt.from, tt.to); got != tt.want {
+			t.Errorf("got %t for %s to %s, want %t", got, tt.from, tt.to, tt.want)
+		}
+	}
+}
+
+func TestIndexedHeapDecreaseKey(t *testing.T) {
+	h := newIndexedHeap[string]()
+	h.pushOrDecrease("a", 5)
+	h.pushOrDecrease("b", 3)
+	h.pushOrDecrease("c", 4)
+	h.pushOrDecrease("a", 1)
+	// Raising a priority is ignored
+	h.pushOrDecrease("b", 10)
+
+	var order []string
+	for h.Len() > 0 {
+		order = append(order, h.pop())
+	}
+
+	if want := []string{"a", "b", "c"}; !slices.Equal(order, want) {
+		t.Errorf("got %v, want %v", order, want)
+	}
+}